@@ -0,0 +1,143 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package echotron
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Album represents a group of messages sent together, which share the same MediaGroupID.
+type Album struct {
+	MediaGroupID string
+	// Messages contains the messages of the album sorted by message ID.
+	Messages []*Message
+	// Updates contains the updates the messages have been received with, in the same order.
+	Updates []*Update
+}
+
+// Caption returns the first non empty caption of the messages in the album.
+func (a Album) Caption() string {
+	for _, m := range a.Messages {
+		if m.Caption != "" {
+			return m.Caption
+		}
+	}
+	return ""
+}
+
+type albumKey struct {
+	groupID    string
+	sessionKey int64
+}
+
+type pendingAlbum struct {
+	timer    *time.Timer
+	updates  []*Update
+	releases []func()
+}
+
+// MediaGroups returns a Middleware that buffers the messages sharing the same MediaGroupID
+// until no new message of the group is received for the given window of time.
+// The album is then passed to next as a single update, which is the one of the first message
+// of the album and carries all of them in its Album method.
+// The buffered updates are kept in flight, with their context and span, until the album is handled.
+// All the other updates are passed to next immediately.
+func MediaGroups(window time.Duration) Middleware {
+	var (
+		mu      sync.Mutex
+		pending = make(map[albumKey]*pendingAlbum)
+	)
+
+	return func(next HandlerFunc) HandlerFunc {
+		flush := func(key albumKey) {
+			mu.Lock()
+			p := pending[key]
+			delete(pending, key)
+			mu.Unlock()
+
+			// The timer might have been reset after firing, in that case the album has already been flushed.
+			if p == nil {
+				return
+			}
+
+			defer func() {
+				for _, release := range p.releases {
+					release()
+				}
+			}()
+			next(key.sessionKey, newAlbumUpdate(key.groupID, p.updates))
+		}
+
+		return func(sessionKey int64, update *Update) {
+			msg := albumMessage(update)
+			if msg == nil {
+				next(sessionKey, update)
+				return
+			}
+
+			key := albumKey{msg.MediaGroupID, sessionKey}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			p, ok := pending[key]
+			if !ok {
+				p = &pendingAlbum{timer: time.AfterFunc(window, func() { flush(key) })}
+				pending[key] = p
+			} else {
+				p.timer.Reset(window)
+			}
+			p.updates = append(p.updates, update)
+			p.releases = append(p.releases, holdUpdate(update))
+		}
+	}
+}
+
+// albumMessage returns the message of the update if it's part of a media group.
+func albumMessage(update *Update) *Message {
+	switch {
+	case update.Message != nil && update.Message.MediaGroupID != "":
+		return update.Message
+	case update.ChannelPost != nil && update.ChannelPost.MediaGroupID != "":
+		return update.ChannelPost
+	default:
+		return nil
+	}
+}
+
+func newAlbumUpdate(groupID string, updates []*Update) *Update {
+	sort.Slice(updates, func(i, j int) bool {
+		return albumMessage(updates[i]).ID < albumMessage(updates[j]).ID
+	})
+
+	album := &Album{
+		MediaGroupID: groupID,
+		Messages:     make([]*Message, len(updates)),
+		Updates:      updates,
+	}
+	for i, u := range updates {
+		album.Messages[i] = albumMessage(u)
+	}
+
+	first := *updates[0]
+	first.album = album
+	return &first
+}
@@ -0,0 +1,103 @@
+package echotron
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMediaGroups(t *testing.T) {
+	var (
+		wg     sync.WaitGroup
+		result = make(chan *Update, 4)
+		h      = MediaGroups(20 * time.Millisecond)(func(_ int64, u *Update) { result <- u })
+		album  = func(id int, caption string) *Update {
+			return &Update{
+				ID: id,
+				Message: &Message{
+					ID:           id,
+					Chat:         Chat{ID: 1},
+					MediaGroupID: "group",
+					Caption:      caption,
+					Photo:        []*PhotoSize{{FileID: caption}},
+				},
+			}
+		}
+	)
+
+	for _, u := range []*Update{album(3, ""), album(1, "first"), album(2, "")} {
+		wg.Add(1)
+		go func(u *Update) {
+			defer wg.Done()
+			h(1, u)
+		}(u)
+	}
+	h(1, &Update{ID: 4, Message: &Message{ID: 4, Text: "hello"}})
+	wg.Wait()
+
+	if u := <-result; u.ID != 4 || u.Album() != nil {
+		t.Fatalf("plain message should be passed immediately, got %+v", u)
+	}
+
+	select {
+	case u := <-result:
+		a := u.Album()
+		if a == nil || len(a.Messages) != 3 || u.ID != 1 {
+			t.Fatalf("unexpected album update %+v", u)
+		}
+		for i, m := range a.Messages {
+			if m.ID != i+1 {
+				t.Fatalf("album messages are not sorted: %d at index %d", m.ID, i)
+			}
+		}
+		if a.Caption() != "first" {
+			t.Fatalf("unexpected caption %q", a.Caption())
+		}
+
+	case <-time.After(time.Second):
+		t.Fatal("album has not been delivered")
+	}
+
+	if len(result) != 0 {
+		t.Fatal("album messages should be delivered once")
+	}
+}
+
+func TestMediaGroupsInFlight(t *testing.T) {
+	var (
+		tracer = &testTracer{}
+		d, ch  = newRecorderDispatcher()
+		album  = func(id int) *Update {
+			return &Update{ID: id, Message: &Message{ID: id, Chat: Chat{ID: 1}, MediaGroupID: "group"}}
+		}
+	)
+
+	d.SetTracer(tracer)
+	d.Use(MediaGroups(20 * time.Millisecond))
+	d.Dispatch(album(1))
+	d.Dispatch(album(2))
+
+	if n := d.inFlight.Load(); n != 2 {
+		t.Fatalf("expected the buffered updates to be in flight, got %d", n)
+	}
+
+	select {
+	case u := <-ch:
+		if _, ok := UpdateMetaFromContext(u.Context()); !ok || u.Album() == nil {
+			t.Fatalf("album delivered without the context of its update %+v", u)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("album has not been delivered")
+	}
+
+	for deadline := time.Now().Add(time.Second); d.inFlight.Load() != 0; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("the album updates are still in flight")
+		}
+	}
+	for _, s := range tracer.spans {
+		if !s.ended {
+			t.Fatalf("span %s has not been ended", s.name)
+		}
+	}
+}
@@ -18,30 +18,109 @@
 
 package echotron
 
+//go:generate go run ./internal/apigen -spec spec/botapi.json -dir .
+
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/url"
 	"strings"
 )
 
 // API is the object that contains all the functions that wrap those of the Telegram Bot API.
 type API struct {
-	token string
-	base  string
+	ctx          context.Context
+	logger       Logger
+	client       *http.Client
+	tracer       Tracer
+	token        string
+	base         string
+	fileBase     string
+	interceptors []Interceptor
+}
+
+// APIOptions contains the optional parameters used by the NewAPIOptions function.
+type APIOptions struct {
+	// BaseURL is the URL of the Bot API server, defaults to DefaultBaseURL.
+	// It allows to use a local Bot API server or a fake one such as the echotrontest server.
+	BaseURL string
+	// Client is the HTTP client used to send the requests, if nil the one set with SetDefaultClient is used.
+	Client *http.Client
+	// Logger is the Logger used by the API, if nil the one set with SetLogger is used.
+	Logger Logger
+	// Tracer is used to start a span for each call, if nil no spans are started.
+	Tracer Tracer
+	// Interceptors contains the hooks run around each call, in order.
+	Interceptors []Interceptor
+}
+
+// DefaultBaseURL is the URL of the Telegram Bot API server.
+const DefaultBaseURL = "https://api.telegram.org"
+
+// NewAPI is a wrapper function for NewAPIOptions.
+func NewAPI(token string) API {
+	return NewAPIOptions(token, nil)
 }
 
-// NewAPI returns a new API object.
-func NewAPI(token string) API {
-	return API{
-		token: token,
-		base:  fmt.Sprintf("https://api.telegram.org/bot%s/", token),
+// NewAPIOptions returns a new API object configured with the given options.
+func NewAPIOptions(token string, opts *APIOptions) API {
+	var baseURL = DefaultBaseURL
+
+	if opts != nil && opts.BaseURL != "" {
+		baseURL = strings.TrimSuffix(opts.BaseURL, "/")
+	}
+
+	a := API{
+		token:    token,
+		base:     fmt.Sprintf("%s/bot%s/", baseURL, token),
+		fileBase: fmt.Sprintf("%s/file/bot%s/", baseURL, token),
+	}
+
+	if opts != nil {
+		a.logger = opts.Logger
+		a.client = opts.Client
+		a.tracer = opts.Tracer
+		a.interceptors = opts.Interceptors
+	}
+	return a
+}
+
+// WithContext returns a copy of the API whose calls are bound to ctx:
+// the requests are cancelled when ctx is done and the UpdateMeta it carries,
+// such as the one of Update.Context, is passed to the interceptors, the logs and the spans.
+func (a API) WithContext(ctx context.Context) API {
+	a.ctx = ctx
+	return a
+}
+
+// context returns the context the calls of the API are bound to.
+func (a API) context() context.Context {
+	if a.ctx != nil {
+		return a.ctx
+	}
+	return context.Background()
+}
+
+func (a API) log() Logger {
+	if a.logger != nil {
+		return a.logger
+	}
+	return getLogger()
+}
+
+// logError logs the error returned by a call to the given method, if any.
+func (a API) logError(method string, err error) {
+	if err != nil {
+		keyvals := append([]any{"method", method, "error", err}, metaKeyvals(a.ctx)...)
+		a.log().Log(LogDebug, "API call failed", keyvals...)
 	}
 }
 
 // GetUpdates is used to receive incoming updates using long polling.
 func (a API) GetUpdates(opts *UpdateOptions) (res APIResponseUpdate, err error) {
-	return get[APIResponseUpdate](a.base, "getUpdates", urlValues(opts))
+	return get[APIResponseUpdate](a, "getUpdates", urlValues(opts))
 }
 
 // SetWebhook is used to specify a url and receive incoming updates via an outgoing webhook.
@@ -51,26 +130,28 @@ func (a API) SetWebhook(webhookURL string, dropPendingUpdates bool, opts *Webhoo
 		keyVal = map[string]string{"url": webhookURL}
 	)
 
-	url, err := url.JoinPath(a.base, "setWebhook")
-	if err != nil {
-		return res, err
-	}
-
 	vals.Set("drop_pending_updates", btoa(dropPendingUpdates))
 	addValues(vals, opts)
-	url = fmt.Sprintf("%s?%s", strings.TrimSuffix(url, "/"), vals.Encode())
 
-	cnt, err := sendPostForm(url, keyVal)
+	// The public key certificate, if any, must be uploaded.
+	if opts != nil && opts.Certificate.path != "" {
+		vals.Set("url", webhookURL)
+		return postFile[APIResponseBase](a, "setWebhook", "certificate", opts.Certificate, InputFile{}, vals)
+	}
+
+	addr, err := joinURL(a.base, "setWebhook", vals)
 	if err != nil {
-		return
+		return res, err
 	}
 
-	if err = json.Unmarshal(cnt, &res); err != nil {
-		return
+	params := url.Values{"url": {webhookURL}}
+	for k, v := range vals {
+		params[k] = v
 	}
 
-	err = check(res)
-	return
+	return request[APIResponseBase](a, "setWebhook", params, nil, func(a API) ([]byte, error) {
+		return a.sendPostForm(addr, keyVal)
+	})
 }
 
 // DeleteWebhook is used to remove webhook integration if you decide to switch back to GetUpdates.
@@ -78,17 +159,17 @@ func (a API) DeleteWebhook(dropPendingUpdates bool) (res APIResponseBase, err er
 	var vals = make(url.Values)
 	vals.Set("drop_pending_updates", btoa(dropPendingUpdates))
 
-	return get[APIResponseBase](a.base, "deleteWebhook", vals)
+	return get[APIResponseBase](a, "deleteWebhook", vals)
 }
 
 // GetWebhookInfo is used to get current webhook status.
 func (a API) GetWebhookInfo() (res APIResponseWebhook, err error) {
-	return get[APIResponseWebhook](a.base, "getWebhookInfo", nil)
+	return get[APIResponseWebhook](a, "getWebhookInfo", nil)
 }
 
 // GetMe is a simple method for testing your bot's auth token.
 func (a API) GetMe() (res APIResponseUser, err error) {
-	return get[APIResponseUser](a.base, "getMe", nil)
+	return get[APIResponseUser](a, "getMe", nil)
 }
 
 // LogOut is used to log out from the cloud Bot API server before launching the bot locally.
@@ -96,14 +177,14 @@ func (a API) GetMe() (res APIResponseUser, err error) {
 // After a successful call, you can immediately log in on a local server,
 // but will not be able to log in back to the cloud Bot API server for 10 minutes.
 func (a API) LogOut() (res APIResponseBool, err error) {
-	return get[APIResponseBool](a.base, "logOut", nil)
+	return get[APIResponseBool](a, "logOut", nil)
 }
 
 // Close is used to close the bot instance before moving it from one local server to another.
 // You need to delete the webhook before calling this method to ensure that the bot isn't launched again after server restart.
 // The method will return error 429 in the first 10 minutes after the bot is launched.
 func (a API) Close() (res APIResponseBool, err error) {
-	return get[APIResponseBool](a.base, "close", nil)
+	return get[APIResponseBool](a, "close", nil)
 }
 
 // SendMessage is used to send text messages.
@@ -112,7 +193,7 @@ func (a API) SendMessage(text string, chatID int64, opts *MessageOptions) (res A
 
 	vals.Set("text", text)
 	vals.Set("chat_id", itoa(chatID))
-	return get[APIResponseMessage](a.base, "sendMessage", addValues(vals, opts))
+	return get[APIResponseMessage](a, "sendMessage", addValues(vals, opts))
 }
 
 func (a API) SendMessageWithUserName(text string, userName string, opts *MessageOptions) (res APIResponseMessage, err error) {
@@ -120,7 +201,7 @@ func (a API) SendMessageWithUserName(text string, userName string, opts *Message
 
 	vals.Set("text", text)
 	vals.Set("chat_id", userName)
-	return get[APIResponseMessage](a.base, "sendMessage", addValues(vals, opts))
+	return get[APIResponseMessage](a, "sendMessage", addValues(vals, opts))
 }
 
 // ForwardMessage is used to forward messages of any kind.
@@ -131,7 +212,7 @@ func (a API) ForwardMessage(chatID, fromChatID int64, messageID int, opts *Forwa
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("from_chat_id", itoa(fromChatID))
 	vals.Set("message_id", itoa(int64(messageID)))
-	return get[APIResponseMessage](a.base, "forwardMessage", addValues(vals, opts))
+	return get[APIResponseMessage](a, "forwardMessage", addValues(vals, opts))
 }
 
 // CopyMessage is used to copy messages of any kind.
@@ -144,7 +225,7 @@ func (a API) CopyMessage(chatID, fromChatID int64, messageID int, opts *CopyOpti
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("from_chat_id", itoa(fromChatID))
 	vals.Set("message_id", itoa(int64(messageID)))
-	return get[APIResponseMessageID](a.base, "forwardMessage", addValues(vals, opts))
+	return get[APIResponseMessageID](a, "forwardMessage", addValues(vals, opts))
 }
 
 // SendPhoto is used to send photos.
@@ -152,7 +233,7 @@ func (a API) SendPhoto(file InputFile, chatID int64, opts *PhotoOptions) (res AP
 	var vals = make(url.Values)
 
 	vals.Set("chat_id", itoa(chatID))
-	return postFile[APIResponseMessage](a.base, "sendPhoto", "photo", file, InputFile{}, addValues(vals, opts))
+	return postFile[APIResponseMessage](a, "sendPhoto", "photo", file, InputFile{}, addValues(vals, opts))
 }
 
 // SendAudio is used to send audio files,
@@ -169,7 +250,7 @@ func (a API) SendAudio(file InputFile, chatID int64, opts *AudioOptions) (res AP
 	}
 
 	vals.Set("chat_id", itoa(chatID))
-	return postFile[APIResponseMessage](a.base, "sendAudio", "audio", file, thumbnail, addValues(vals, opts))
+	return postFile[APIResponseMessage](a, "sendAudio", "audio", file, thumbnail, addValues(vals, opts))
 }
 
 // SendDocument is used to send general files.
@@ -184,7 +265,7 @@ func (a API) SendDocument(file InputFile, chatID int64, opts *DocumentOptions) (
 	}
 
 	vals.Set("chat_id", itoa(chatID))
-	return postFile[APIResponseMessage](a.base, "sendDocument", "document", file, thumbnail, addValues(vals, opts))
+	return postFile[APIResponseMessage](a, "sendDocument", "document", file, thumbnail, addValues(vals, opts))
 }
 
 // SendVideo is used to send video files.
@@ -200,7 +281,7 @@ func (a API) SendVideo(file InputFile, chatID int64, opts *VideoOptions) (res AP
 	}
 
 	vals.Set("chat_id", itoa(chatID))
-	return postFile[APIResponseMessage](a.base, "sendVideo", "video", file, thumbnail, addValues(vals, opts))
+	return postFile[APIResponseMessage](a, "sendVideo", "video", file, thumbnail, addValues(vals, opts))
 }
 
 // SendAnimation is used to send animation files (GIF or H.264/MPEG-4 AVC video without sound).
@@ -215,7 +296,7 @@ func (a API) SendAnimation(file InputFile, chatID int64, opts *AnimationOptions)
 	}
 
 	vals.Set("chat_id", itoa(chatID))
-	return postFile[APIResponseMessage](a.base, "sendAnimation", "animation", file, thumbnail, addValues(vals, opts))
+	return postFile[APIResponseMessage](a, "sendAnimation", "animation", file, thumbnail, addValues(vals, opts))
 }
 
 // SendVoice is used to send audio files, if you want Telegram clients to display the file as a playable voice message.
@@ -224,7 +305,7 @@ func (a API) SendVoice(file InputFile, chatID int64, opts *VoiceOptions) (res AP
 	var vals = make(url.Values)
 
 	vals.Set("chat_id", itoa(chatID))
-	return postFile[APIResponseMessage](a.base, "sendVoice", "voice", file, InputFile{}, addValues(vals, opts))
+	return postFile[APIResponseMessage](a, "sendVoice", "voice", file, InputFile{}, addValues(vals, opts))
 }
 
 // SendVideoNote is used to send video messages.
@@ -239,7 +320,7 @@ func (a API) SendVideoNote(file InputFile, chatID int64, opts *VideoNoteOptions)
 	}
 
 	vals.Set("chat_id", itoa(chatID))
-	return postFile[APIResponseMessage](a.base, "sendVideoNote", "video_note", file, thumbnail, addValues(vals, opts))
+	return postFile[APIResponseMessage](a, "sendVideoNote", "video_note", file, thumbnail, addValues(vals, opts))
 }
 
 // SendMediaGroup is used to send a group of photos, videos, documents or audios as an album.
@@ -248,7 +329,7 @@ func (a API) SendMediaGroup(chatID int64, media []GroupableInputMedia, opts *Med
 	var vals = make(url.Values)
 
 	vals.Set("chat_id", itoa(chatID))
-	return postMedia[APIResponseMessageArray](a.base, "sendMediaGroup", false, addValues(vals, opts), toInputMedia(media)...)
+	return postMedia[APIResponseMessageArray](a, "sendMediaGroup", false, addValues(vals, opts), toInputMedia(media)...)
 }
 
 // SendLocation is used to send point on the map.
@@ -258,7 +339,7 @@ func (a API) SendLocation(chatID int64, latitude, longitude float64, opts *Locat
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("latitude", ftoa(latitude))
 	vals.Set("longitude", ftoa(longitude))
-	return get[APIResponseMessage](a.base, "sendLocation", addValues(vals, opts))
+	return get[APIResponseMessage](a, "sendLocation", addValues(vals, opts))
 }
 
 // EditMessageLiveLocation is used to edit live location messages.
@@ -268,12 +349,12 @@ func (a API) EditMessageLiveLocation(msg MessageIDOptions, latitude, longitude f
 
 	vals.Set("latitude", ftoa(latitude))
 	vals.Set("longitude", ftoa(longitude))
-	return get[APIResponseMessage](a.base, "editMessageLiveLocation", addValues(addValues(vals, msg), opts))
+	return get[APIResponseMessage](a, "editMessageLiveLocation", addValues(addValues(vals, msg), opts))
 }
 
 // StopMessageLiveLocation is used to stop updating a live location message before `LivePeriod` expires.
 func (a API) StopMessageLiveLocation(msg MessageIDOptions, opts *MessageReplyMarkup) (res APIResponseMessage, err error) {
-	return get[APIResponseMessage](a.base, "stopMessageLiveLocation", addValues(urlValues(msg), opts))
+	return get[APIResponseMessage](a, "stopMessageLiveLocation", addValues(urlValues(msg), opts))
 }
 
 // SendVenue is used to send information about a venue.
@@ -285,7 +366,7 @@ func (a API) SendVenue(chatID int64, latitude, longitude float64, title, address
 	vals.Set("longitude", ftoa(longitude))
 	vals.Set("title", title)
 	vals.Set("address", address)
-	return get[APIResponseMessage](a.base, "sendVenue", addValues(vals, opts))
+	return get[APIResponseMessage](a, "sendVenue", addValues(vals, opts))
 }
 
 // SendContact is used to send phone contacts.
@@ -295,7 +376,7 @@ func (a API) SendContact(phoneNumber, firstName string, chatID int64, opts *Cont
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("phone_number", phoneNumber)
 	vals.Set("first_name", firstName)
-	return get[APIResponseMessage](a.base, "sendContact", addValues(vals, opts))
+	return get[APIResponseMessage](a, "sendContact", addValues(vals, opts))
 }
 
 // SendPoll is used to send a native poll.
@@ -310,7 +391,7 @@ func (a API) SendPoll(chatID int64, question string, options []string, opts *Pol
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("question", question)
 	vals.Set("options", string(pollOpts))
-	return get[APIResponseMessage](a.base, "sendPoll", addValues(vals, opts))
+	return get[APIResponseMessage](a, "sendPoll", addValues(vals, opts))
 }
 
 // SendDice is used to send an animated emoji that will display a random value.
@@ -319,7 +400,7 @@ func (a API) SendDice(chatID int64, emoji DiceEmoji, opts *BaseOptions) (res API
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("emoji", string(emoji))
-	return get[APIResponseMessage](a.base, "sendDice", addValues(vals, opts))
+	return get[APIResponseMessage](a, "sendDice", addValues(vals, opts))
 }
 
 // SendChatAction is used to tell the user that something is happening on the bot's side.
@@ -329,7 +410,7 @@ func (a API) SendChatAction(action ChatAction, chatID int64, opts *ChatActionOpt
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("action", string(action))
-	return get[APIResponseBool](a.base, "sendChatAction", addValues(vals, opts))
+	return get[APIResponseBool](a, "sendChatAction", addValues(vals, opts))
 }
 
 // GetUserProfilePhotos is used to get a list of profile pictures for a user.
@@ -337,7 +418,7 @@ func (a API) GetUserProfilePhotos(userID int64, opts *UserProfileOptions) (res A
 	var vals = make(url.Values)
 
 	vals.Set("user_id", itoa(userID))
-	return get[APIResponseUserProfile](a.base, "getUserProfilePhotos", addValues(vals, opts))
+	return get[APIResponseUserProfile](a, "getUserProfilePhotos", addValues(vals, opts))
 }
 
 // GetFile returns the basic info about a file and prepares it for downloading.
@@ -349,18 +430,14 @@ func (a API) GetFile(fileID string) (res APIResponseFile, err error) {
 	var vals = make(url.Values)
 
 	vals.Set("file_id", fileID)
-	return get[APIResponseFile](a.base, "getFile", vals)
+	return get[APIResponseFile](a, "getFile", vals)
 }
 
 // DownloadFile returns the bytes of the file corresponding to the given filePath.
 // This function is callable for at least 1 hour since the call to GetFile.
 // When the download expires a new one can be requested by calling GetFile again.
 func (a API) DownloadFile(filePath string) ([]byte, error) {
-	return sendGetRequest(fmt.Sprintf(
-		"https://api.telegram.org/file/bot%s/%s",
-		a.token,
-		filePath,
-	))
+	return a.sendGetRequest(a.fileBase + filePath)
 }
 
 // BanChatMember is used to ban a user in a group, a supergroup or a channel.
@@ -372,7 +449,7 @@ func (a API) BanChatMember(chatID, userID int64, opts *BanOptions) (res APIRespo
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("user_id", itoa(userID))
-	return get[APIResponseBool](a.base, "banChatMember", addValues(vals, opts))
+	return get[APIResponseBool](a, "banChatMember", addValues(vals, opts))
 }
 
 // UnbanChatMember is used to unban a previously banned user in a supergroup or channel.
@@ -386,7 +463,7 @@ func (a API) UnbanChatMember(chatID, userID int64, opts *UnbanOptions) (res APIR
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("user_id", itoa(userID))
-	return get[APIResponseBool](a.base, "unbanChatMember", addValues(vals, opts))
+	return get[APIResponseBool](a, "unbanChatMember", addValues(vals, opts))
 }
 
 // RestrictChatMember is used to restrict a user in a supergroup.
@@ -402,7 +479,7 @@ func (a API) RestrictChatMember(chatID, userID int64, permissions ChatPermission
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("user_id", itoa(userID))
 	vals.Set("permissions", perm)
-	return get[APIResponseBool](a.base, "restrictChatMember", addValues(vals, opts))
+	return get[APIResponseBool](a, "restrictChatMember", addValues(vals, opts))
 }
 
 // PromoteChatMember is used to promote or demote a user in a supergroup or a channel.
@@ -412,7 +489,7 @@ func (a API) PromoteChatMember(chatID, userID int64, opts *PromoteOptions) (res
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("user_id", itoa(userID))
-	return get[APIResponseBool](a.base, "promoteChatMember", addValues(vals, opts))
+	return get[APIResponseBool](a, "promoteChatMember", addValues(vals, opts))
 }
 
 // SetChatAdministratorCustomTitle is used to set a custom title for an administrator in a supergroup promoted by the bot.
@@ -422,7 +499,7 @@ func (a API) SetChatAdministratorCustomTitle(chatID, userID int64, customTitle s
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("user_id", itoa(userID))
 	vals.Set("custom_title", customTitle)
-	return get[APIResponseBool](a.base, "setChatAdministratorCustomTitle", vals)
+	return get[APIResponseBool](a, "setChatAdministratorCustomTitle", vals)
 }
 
 // BanChatSenderChat is used to ban a channel chat in a supergroup or a channel.
@@ -433,7 +510,7 @@ func (a API) BanChatSenderChat(chatID, senderChatID int64) (res APIResponseBool,
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("sender_chat_id", itoa(senderChatID))
-	return get[APIResponseBool](a.base, "banChatSenderChat", vals)
+	return get[APIResponseBool](a, "banChatSenderChat", vals)
 }
 
 // UnbanChatSenderChat is used to unban a previously channel chat in a supergroup or channel.
@@ -443,7 +520,7 @@ func (a API) UnbanChatSenderChat(chatID, senderChatID int64) (res APIResponseBoo
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("sender_chat_id", itoa(senderChatID))
-	return get[APIResponseBool](a.base, "unbanChatSenderChat", vals)
+	return get[APIResponseBool](a, "unbanChatSenderChat", vals)
 }
 
 // SetChatPermissions is used to set default chat permissions for all members.
@@ -458,7 +535,7 @@ func (a API) SetChatPermissions(chatID int64, permissions ChatPermissions, opts
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("permissions", perm)
-	return get[APIResponseBool](a.base, "setChatPermissions", addValues(vals, opts))
+	return get[APIResponseBool](a, "setChatPermissions", addValues(vals, opts))
 }
 
 // ExportChatInviteLink is used to generate a new primary invite link for a chat;
@@ -468,7 +545,7 @@ func (a API) ExportChatInviteLink(chatID int64) (res APIResponseString, err erro
 	var vals = make(url.Values)
 
 	vals.Set("chat_id", itoa(chatID))
-	return get[APIResponseString](a.base, "exportChatInviteLink", vals)
+	return get[APIResponseString](a, "exportChatInviteLink", vals)
 }
 
 // CreateChatInviteLink is used to create an additional invite link for a chat.
@@ -478,7 +555,7 @@ func (a API) CreateChatInviteLink(chatID int64, opts *InviteLinkOptions) (res AP
 	var vals = make(url.Values)
 
 	vals.Set("chat_id", itoa(chatID))
-	return get[APIResponseInviteLink](a.base, "createChatInviteLink", addValues(vals, opts))
+	return get[APIResponseInviteLink](a, "createChatInviteLink", addValues(vals, opts))
 }
 
 // EditChatInviteLink is used to edit a non-primary invite link created by the bot.
@@ -488,7 +565,7 @@ func (a API) EditChatInviteLink(chatID int64, inviteLink string, opts *InviteLin
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("invite_link", inviteLink)
-	return get[APIResponseInviteLink](a.base, "editChatInviteLink", addValues(vals, opts))
+	return get[APIResponseInviteLink](a, "editChatInviteLink", addValues(vals, opts))
 }
 
 // RevokeChatInviteLink is used to revoke an invite link created by the bot.
@@ -499,7 +576,7 @@ func (a API) RevokeChatInviteLink(chatID int64, inviteLink string) (res APIRespo
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("invite_link", inviteLink)
-	return get[APIResponseInviteLink](a.base, "editChatInviteLink", vals)
+	return get[APIResponseInviteLink](a, "editChatInviteLink", vals)
 }
 
 // ApproveChatJoinRequest is used to approve a chat join request.
@@ -509,7 +586,7 @@ func (a API) ApproveChatJoinRequest(chatID, userID int64) (res APIResponseBool,
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("user_id", itoa(userID))
-	return get[APIResponseBool](a.base, "approveChatJoinRequest", vals)
+	return get[APIResponseBool](a, "approveChatJoinRequest", vals)
 }
 
 // DeclineChatJoinRequest is used to decline a chat join request.
@@ -519,7 +596,7 @@ func (a API) DeclineChatJoinRequest(chatID, userID int64) (res APIResponseBool,
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("user_id", itoa(userID))
-	return get[APIResponseBool](a.base, "declineChatJoinRequest", vals)
+	return get[APIResponseBool](a, "declineChatJoinRequest", vals)
 }
 
 // SetChatPhoto is used to set a new profile photo for the chat.
@@ -529,7 +606,7 @@ func (a API) SetChatPhoto(file InputFile, chatID int64) (res APIResponseBool, er
 	var vals = make(url.Values)
 
 	vals.Set("chat_id", itoa(chatID))
-	return postFile[APIResponseBool](a.base, "setChatPhoto", "photo", file, InputFile{}, vals)
+	return postFile[APIResponseBool](a, "setChatPhoto", "photo", file, InputFile{}, vals)
 }
 
 // DeleteChatPhoto is used to delete a chat photo.
@@ -539,7 +616,7 @@ func (a API) DeleteChatPhoto(chatID int64) (res APIResponseBool, err error) {
 	var vals = make(url.Values)
 
 	vals.Set("chat_id", itoa(chatID))
-	return get[APIResponseBool](a.base, "deleteChatPhoto", vals)
+	return get[APIResponseBool](a, "deleteChatPhoto", vals)
 }
 
 // SetChatTitle is used to change the title of a chat.
@@ -550,7 +627,7 @@ func (a API) SetChatTitle(chatID int64, title string) (res APIResponseBool, err
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("title", title)
-	return get[APIResponseBool](a.base, "setChatTitle", vals)
+	return get[APIResponseBool](a, "setChatTitle", vals)
 }
 
 // SetChatDescription is used to change the description of a group, a supergroup or a channel.
@@ -560,7 +637,7 @@ func (a API) SetChatDescription(chatID int64, description string) (res APIRespon
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("description", description)
-	return get[APIResponseBool](a.base, "setChatDescription", vals)
+	return get[APIResponseBool](a, "setChatDescription", vals)
 }
 
 // PinChatMessage is used to add a message to the list of pinned messages in the chat.
@@ -571,7 +648,7 @@ func (a API) PinChatMessage(chatID int64, messageID int, opts *PinMessageOptions
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("message_id", itoa(int64(messageID)))
-	return get[APIResponseBool](a.base, "pinChatMessage", addValues(vals, opts))
+	return get[APIResponseBool](a, "pinChatMessage", addValues(vals, opts))
 }
 
 // UnpinChatMessage is used to remove a message from the list of pinned messages in the chat.
@@ -582,7 +659,7 @@ func (a API) UnpinChatMessage(chatID int64, messageID int) (res APIResponseBool,
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("message_id", itoa(int64(messageID)))
-	return get[APIResponseBool](a.base, "unpinChatMessage", vals)
+	return get[APIResponseBool](a, "unpinChatMessage", vals)
 }
 
 // UnpinAllChatMessages is used to clear the list of pinned messages in a chat.
@@ -592,7 +669,7 @@ func (a API) UnpinAllChatMessages(chatID int64) (res APIResponseBool, err error)
 	var vals = make(url.Values)
 
 	vals.Set("chat_id", itoa(chatID))
-	return get[APIResponseBool](a.base, "unpinAllChatMessages", vals)
+	return get[APIResponseBool](a, "unpinAllChatMessages", vals)
 }
 
 // LeaveChat is used to make the bot leave a group, supergroup or channel.
@@ -600,7 +677,7 @@ func (a API) LeaveChat(chatID int64) (res APIResponseBool, err error) {
 	var vals = make(url.Values)
 
 	vals.Set("chat_id", itoa(chatID))
-	return get[APIResponseBool](a.base, "leaveChat", vals)
+	return get[APIResponseBool](a, "leaveChat", vals)
 }
 
 // GetChat is used to get up to date information about the chat.
@@ -609,7 +686,7 @@ func (a API) GetChat(chatID int64) (res APIResponseChat, err error) {
 	var vals = make(url.Values)
 
 	vals.Set("chat_id", itoa(chatID))
-	return get[APIResponseChat](a.base, "getChat", vals)
+	return get[APIResponseChat](a, "getChat", vals)
 }
 
 // GetChatAdministrators is used to get a list of administrators in a chat.
@@ -617,7 +694,7 @@ func (a API) GetChatAdministrators(chatID int64) (res APIResponseAdministrators,
 	var vals = make(url.Values)
 
 	vals.Set("chat_id", itoa(chatID))
-	return get[APIResponseAdministrators](a.base, "getChatAdministrators", vals)
+	return get[APIResponseAdministrators](a, "getChatAdministrators", vals)
 }
 
 // GetChatMemberCount is used to get the number of members in a chat.
@@ -625,7 +702,7 @@ func (a API) GetChatMemberCount(chatID int64) (res APIResponseInteger, err error
 	var vals = make(url.Values)
 
 	vals.Set("chat_id", itoa(chatID))
-	return get[APIResponseInteger](a.base, "getChatMemberCount", vals)
+	return get[APIResponseInteger](a, "getChatMemberCount", vals)
 }
 
 // GetChatMember is used to get information about a member of a chat.
@@ -634,7 +711,7 @@ func (a API) GetChatMember(chatID, userID int64) (res APIResponseChatMember, err
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("user_id", itoa(userID))
-	return get[APIResponseChatMember](a.base, "getChatMember", vals)
+	return get[APIResponseChatMember](a, "getChatMember", vals)
 }
 
 // SetChatStickerSet is used to set a new group sticker set for a supergroup.
@@ -645,7 +722,7 @@ func (a API) SetChatStickerSet(chatID int64, stickerSetName string) (res APIResp
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("sticker_set_name", stickerSetName)
-	return get[APIResponseBool](a.base, "setChatStickerSet", vals)
+	return get[APIResponseBool](a, "setChatStickerSet", vals)
 }
 
 // DeleteChatStickerSet is used to delete a group sticker set for a supergroup.
@@ -655,7 +732,7 @@ func (a API) DeleteChatStickerSet(chatID int64) (res APIResponseBool, err error)
 	var vals = make(url.Values)
 
 	vals.Set("chat_id", itoa(chatID))
-	return get[APIResponseBool](a.base, "deleteChatStickerSet", vals)
+	return get[APIResponseBool](a, "deleteChatStickerSet", vals)
 }
 
 // CreateForumTopic is used to create a topic in a forum supergroup chat.
@@ -665,7 +742,7 @@ func (a API) CreateForumTopic(chatID int64, name string, opts *CreateTopicOption
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("name", name)
-	return get[APIResponseForumTopic](a.base, "createForumTopic", addValues(vals, opts))
+	return get[APIResponseForumTopic](a, "createForumTopic", addValues(vals, opts))
 }
 
 // EditForumTopic is used to edit name and icon of a topic in a forum supergroup chat.
@@ -675,7 +752,7 @@ func (a API) EditForumTopic(chatID, messageThreadID int64, opts *EditTopicOption
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("message_thread_id", itoa(messageThreadID))
-	return get[APIResponseBool](a.base, "editForumTopic", addValues(vals, opts))
+	return get[APIResponseBool](a, "editForumTopic", addValues(vals, opts))
 }
 
 // CloseForumTopic is used to close an open topic in a forum supergroup chat.
@@ -685,7 +762,7 @@ func (a API) CloseForumTopic(chatID, messageThreadID int64) (res APIResponseBool
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("message_thread_id", itoa(messageThreadID))
-	return get[APIResponseBool](a.base, "closeForumTopic", vals)
+	return get[APIResponseBool](a, "closeForumTopic", vals)
 }
 
 // ReopenForumTopic is used to reopen a closed topic in a forum supergroup chat.
@@ -695,7 +772,7 @@ func (a API) ReopenForumTopic(chatID, messageThreadID int64) (res APIResponseBoo
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("message_thread_id", itoa(messageThreadID))
-	return get[APIResponseBool](a.base, "reopenForumTopic", vals)
+	return get[APIResponseBool](a, "reopenForumTopic", vals)
 }
 
 // DeleteForumTopic is used to delete a forum topic along with all its messages in a forum supergroup chat.
@@ -705,7 +782,7 @@ func (a API) DeleteForumTopic(chatID, messageThreadID int64) (res APIResponseBoo
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("message_thread_id", itoa(messageThreadID))
-	return get[APIResponseBool](a.base, "deleteForumTopic", vals)
+	return get[APIResponseBool](a, "deleteForumTopic", vals)
 }
 
 // UnpinAllForumTopicMessages is used to clear the list of pinned messages in a forum topic.
@@ -715,7 +792,7 @@ func (a API) UnpinAllForumTopicMessages(chatID, messageThreadID int64) (res APIR
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("message_thread_id", itoa(messageThreadID))
-	return get[APIResponseBool](a.base, "unpinAllForumTopicMessages", vals)
+	return get[APIResponseBool](a, "unpinAllForumTopicMessages", vals)
 }
 
 // EditGeneralForumTopic is used to edit the name of the 'General' topic in a forum supergroup chat.
@@ -725,7 +802,7 @@ func (a API) EditGeneralForumTopic(chatID int64, name string) (res APIResponseBo
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("name", name)
-	return get[APIResponseBool](a.base, "editGeneralForumTopic", vals)
+	return get[APIResponseBool](a, "editGeneralForumTopic", vals)
 }
 
 // CloseGeneralForumTopic is used to close an open 'General' topic in a forum supergroup chat.
@@ -734,7 +811,7 @@ func (a API) CloseGeneralForumTopic(chatID int64) (res APIResponseBool, err erro
 	var vals = make(url.Values)
 
 	vals.Set("chat_id", itoa(chatID))
-	return get[APIResponseBool](a.base, "closeGeneralForumTopic", vals)
+	return get[APIResponseBool](a, "closeGeneralForumTopic", vals)
 }
 
 // ReopenGeneralForumTopic is used to reopen a closed 'General' topic in a forum supergroup chat.
@@ -744,7 +821,7 @@ func (a API) ReopenGeneralForumTopic(chatID int64) (res APIResponseBool, err err
 	var vals = make(url.Values)
 
 	vals.Set("chat_id", itoa(chatID))
-	return get[APIResponseBool](a.base, "reopenGeneralForumTopic", vals)
+	return get[APIResponseBool](a, "reopenGeneralForumTopic", vals)
 }
 
 // HideGeneralForumTopic is used to hide the 'General' topic in a forum supergroup chat.
@@ -754,7 +831,7 @@ func (a API) HideGeneralForumTopic(chatID int64) (res APIResponseBool, err error
 	var vals = make(url.Values)
 
 	vals.Set("chat_id", itoa(chatID))
-	return get[APIResponseBool](a.base, "hideGeneralForumTopic", vals)
+	return get[APIResponseBool](a, "hideGeneralForumTopic", vals)
 }
 
 // UnhideGeneralForumTopic is used to unhide the 'General' topic in a forum supergroup chat.
@@ -763,7 +840,7 @@ func (a API) UnhideGeneralForumTopic(chatID int64) (res APIResponseBool, err err
 	var vals = make(url.Values)
 
 	vals.Set("chat_id", itoa(chatID))
-	return get[APIResponseBool](a.base, "unhideGeneralForumTopic", vals)
+	return get[APIResponseBool](a, "unhideGeneralForumTopic", vals)
 }
 
 // AnswerCallbackQuery is used to send answers to callback queries sent from inline keyboards.
@@ -772,7 +849,7 @@ func (a API) AnswerCallbackQuery(callbackID string, opts *CallbackQueryOptions)
 	var vals = make(url.Values)
 
 	vals.Set("callback_query_id", callbackID)
-	return get[APIResponseBool](a.base, "answerCallbackQuery", addValues(vals, opts))
+	return get[APIResponseBool](a, "answerCallbackQuery", addValues(vals, opts))
 }
 
 // SetMyCommands is used to change the list of the bot's commands for the given scope and user language.
@@ -781,17 +858,17 @@ func (a API) SetMyCommands(opts *CommandOptions, commands ...BotCommand) (res AP
 
 	jsn, _ := json.Marshal(commands)
 	vals.Set("commands", string(jsn))
-	return get[APIResponseBool](a.base, "setMyCommands", addValues(vals, opts))
+	return get[APIResponseBool](a, "setMyCommands", addValues(vals, opts))
 }
 
 // DeleteMyCommands is used to delete the list of the bot's commands for the given scope and user language.
 func (a API) DeleteMyCommands(opts *CommandOptions) (res APIResponseBool, err error) {
-	return get[APIResponseBool](a.base, "deleteMyCommands", urlValues(opts))
+	return get[APIResponseBool](a, "deleteMyCommands", urlValues(opts))
 }
 
 // GetMyCommands is used to get the current list of the bot's commands for the given scope and user language.
 func (a API) GetMyCommands(opts *CommandOptions) (res APIResponseCommands, err error) {
-	return get[APIResponseCommands](a.base, "getMyCommands", urlValues(opts))
+	return get[APIResponseCommands](a, "getMyCommands", urlValues(opts))
 }
 
 // SetMyName is used to change the bot's name.
@@ -800,7 +877,7 @@ func (a API) SetMyName(name, languageCode string) (res APIResponseBool, err erro
 
 	vals.Set("name", name)
 	vals.Set("language_code", languageCode)
-	return get[APIResponseBool](a.base, "setMyName", vals)
+	return get[APIResponseBool](a, "setMyName", vals)
 }
 
 // GetMyName is used to get the current bot name for the given user language.
@@ -808,7 +885,7 @@ func (a API) GetMyName(languageCode string) (res APIResponseBotName, err error)
 	var vals = make(url.Values)
 
 	vals.Set("language_code", languageCode)
-	return get[APIResponseBotName](a.base, "getMyName", vals)
+	return get[APIResponseBotName](a, "getMyName", vals)
 }
 
 // SetMyDescription is used to to change the bot's description, which is shown in the chat with the bot if the chat is empty.
@@ -817,7 +894,7 @@ func (a API) SetMyDescription(description, languageCode string) (res APIResponse
 
 	vals.Set("description", description)
 	vals.Set("language_code", languageCode)
-	return get[APIResponseBool](a.base, "setMyDescription", vals)
+	return get[APIResponseBool](a, "setMyDescription", vals)
 }
 
 // GetMyDescription is used to get the current bot description for the given user language.
@@ -825,7 +902,7 @@ func (a API) GetMyDescription(languageCode string) (res APIResponseBotDescriptio
 	var vals = make(url.Values)
 
 	vals.Set("language_code", languageCode)
-	return get[APIResponseBotDescription](a.base, "getMyDescription", vals)
+	return get[APIResponseBotDescription](a, "getMyDescription", vals)
 }
 
 // SetMyShortDescription is used to to change the bot's short description,
@@ -835,7 +912,7 @@ func (a API) SetMyShortDescription(shortDescription, languageCode string) (res A
 
 	vals.Set("short_description", shortDescription)
 	vals.Set("language_code", languageCode)
-	return get[APIResponseBool](a.base, "setMyShortDescription", vals)
+	return get[APIResponseBool](a, "setMyShortDescription", vals)
 }
 
 // GetMyShortDescription is used to get the current bot short description for the given user language.
@@ -843,7 +920,7 @@ func (a API) GetMyShortDescription(languageCode string) (res APIResponseBotShort
 	var vals = make(url.Values)
 
 	vals.Set("language_code", languageCode)
-	return get[APIResponseBotShortDescription](a.base, "getMyDescription", vals)
+	return get[APIResponseBotShortDescription](a, "getMyShortDescription", vals)
 }
 
 // EditMessageText is used to edit text and game messages.
@@ -851,12 +928,12 @@ func (a API) EditMessageText(text string, msg MessageIDOptions, opts *MessageTex
 	var vals = make(url.Values)
 
 	vals.Set("text", text)
-	return get[APIResponseMessage](a.base, "editMessageText", addValues(addValues(vals, msg), opts))
+	return get[APIResponseMessage](a, "editMessageText", addValues(addValues(vals, msg), opts))
 }
 
 // EditMessageCaption is used to edit captions of messages.
 func (a API) EditMessageCaption(msg MessageIDOptions, opts *MessageCaptionOptions) (res APIResponseMessage, err error) {
-	return get[APIResponseMessage](a.base, "editMessageCaption", addValues(urlValues(msg), opts))
+	return get[APIResponseMessage](a, "editMessageCaption", addValues(urlValues(msg), opts))
 }
 
 // EditMessageMedia is used to edit animation, audio, document, photo or video messages.
@@ -865,12 +942,12 @@ func (a API) EditMessageCaption(msg MessageIDOptions, opts *MessageCaptionOption
 // When an inline message is edited, a new file can't be uploaded.
 // Use a previously uploaded file via its file_id or specify a URL.
 func (a API) EditMessageMedia(msg MessageIDOptions, media InputMedia, opts *MessageReplyMarkup) (res APIResponseMessage, err error) {
-	return postMedia[APIResponseMessage](a.base, "editMessageMedia", true, addValues(urlValues(msg), opts), media)
+	return postMedia[APIResponseMessage](a, "editMessageMedia", true, addValues(urlValues(msg), opts), media)
 }
 
 // EditMessageReplyMarkup is used to edit only the reply markup of messages.
 func (a API) EditMessageReplyMarkup(msg MessageIDOptions, opts *MessageReplyMarkup) (res APIResponseMessage, err error) {
-	return get[APIResponseMessage](a.base, "editMessageReplyMarkup", addValues(urlValues(msg), opts))
+	return get[APIResponseMessage](a, "editMessageReplyMarkup", addValues(urlValues(msg), opts))
 }
 
 // StopPoll is used to stop a poll which was sent by the bot.
@@ -879,7 +956,7 @@ func (a API) StopPoll(chatID int64, messageID int, opts *MessageReplyMarkup) (re
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("message_id", itoa(int64(messageID)))
-	return get[APIResponsePoll](a.base, "stopPoll", addValues(vals, opts))
+	return get[APIResponsePoll](a, "stopPoll", addValues(vals, opts))
 }
 
 // DeleteMessage is used to delete a message, including service messages, with the following limitations:
@@ -895,5 +972,5 @@ func (a API) DeleteMessage(chatID int64, messageID int) (res APIResponseBase, er
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("message_id", itoa(int64(messageID)))
-	return get[APIResponseBase](a.base, "deleteMessage", vals)
+	return get[APIResponseBase](a, "deleteMessage", vals)
 }
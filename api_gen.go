@@ -0,0 +1,74 @@
+// Code generated by apigen; DO NOT EDIT.
+
+package echotron
+
+import (
+	"net/url"
+)
+
+// CopyMessages is used to copy messages of any kind. If some of the specified messages can't be found or copied,
+// they are skipped. Service messages, giveaway messages, giveaway winners messages, and invoice messages can't be
+// copied. A quiz poll can be copied only if the value of the field correct_option_id is known to the bot. The
+// method is analogous to the method forwardMessages, but the copied messages don't have a link to the original
+// message. Album grouping is kept for copied messages.
+func (a API) CopyMessages(chatID, fromChatID int64, messageIDs []int, opts *CopyMessagesOptions) (res APIResponseMessageIDArray, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", itoa(chatID))
+	vals.Set("from_chat_id", itoa(fromChatID))
+	vals.Set("message_ids", jsonString(messageIDs))
+	return get[APIResponseMessageIDArray](a, "copyMessages", addValues(vals, opts))
+}
+
+// DeleteMessages is used to delete multiple messages simultaneously. If some of the specified messages can't be
+// found, they are skipped.
+func (a API) DeleteMessages(chatID int64, messageIDs []int) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", itoa(chatID))
+	vals.Set("message_ids", jsonString(messageIDs))
+	return get[APIResponseBool](a, "deleteMessages", vals)
+}
+
+// ForwardMessages is used to forward multiple messages of any kind. If some of the specified messages can't be
+// found or forwarded, they are skipped. Service messages and messages with protected content can't be forwarded.
+// Album grouping is kept for forwarded messages.
+func (a API) ForwardMessages(chatID, fromChatID int64, messageIDs []int, opts *ForwardMessagesOptions) (res APIResponseMessageIDArray, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", itoa(chatID))
+	vals.Set("from_chat_id", itoa(fromChatID))
+	vals.Set("message_ids", jsonString(messageIDs))
+	return get[APIResponseMessageIDArray](a, "forwardMessages", addValues(vals, opts))
+}
+
+// GetUserChatBoosts is used to get the list of boosts added to a chat by a user. Requires administrator rights in
+// the chat.
+func (a API) GetUserChatBoosts(chatID, userID int64) (res APIResponseUserChatBoosts, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", itoa(chatID))
+	vals.Set("user_id", itoa(userID))
+	return get[APIResponseUserChatBoosts](a, "getUserChatBoosts", vals)
+}
+
+// SetMessageReaction is used to change the chosen reactions on a message. Service messages can't be reacted to.
+// Automatically forwarded messages from a channel to its discussion group have the same available reactions as
+// messages in the channel.
+func (a API) SetMessageReaction(chatID int64, messageID int, opts *SetMessageReactionOptions) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", itoa(chatID))
+	vals.Set("message_id", itoa(int64(messageID)))
+	return get[APIResponseBool](a, "setMessageReaction", addValues(vals, opts))
+}
+
+// UnpinAllGeneralForumTopicMessages is used to clear the list of pinned messages in a General forum topic. The
+// bot must be an administrator in the chat for this to work and must have the can_pin_messages administrator
+// right in the supergroup.
+func (a API) UnpinAllGeneralForumTopicMessages(chatID int64) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", itoa(chatID))
+	return get[APIResponseBool](a, "unpinAllGeneralForumTopicMessages", vals)
+}
@@ -0,0 +1,37 @@
+package echotron
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestGeneratedMethods(t *testing.T) {
+	var (
+		params url.Values
+		path   string
+		a      = newTestServerAPI(t, nil, func(w http.ResponseWriter, r *http.Request) {
+			params, path = r.URL.Query(), r.URL.Path
+			w.Write([]byte(`{"ok":true,"result":[{"message_id":10},{"message_id":11}]}`))
+		})
+	)
+
+	res, err := a.ForwardMessages(42, 43, []int{1, 2}, &ForwardMessagesOptions{ProtectContent: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/bottoken/forwardMessages" {
+		t.Fatalf("unexpected path %s", path)
+	}
+	if params.Get("chat_id") != "42" || params.Get("from_chat_id") != "43" || params.Get("message_ids") != "[1,2]" || params.Get("protect_content") != "true" {
+		t.Fatalf("unexpected params %v", params)
+	}
+	if len(res.Result) != 2 || res.Result[1].MessageID != 11 {
+		t.Fatalf("unexpected result %+v", res.Result)
+	}
+
+	a.SetMessageReaction(42, 1, &SetMessageReactionOptions{Reaction: []ReactionType{{Type: "emoji", Emoji: "👍"}}})
+	if params.Get("reaction") != `[{"type":"emoji","emoji":"👍"}]` {
+		t.Fatalf("unexpected reaction %q", params.Get("reaction"))
+	}
+}
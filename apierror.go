@@ -18,12 +18,17 @@
 
 package echotron
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
 
 // APIError represents an error returned by the Telegram API.
 type APIError struct {
-	desc string
-	code int
+	params *ResponseParameters
+	desc   string
+	code   int
 }
 
 // ErrorCode returns the error code received from the Telegram API.
@@ -36,7 +41,20 @@ func (a *APIError) Description() string {
 	return a.desc
 }
 
+// Parameters returns the parameters of the response received from the Telegram API, if any,
+// which tell for example how long to wait before retrying when the flood limit is exceeded.
+func (a *APIError) Parameters() *ResponseParameters {
+	return a.params
+}
+
 // Error returns the error string.
 func (a *APIError) Error() string {
 	return fmt.Sprintf("API error: %d %s", a.code, a.desc)
 }
+
+// IsConflict reports whether err is the 409 Conflict error returned by Telegram when another getUpdates
+// request or a webhook is active for the same bot, eg: when two replicas of the bot are polling at once.
+func IsConflict(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.code == http.StatusConflict
+}
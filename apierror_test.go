@@ -1,6 +1,11 @@
 package echotron
 
-import "testing"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
 
 var a APIError
 
@@ -15,3 +20,30 @@ func TestDescription(_ *testing.T) {
 func TestError(_ *testing.T) {
 	_ = a.Error()
 }
+
+func TestIsConflict(t *testing.T) {
+	conflict := &APIError{code: 409, desc: "Conflict: terminated by other getUpdates request"}
+
+	if !IsConflict(conflict) || !IsConflict(fmt.Errorf("wrapped: %w", conflict)) {
+		t.Fatal("expected a conflict")
+	}
+	if IsConflict(&APIError{code: 400}) || IsConflict(nil) {
+		t.Fatal("unexpected conflict")
+	}
+}
+
+func TestParameters(t *testing.T) {
+	a := newTestServerAPI(t, nil, func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 5","parameters":{"retry_after":5}}`))
+	})
+
+	_, err := a.GetMe()
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an APIError, got %v", err)
+	}
+	if p := apiErr.Parameters(); p == nil || p.RetryAfter != 5 {
+		t.Fatalf("unexpected parameters %+v", p)
+	}
+}
@@ -0,0 +1,161 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package echotron
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+)
+
+// APIResponseRaw represents the incoming response from Telegram servers.
+// Used by the Call method, which leaves the result to be decoded by the caller.
+type APIResponseRaw struct {
+	Result json.RawMessage `json:"result,omitempty"`
+	APIResponseBase
+}
+
+// Base returns the contained object of type APIResponseBase.
+func (a APIResponseRaw) Base() APIResponseBase {
+	return a.APIResponseBase
+}
+
+// Call calls the given method of the Telegram Bot API, which allows to use the methods that
+// aren't supported by echotron yet.
+// The params can be nil, a url.Values, a map[string]string, a map[string]any whose values are
+// sent as JSON unless they're strings or numbers, or a struct whose fields with the query tag are
+// sent like the ones of the option structs, eg: `query:"chat_id"`.
+// The files are uploaded, or sent by ID or URL, as the parameters named after their key.
+// See the generic Call function to decode the result.
+func (a API) Call(method string, params any, files map[string]InputFile) (res APIResponseRaw, err error) {
+	vals, err := callValues(params)
+	if err != nil {
+		return res, err
+	}
+
+	// The files already on Telegram's servers or reachable by URL are sent as parameters.
+	var names []string
+	for name, f := range files {
+		switch {
+		case f.id != "":
+			vals.Set(name, f.id)
+		case f.url != "":
+			vals.Set(name, f.url)
+		default:
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	addr, err := joinURL(a.base, method, vals)
+	if err != nil {
+		return res, err
+	}
+
+	return request[APIResponseRaw](a, method, vals, files, func(a API) ([]byte, error) {
+		if len(names) == 0 {
+			return a.sendGetRequest(addr)
+		}
+
+		var cnt []content
+		for _, name := range names {
+			c, err := toContent(name, files[name])
+			if err != nil {
+				return nil, err
+			}
+			cnt = append(cnt, c)
+		}
+		return a.sendPostRequest(addr, cnt...)
+	})
+}
+
+// Call calls the given method of the Telegram Bot API like the Call method of the API object,
+// and decodes the result in a value of type T, eg:
+//
+//	boosts, err := echotron.Call[UserChatBoosts](api, "getUserChatBoosts", map[string]any{"chat_id": chatID, "user_id": userID}, nil)
+func Call[T any](a API, method string, params any, files map[string]InputFile) (res T, err error) {
+	raw, err := a.Call(method, params, files)
+	if err != nil {
+		return res, err
+	}
+
+	if len(raw.Result) > 0 {
+		err = json.Unmarshal(raw.Result, &res)
+	}
+	return
+}
+
+// callValues returns the parameters passed to the Call method as url.Values.
+func callValues(params any) (url.Values, error) {
+	var vals = make(url.Values)
+
+	switch p := params.(type) {
+	case nil:
+		return vals, nil
+
+	case url.Values:
+		for k, v := range p {
+			vals[k] = append([]string(nil), v...)
+		}
+		return vals, nil
+
+	case map[string]string:
+		for k, v := range p {
+			vals.Set(k, v)
+		}
+		return vals, nil
+
+	case map[string]any:
+		for k, v := range p {
+			vals.Set(k, paramString(v))
+		}
+		return vals, nil
+	}
+
+	if v := reflect.Indirect(reflect.ValueOf(params)); v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("echotron: unsupported params of type %T", params)
+	}
+	return scan(params, vals), nil
+}
+
+// paramString returns the value of a parameter: strings and numbers are sent as they are, the rest as JSON.
+func paramString(v any) string {
+	if chat, ok := v.(ChatID); ok {
+		return chat.String()
+	}
+
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return itoa(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprint(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return ftoa(rv.Float())
+	case reflect.Bool:
+		return btoa(rv.Bool())
+	default:
+		return jsonString(v)
+	}
+}
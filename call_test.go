@@ -0,0 +1,90 @@
+package echotron
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestCall(t *testing.T) {
+	var (
+		params url.Values
+		a      = newTestServerAPI(t, nil, func(w http.ResponseWriter, r *http.Request) {
+			params = r.URL.Query()
+			w.Write([]byte(`{"ok":true,"result":{"boosts":[{"boost_id":"b1","add_date":10,"source":{"source":"premium"}}]}}`))
+		})
+	)
+
+	boosts, err := Call[UserChatBoosts](a, "getUserChatBoosts", map[string]any{"chat_id": int64(42), "user_id": 7}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Get("chat_id") != "42" || params.Get("user_id") != "7" {
+		t.Fatalf("unexpected params %v", params)
+	}
+	if len(boosts.Boosts) != 1 || boosts.Boosts[0].BoostID != "b1" || boosts.Boosts[0].Source.Source != "premium" {
+		t.Fatalf("unexpected result %+v", boosts)
+	}
+
+	type reactionOptions struct {
+		Reaction []ReactionType `query:"reaction"`
+		ChatID   int64          `query:"chat_id"`
+		IsBig    bool           `query:"is_big"`
+	}
+
+	res, err := a.Call("setMessageReaction", &reactionOptions{ChatID: 42, Reaction: []ReactionType{{Type: "emoji", Emoji: "🔥"}}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Ok || len(res.Result) == 0 {
+		t.Fatalf("unexpected response %+v", res)
+	}
+	if params.Get("reaction") != `[{"type":"emoji","emoji":"🔥"}]` || params.Has("is_big") {
+		t.Fatalf("unexpected params %v", params)
+	}
+
+	if _, err := a.Call("getMe", 42, nil); err == nil {
+		t.Fatal("expected an error for unsupported params")
+	}
+}
+
+func TestCallFiles(t *testing.T) {
+	var (
+		params url.Values
+		upload string
+		a      = newTestServerAPI(t, nil, func(w http.ResponseWriter, r *http.Request) {
+			params = r.URL.Query()
+			if f, _, err := r.FormFile("photo"); err == nil {
+				data, _ := io.ReadAll(f)
+				upload = string(data)
+			}
+			w.Write([]byte(`{"ok":true,"result":true}`))
+		})
+	)
+
+	ok, err := Call[bool](a, "setChatPhoto", url.Values{"chat_id": {"42"}}, map[string]InputFile{
+		"photo": NewInputFileBytes("photo.jpg", []byte("jpeg")),
+		"thumb": NewInputFileID("file_id"),
+	})
+	if err != nil || !ok {
+		t.Fatalf("unexpected result %v, %v", ok, err)
+	}
+	if upload != "jpeg" || params.Get("thumb") != "file_id" || params.Get("chat_id") != "42" {
+		t.Fatalf("unexpected request %v %q", params, upload)
+	}
+}
+
+func TestCallError(t *testing.T) {
+	a := newTestServerAPI(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":false,"error_code":404,"description":"Not Found"}`))
+	})
+
+	_, err := Call[User](a, "brandNewMethod", nil, nil)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode() != 404 {
+		t.Fatalf("expected a 404 API error, got %v", err)
+	}
+}
@@ -0,0 +1,820 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package echotron
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// ChatID identifies a chat either by its unique identifier or, for supergroups
+// and channels, by its username in the format @username.
+type ChatID struct {
+	username string
+	id       int64
+}
+
+// NewChatID returns the ChatID of the chat with the given unique identifier.
+func NewChatID(id int64) ChatID {
+	return ChatID{id: id}
+}
+
+// NewChatUsername returns the ChatID of the supergroup or channel with the given username,
+// the leading @ is added if missing.
+func NewChatUsername(username string) ChatID {
+	if !strings.HasPrefix(username, "@") {
+		username = "@" + username
+	}
+	return ChatID{username: username}
+}
+
+// ID returns the unique identifier of the chat, it's 0 if the ChatID is a username.
+func (c ChatID) ID() int64 {
+	return c.id
+}
+
+// Username returns the username of the chat, it's empty if the ChatID is a unique identifier.
+func (c ChatID) Username() string {
+	return c.username
+}
+
+// String returns the ChatID as it's sent to the Telegram Bot API.
+func (c ChatID) String() string {
+	if c.username != "" {
+		return c.username
+	}
+	return itoa(c.id)
+}
+
+// MarshalJSON encodes the ChatID as a number or as a string if it's a username.
+func (c ChatID) MarshalJSON() ([]byte, error) {
+	if c.username != "" {
+		return json.Marshal(c.username)
+	}
+	return json.Marshal(c.id)
+}
+
+// UnmarshalJSON decodes the ChatID from either a number or a string.
+func (c *ChatID) UnmarshalJSON(data []byte) error {
+	var username string
+
+	if err := json.Unmarshal(data, &username); err == nil {
+		*c = NewChatUsername(username)
+		return nil
+	}
+
+	var id int64
+	if err := json.Unmarshal(data, &id); err != nil {
+		return err
+	}
+	*c = NewChatID(id)
+	return nil
+}
+
+// ChatAPI contains the methods of the API which target a chat, bound to the chat
+// passed to the Chat method of the API.
+// Since the chat is identified by a ChatID, it allows to call them with the username
+// of a supergroup or a channel, eg:
+//
+//	api.Chat(echotron.NewChatUsername("@mychannel")).SendPhoto(photo, nil)
+type ChatAPI struct {
+	api  API
+	chat ChatID
+}
+
+// Chat returns the ChatAPI bound to the given chat.
+func (a API) Chat(chat ChatID) ChatAPI {
+	return ChatAPI{api: a, chat: chat}
+}
+
+// ChatID returns the identifier of the chat the ChatAPI is bound to.
+func (c ChatAPI) ChatID() ChatID {
+	return c.chat
+}
+
+// SendMessage is used to send text messages to the chat.
+func (c ChatAPI) SendMessage(text string, opts *MessageOptions) (res APIResponseMessage, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("text", text)
+	vals.Set("chat_id", c.chat.String())
+	return get[APIResponseMessage](c.api, "sendMessage", addValues(vals, opts))
+}
+
+// ForwardMessage is used to forward to the chat messages of any kind.
+// Service messages can't be forwarded.
+func (c ChatAPI) ForwardMessage(fromChatID int64, messageID int, opts *ForwardOptions) (res APIResponseMessage, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("from_chat_id", itoa(fromChatID))
+	vals.Set("message_id", itoa(int64(messageID)))
+	return get[APIResponseMessage](c.api, "forwardMessage", addValues(vals, opts))
+}
+
+// ForwardMessages is used to forward multiple messages of any kind to the chat.
+func (c ChatAPI) ForwardMessages(fromChatID int64, messageIDs []int, opts *ForwardMessagesOptions) (res APIResponseMessageIDArray, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("from_chat_id", itoa(fromChatID))
+	vals.Set("message_ids", jsonString(messageIDs))
+	return get[APIResponseMessageIDArray](c.api, "forwardMessages", addValues(vals, opts))
+}
+
+// CopyMessage is used to copy messages of any kind to the chat.
+func (c ChatAPI) CopyMessage(fromChatID int64, messageID int, opts *CopyOptions) (res APIResponseMessageID, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("from_chat_id", itoa(fromChatID))
+	vals.Set("message_id", itoa(int64(messageID)))
+	return get[APIResponseMessageID](c.api, "copyMessage", addValues(vals, opts))
+}
+
+// CopyMessages is used to copy messages of any kind to the chat.
+func (c ChatAPI) CopyMessages(fromChatID int64, messageIDs []int, opts *CopyMessagesOptions) (res APIResponseMessageIDArray, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("from_chat_id", itoa(fromChatID))
+	vals.Set("message_ids", jsonString(messageIDs))
+	return get[APIResponseMessageIDArray](c.api, "copyMessages", addValues(vals, opts))
+}
+
+// SendPhoto is used to send photos to the chat.
+func (c ChatAPI) SendPhoto(file InputFile, opts *PhotoOptions) (res APIResponseMessage, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	return postFile[APIResponseMessage](c.api, "sendPhoto", "photo", file, InputFile{}, addValues(vals, opts))
+}
+
+// SendAudio is used to send audio files to the chat.
+func (c ChatAPI) SendAudio(file InputFile, opts *AudioOptions) (res APIResponseMessage, err error) {
+	var (
+		thumbnail InputFile
+		vals      = make(url.Values)
+	)
+
+	if opts != nil {
+		thumbnail = opts.Thumbnail
+	}
+
+	vals.Set("chat_id", c.chat.String())
+	return postFile[APIResponseMessage](c.api, "sendAudio", "audio", file, thumbnail, addValues(vals, opts))
+}
+
+// SendDocument is used to send general files to the chat.
+func (c ChatAPI) SendDocument(file InputFile, opts *DocumentOptions) (res APIResponseMessage, err error) {
+	var (
+		thumbnail InputFile
+		vals      = make(url.Values)
+	)
+
+	if opts != nil {
+		thumbnail = opts.Thumbnail
+	}
+
+	vals.Set("chat_id", c.chat.String())
+	return postFile[APIResponseMessage](c.api, "sendDocument", "document", file, thumbnail, addValues(vals, opts))
+}
+
+// SendVideo is used to send video files to the chat.
+func (c ChatAPI) SendVideo(file InputFile, opts *VideoOptions) (res APIResponseMessage, err error) {
+	var (
+		thumbnail InputFile
+		vals      = make(url.Values)
+	)
+
+	if opts != nil {
+		thumbnail = opts.Thumbnail
+	}
+
+	vals.Set("chat_id", c.chat.String())
+	return postFile[APIResponseMessage](c.api, "sendVideo", "video", file, thumbnail, addValues(vals, opts))
+}
+
+// SendAnimation is used to send animation files to the chat.
+func (c ChatAPI) SendAnimation(file InputFile, opts *AnimationOptions) (res APIResponseMessage, err error) {
+	var (
+		thumbnail InputFile
+		vals      = make(url.Values)
+	)
+
+	if opts != nil {
+		thumbnail = opts.Thumbnail
+	}
+
+	vals.Set("chat_id", c.chat.String())
+	return postFile[APIResponseMessage](c.api, "sendAnimation", "animation", file, thumbnail, addValues(vals, opts))
+}
+
+// SendVoice is used to send audio files to the chat, if you want Telegram clients to display the file as a playable voice message.
+func (c ChatAPI) SendVoice(file InputFile, opts *VoiceOptions) (res APIResponseMessage, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	return postFile[APIResponseMessage](c.api, "sendVoice", "voice", file, InputFile{}, addValues(vals, opts))
+}
+
+// SendVideoNote is used to send video messages to the chat.
+func (c ChatAPI) SendVideoNote(file InputFile, opts *VideoNoteOptions) (res APIResponseMessage, err error) {
+	var (
+		thumbnail InputFile
+		vals      = make(url.Values)
+	)
+
+	if opts != nil {
+		thumbnail = opts.Thumbnail
+	}
+
+	vals.Set("chat_id", c.chat.String())
+	return postFile[APIResponseMessage](c.api, "sendVideoNote", "video_note", file, thumbnail, addValues(vals, opts))
+}
+
+// SendMediaGroup is used to send a group of photos, videos, documents or audios as an album to the chat.
+func (c ChatAPI) SendMediaGroup(media []GroupableInputMedia, opts *MediaGroupOptions) (res APIResponseMessageArray, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	return postMedia[APIResponseMessageArray](c.api, "sendMediaGroup", false, addValues(vals, opts), toInputMedia(media)...)
+}
+
+// SendLocation is used to send a point on the map to the chat.
+func (c ChatAPI) SendLocation(latitude, longitude float64, opts *LocationOptions) (res APIResponseMessage, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("latitude", ftoa(latitude))
+	vals.Set("longitude", ftoa(longitude))
+	return get[APIResponseMessage](c.api, "sendLocation", addValues(vals, opts))
+}
+
+// EditMessageLiveLocation is used to edit live location messages sent in the chat.
+func (c ChatAPI) EditMessageLiveLocation(messageID int, latitude, longitude float64, opts *EditLocationOptions) (res APIResponseMessage, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("message_id", itoa(int64(messageID)))
+	vals.Set("latitude", ftoa(latitude))
+	vals.Set("longitude", ftoa(longitude))
+	return get[APIResponseMessage](c.api, "editMessageLiveLocation", addValues(vals, opts))
+}
+
+// StopMessageLiveLocation is used to stop updating a live location message sent in the chat before live_period expires.
+func (c ChatAPI) StopMessageLiveLocation(messageID int, opts *MessageReplyMarkup) (res APIResponseMessage, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("message_id", itoa(int64(messageID)))
+	return get[APIResponseMessage](c.api, "stopMessageLiveLocation", addValues(vals, opts))
+}
+
+// SendVenue is used to send information about a venue to the chat.
+func (c ChatAPI) SendVenue(latitude, longitude float64, title, address string, opts *VenueOptions) (res APIResponseMessage, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("latitude", ftoa(latitude))
+	vals.Set("longitude", ftoa(longitude))
+	vals.Set("title", title)
+	vals.Set("address", address)
+	return get[APIResponseMessage](c.api, "sendVenue", addValues(vals, opts))
+}
+
+// SendContact is used to send phone contacts to the chat.
+func (c ChatAPI) SendContact(phoneNumber, firstName string, opts *ContactOptions) (res APIResponseMessage, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("phone_number", phoneNumber)
+	vals.Set("first_name", firstName)
+	return get[APIResponseMessage](c.api, "sendContact", addValues(vals, opts))
+}
+
+// SendPoll is used to send a native poll to the chat.
+func (c ChatAPI) SendPoll(question string, options []string, opts *PollOptions) (res APIResponseMessage, err error) {
+	var vals = make(url.Values)
+
+	pollOpts, err := json.Marshal(options)
+	if err != nil {
+		return res, err
+	}
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("question", question)
+	vals.Set("options", string(pollOpts))
+	return get[APIResponseMessage](c.api, "sendPoll", addValues(vals, opts))
+}
+
+// SendDice is used to send an animated emoji that will display a random value to the chat.
+func (c ChatAPI) SendDice(emoji DiceEmoji, opts *BaseOptions) (res APIResponseMessage, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("emoji", string(emoji))
+	return get[APIResponseMessage](c.api, "sendDice", addValues(vals, opts))
+}
+
+// SendChatAction is used to tell the users of the chat that something is happening on the bot's side.
+func (c ChatAPI) SendChatAction(action ChatAction, opts *ChatActionOptions) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("action", string(action))
+	return get[APIResponseBool](c.api, "sendChatAction", addValues(vals, opts))
+}
+
+// SetMessageReaction is used to change the chosen reactions on a message of the chat.
+func (c ChatAPI) SetMessageReaction(messageID int, opts *SetMessageReactionOptions) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("message_id", itoa(int64(messageID)))
+	return get[APIResponseBool](c.api, "setMessageReaction", addValues(vals, opts))
+}
+
+// SendSticker is used to send static .WEBP, animated .TGS, or video .WEBM stickers to the chat.
+func (c ChatAPI) SendSticker(stickerID string, opts *StickerOptions) (res APIResponseMessage, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("sticker", stickerID)
+	vals.Set("chat_id", c.chat.String())
+	return get[APIResponseMessage](c.api, "sendSticker", addValues(vals, opts))
+}
+
+// SendGame is used to send a Game to the chat.
+func (c ChatAPI) SendGame(gameShortName string, opts *BaseOptions) (res APIResponseMessage, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("game_short_name", gameShortName)
+	return get[APIResponseMessage](c.api, "sendGame", addValues(vals, opts))
+}
+
+// SendInvoice is used to send invoices to the chat.
+func (c ChatAPI) SendInvoice(title, description, payload, providerToken, currency string, prices []LabeledPrice, opts *InvoiceOptions) (res APIResponseMessage, err error) {
+	var vals = make(url.Values)
+
+	p, err := json.Marshal(prices)
+	if err != nil {
+		return res, err
+	}
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("title", title)
+	vals.Set("description", description)
+	vals.Set("payload", payload)
+	vals.Set("provider_token", providerToken)
+	vals.Set("currency", currency)
+	vals.Set("prices", string(p))
+	return get[APIResponseMessage](c.api, "sendInvoice", addValues(vals, opts))
+}
+
+// BanChatMember is used to ban a user from the chat.
+func (c ChatAPI) BanChatMember(userID int64, opts *BanOptions) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("user_id", itoa(userID))
+	return get[APIResponseBool](c.api, "banChatMember", addValues(vals, opts))
+}
+
+// UnbanChatMember is used to unban a previously banned user in the chat.
+func (c ChatAPI) UnbanChatMember(userID int64, opts *UnbanOptions) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("user_id", itoa(userID))
+	return get[APIResponseBool](c.api, "unbanChatMember", addValues(vals, opts))
+}
+
+// RestrictChatMember is used to restrict a user in the chat.
+func (c ChatAPI) RestrictChatMember(userID int64, permissions ChatPermissions, opts *RestrictOptions) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	perm, err := serializePerms(permissions)
+	if err != nil {
+		return
+	}
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("user_id", itoa(userID))
+	vals.Set("permissions", perm)
+	return get[APIResponseBool](c.api, "restrictChatMember", addValues(vals, opts))
+}
+
+// PromoteChatMember is used to promote or demote a user in the chat.
+func (c ChatAPI) PromoteChatMember(userID int64, opts *PromoteOptions) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("user_id", itoa(userID))
+	return get[APIResponseBool](c.api, "promoteChatMember", addValues(vals, opts))
+}
+
+// SetChatAdministratorCustomTitle is used to set a custom title for an administrator of the chat promoted by the bot.
+func (c ChatAPI) SetChatAdministratorCustomTitle(userID int64, customTitle string) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("user_id", itoa(userID))
+	vals.Set("custom_title", customTitle)
+	return get[APIResponseBool](c.api, "setChatAdministratorCustomTitle", vals)
+}
+
+// BanChatSenderChat is used to ban a channel chat in the chat.
+func (c ChatAPI) BanChatSenderChat(senderChatID int64) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("sender_chat_id", itoa(senderChatID))
+	return get[APIResponseBool](c.api, "banChatSenderChat", vals)
+}
+
+// UnbanChatSenderChat is used to unban a previously banned channel chat in the chat.
+func (c ChatAPI) UnbanChatSenderChat(senderChatID int64) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("sender_chat_id", itoa(senderChatID))
+	return get[APIResponseBool](c.api, "unbanChatSenderChat", vals)
+}
+
+// SetChatPermissions is used to set default chat permissions for all members of the chat.
+func (c ChatAPI) SetChatPermissions(permissions ChatPermissions, opts *ChatPermissionsOptions) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	perm, err := serializePerms(permissions)
+	if err != nil {
+		return
+	}
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("permissions", perm)
+	return get[APIResponseBool](c.api, "setChatPermissions", addValues(vals, opts))
+}
+
+// ExportChatInviteLink is used to generate a new primary invite link for the chat.
+func (c ChatAPI) ExportChatInviteLink() (res APIResponseString, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	return get[APIResponseString](c.api, "exportChatInviteLink", vals)
+}
+
+// CreateChatInviteLink is used to create an additional invite link for the chat.
+func (c ChatAPI) CreateChatInviteLink(opts *InviteLinkOptions) (res APIResponseInviteLink, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	return get[APIResponseInviteLink](c.api, "createChatInviteLink", addValues(vals, opts))
+}
+
+// EditChatInviteLink is used to edit a non-primary invite link of the chat created by the bot.
+func (c ChatAPI) EditChatInviteLink(inviteLink string, opts *InviteLinkOptions) (res APIResponseInviteLink, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("invite_link", inviteLink)
+	return get[APIResponseInviteLink](c.api, "editChatInviteLink", addValues(vals, opts))
+}
+
+// RevokeChatInviteLink is used to revoke an invite link of the chat created by the bot.
+func (c ChatAPI) RevokeChatInviteLink(inviteLink string) (res APIResponseInviteLink, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("invite_link", inviteLink)
+	return get[APIResponseInviteLink](c.api, "revokeChatInviteLink", vals)
+}
+
+// ApproveChatJoinRequest is used to approve a join request to the chat.
+func (c ChatAPI) ApproveChatJoinRequest(userID int64) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("user_id", itoa(userID))
+	return get[APIResponseBool](c.api, "approveChatJoinRequest", vals)
+}
+
+// DeclineChatJoinRequest is used to decline a join request to the chat.
+func (c ChatAPI) DeclineChatJoinRequest(userID int64) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("user_id", itoa(userID))
+	return get[APIResponseBool](c.api, "declineChatJoinRequest", vals)
+}
+
+// SetChatPhoto is used to set a new profile photo for the chat.
+func (c ChatAPI) SetChatPhoto(file InputFile) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	return postFile[APIResponseBool](c.api, "setChatPhoto", "photo", file, InputFile{}, vals)
+}
+
+// DeleteChatPhoto is used to delete the profile photo of the chat.
+func (c ChatAPI) DeleteChatPhoto() (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	return get[APIResponseBool](c.api, "deleteChatPhoto", vals)
+}
+
+// SetChatTitle is used to change the title of the chat.
+func (c ChatAPI) SetChatTitle(title string) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("title", title)
+	return get[APIResponseBool](c.api, "setChatTitle", vals)
+}
+
+// SetChatDescription is used to change the description of the chat.
+func (c ChatAPI) SetChatDescription(description string) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("description", description)
+	return get[APIResponseBool](c.api, "setChatDescription", vals)
+}
+
+// PinChatMessage is used to add a message to the list of pinned messages in the chat.
+func (c ChatAPI) PinChatMessage(messageID int, opts *PinMessageOptions) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("message_id", itoa(int64(messageID)))
+	return get[APIResponseBool](c.api, "pinChatMessage", addValues(vals, opts))
+}
+
+// UnpinChatMessage is used to remove a message from the list of pinned messages in the chat.
+func (c ChatAPI) UnpinChatMessage(messageID int) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("message_id", itoa(int64(messageID)))
+	return get[APIResponseBool](c.api, "unpinChatMessage", vals)
+}
+
+// UnpinAllChatMessages is used to clear the list of pinned messages in the chat.
+func (c ChatAPI) UnpinAllChatMessages() (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	return get[APIResponseBool](c.api, "unpinAllChatMessages", vals)
+}
+
+// LeaveChat is used to make the bot leave the chat.
+func (c ChatAPI) LeaveChat() (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	return get[APIResponseBool](c.api, "leaveChat", vals)
+}
+
+// GetChat is used to get up to date information about the chat.
+func (c ChatAPI) GetChat() (res APIResponseChat, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	return get[APIResponseChat](c.api, "getChat", vals)
+}
+
+// GetChatAdministrators is used to get a list of administrators in the chat, which aren't bots.
+func (c ChatAPI) GetChatAdministrators() (res APIResponseAdministrators, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	return get[APIResponseAdministrators](c.api, "getChatAdministrators", vals)
+}
+
+// GetChatMemberCount is used to get the number of members in the chat.
+func (c ChatAPI) GetChatMemberCount() (res APIResponseInteger, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	return get[APIResponseInteger](c.api, "getChatMemberCount", vals)
+}
+
+// GetChatMember is used to get information about a member of the chat.
+func (c ChatAPI) GetChatMember(userID int64) (res APIResponseChatMember, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("user_id", itoa(userID))
+	return get[APIResponseChatMember](c.api, "getChatMember", vals)
+}
+
+// GetUserChatBoosts is used to get the list of boosts added to the chat by a user.
+func (c ChatAPI) GetUserChatBoosts(userID int64) (res APIResponseUserChatBoosts, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("user_id", itoa(userID))
+	return get[APIResponseUserChatBoosts](c.api, "getUserChatBoosts", vals)
+}
+
+// SetChatStickerSet is used to set a new group sticker set for the chat.
+func (c ChatAPI) SetChatStickerSet(stickerSetName string) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("sticker_set_name", stickerSetName)
+	return get[APIResponseBool](c.api, "setChatStickerSet", vals)
+}
+
+// DeleteChatStickerSet is used to delete the group sticker set of the chat.
+func (c ChatAPI) DeleteChatStickerSet() (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	return get[APIResponseBool](c.api, "deleteChatStickerSet", vals)
+}
+
+// CreateForumTopic is used to create a topic in the chat.
+func (c ChatAPI) CreateForumTopic(name string, opts *CreateTopicOptions) (res APIResponseForumTopic, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("name", name)
+	return get[APIResponseForumTopic](c.api, "createForumTopic", addValues(vals, opts))
+}
+
+// EditForumTopic is used to edit the name and icon of a topic in the chat.
+func (c ChatAPI) EditForumTopic(messageThreadID int64, opts *EditTopicOptions) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("message_thread_id", itoa(messageThreadID))
+	return get[APIResponseBool](c.api, "editForumTopic", addValues(vals, opts))
+}
+
+// CloseForumTopic is used to close an open topic in the chat.
+func (c ChatAPI) CloseForumTopic(messageThreadID int64) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("message_thread_id", itoa(messageThreadID))
+	return get[APIResponseBool](c.api, "closeForumTopic", vals)
+}
+
+// ReopenForumTopic is used to reopen a closed topic in the chat.
+func (c ChatAPI) ReopenForumTopic(messageThreadID int64) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("message_thread_id", itoa(messageThreadID))
+	return get[APIResponseBool](c.api, "reopenForumTopic", vals)
+}
+
+// DeleteForumTopic is used to delete a topic in the chat along with all its messages.
+func (c ChatAPI) DeleteForumTopic(messageThreadID int64) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("message_thread_id", itoa(messageThreadID))
+	return get[APIResponseBool](c.api, "deleteForumTopic", vals)
+}
+
+// UnpinAllForumTopicMessages is used to clear the list of pinned messages in a topic of the chat.
+func (c ChatAPI) UnpinAllForumTopicMessages(messageThreadID int64) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("message_thread_id", itoa(messageThreadID))
+	return get[APIResponseBool](c.api, "unpinAllForumTopicMessages", vals)
+}
+
+// EditGeneralForumTopic is used to edit the name of the 'General' topic in the chat.
+func (c ChatAPI) EditGeneralForumTopic(name string) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("name", name)
+	return get[APIResponseBool](c.api, "editGeneralForumTopic", vals)
+}
+
+// CloseGeneralForumTopic is used to close an open 'General' topic in the chat.
+func (c ChatAPI) CloseGeneralForumTopic() (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	return get[APIResponseBool](c.api, "closeGeneralForumTopic", vals)
+}
+
+// ReopenGeneralForumTopic is used to reopen a closed 'General' topic in the chat.
+func (c ChatAPI) ReopenGeneralForumTopic() (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	return get[APIResponseBool](c.api, "reopenGeneralForumTopic", vals)
+}
+
+// HideGeneralForumTopic is used to hide the 'General' topic in the chat.
+func (c ChatAPI) HideGeneralForumTopic() (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	return get[APIResponseBool](c.api, "hideGeneralForumTopic", vals)
+}
+
+// UnhideGeneralForumTopic is used to unhide the 'General' topic in the chat.
+func (c ChatAPI) UnhideGeneralForumTopic() (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	return get[APIResponseBool](c.api, "unhideGeneralForumTopic", vals)
+}
+
+// UnpinAllGeneralForumTopicMessages is used to clear the list of pinned messages in the 'General' topic of the chat.
+func (c ChatAPI) UnpinAllGeneralForumTopicMessages() (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	return get[APIResponseBool](c.api, "unpinAllGeneralForumTopicMessages", vals)
+}
+
+// EditMessageText is used to edit text and game messages sent in the chat.
+func (c ChatAPI) EditMessageText(text string, messageID int, opts *MessageTextOptions) (res APIResponseMessage, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("message_id", itoa(int64(messageID)))
+	vals.Set("text", text)
+	return get[APIResponseMessage](c.api, "editMessageText", addValues(vals, opts))
+}
+
+// EditMessageCaption is used to edit captions of messages sent in the chat.
+func (c ChatAPI) EditMessageCaption(messageID int, opts *MessageCaptionOptions) (res APIResponseMessage, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("message_id", itoa(int64(messageID)))
+	return get[APIResponseMessage](c.api, "editMessageCaption", addValues(vals, opts))
+}
+
+// EditMessageMedia is used to edit animation, audio, document, photo or video messages sent in the chat.
+func (c ChatAPI) EditMessageMedia(messageID int, media InputMedia, opts *MessageReplyMarkup) (res APIResponseMessage, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("message_id", itoa(int64(messageID)))
+	return postMedia[APIResponseMessage](c.api, "editMessageMedia", true, addValues(vals, opts), media)
+}
+
+// EditMessageReplyMarkup is used to edit only the reply markup of messages sent in the chat.
+func (c ChatAPI) EditMessageReplyMarkup(messageID int, opts *MessageReplyMarkup) (res APIResponseMessage, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("message_id", itoa(int64(messageID)))
+	return get[APIResponseMessage](c.api, "editMessageReplyMarkup", addValues(vals, opts))
+}
+
+// StopPoll is used to stop a poll sent by the bot in the chat.
+func (c ChatAPI) StopPoll(messageID int, opts *MessageReplyMarkup) (res APIResponsePoll, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("message_id", itoa(int64(messageID)))
+	return get[APIResponsePoll](c.api, "stopPoll", addValues(vals, opts))
+}
+
+// DeleteMessage is used to delete a message of the chat, see the DeleteMessage method of the API for its limitations.
+func (c ChatAPI) DeleteMessage(messageID int) (res APIResponseBase, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("message_id", itoa(int64(messageID)))
+	return get[APIResponseBase](c.api, "deleteMessage", vals)
+}
+
+// DeleteMessages is used to delete multiple messages of the chat simultaneously.
+func (c ChatAPI) DeleteMessages(messageIDs []int) (res APIResponseBool, err error) {
+	var vals = make(url.Values)
+
+	vals.Set("chat_id", c.chat.String())
+	vals.Set("message_ids", jsonString(messageIDs))
+	return get[APIResponseBool](c.api, "deleteMessages", vals)
+}
@@ -0,0 +1,72 @@
+package echotron
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestChatID(t *testing.T) {
+	if c := NewChatUsername("mychannel"); c.String() != "@mychannel" || c.Username() != "@mychannel" || c.ID() != 0 {
+		t.Fatalf("unexpected chat %+v", c)
+	}
+	if c := NewChatID(-1001); c.String() != "-1001" || c.Username() != "" || c.ID() != -1001 {
+		t.Fatalf("unexpected chat %+v", c)
+	}
+
+	for _, c := range []ChatID{NewChatID(42), NewChatUsername("@mychannel")} {
+		data, err := json.Marshal(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got ChatID
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got != c {
+			t.Fatalf("expected %+v, got %+v from %s", c, got, data)
+		}
+	}
+
+	if p := paramString(NewChatUsername("mychannel")); p != "@mychannel" {
+		t.Fatalf("unexpected param %q", p)
+	}
+}
+
+func TestChatAPI(t *testing.T) {
+	var (
+		params []url.Values
+		a      = newTestServerAPI(t, nil, func(w http.ResponseWriter, r *http.Request) {
+			r.ParseMultipartForm(1 << 20)
+			params = append(params, r.URL.Query())
+			w.Write([]byte(`{"ok":true,"result":true}`))
+		})
+		chat = a.Chat(NewChatUsername("mychannel"))
+	)
+
+	if chat.ChatID() != NewChatUsername("@mychannel") {
+		t.Fatalf("unexpected chat %v", chat.ChatID())
+	}
+
+	chat.SendPhoto(NewInputFileBytes("photo.jpg", []byte("photo")), nil)
+	chat.EditMessageText("hello", 10, nil)
+	chat.PinChatMessage(10, nil)
+	chat.ForwardMessage(42, 7, nil)
+	a.SendMessage("hello", 42, nil)
+	chat.EditMessageCaption(11, nil)
+
+	expected := []string{"@mychannel", "@mychannel", "@mychannel", "@mychannel", "42", "@mychannel"}
+	if len(params) != len(expected) {
+		t.Fatalf("expected %d calls, got %d", len(expected), len(params))
+	}
+	for i, p := range params {
+		if p.Get("chat_id") != expected[i] {
+			t.Fatalf("call %d: expected chat_id %q, got %v", i, expected[i], p)
+		}
+	}
+	if params[1].Get("message_id") != "10" || params[3].Get("from_chat_id") != "42" || params[5].Get("message_id") != "11" {
+		t.Fatalf("unexpected params %v", params)
+	}
+}
@@ -52,10 +52,10 @@ type GetMyDefaultAdministratorRightsOptions struct {
 // These rights will be suggested to users, but they are are free to modify the list
 // before adding the bot.
 func (a API) SetMyDefaultAdministratorRights(opts SetMyDefaultAdministratorRightsOptions) (res APIResponseBool, err error) {
-	return get[APIResponseBool](a.base, "setMyDefaultAdministratorRights", urlValues(opts))
+	return get[APIResponseBool](a, "setMyDefaultAdministratorRights", urlValues(opts))
 }
 
 // GetMyDefaultAdministratorRights is used to get the current default administrator rights of the bot.
 func (a API) GetMyDefaultAdministratorRights(opts GetMyDefaultAdministratorRightsOptions) (res APIResponseChatAdministratorRights, err error) {
-	return get[APIResponseChatAdministratorRights](a.base, "getMyDefaultAdministratorRights", urlValues(opts))
+	return get[APIResponseChatAdministratorRights](a, "getMyDefaultAdministratorRights", urlValues(opts))
 }
@@ -0,0 +1,497 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Command echotron is a command-line tool for the routine administration of a Telegram bot.
+//
+// The token of the bot is read from the ECHOTRON_TOKEN environment variable, or from the file
+// passed with -token-file, so that it never ends up in the shell history.
+//
+// Usage:
+//
+//	echotron [-token-file path] [-base-url url] <command> [arguments]
+//
+// The commands are:
+//
+//	me                                    print the bot user
+//	webhook info                          print the current webhook status
+//	webhook set [flags] <url>             set the webhook, see 'echotron webhook set -h'
+//	webhook delete [-drop-pending]        delete the webhook
+//	send message <chat_id> <text>         send a text message
+//	send file [-caption text] <chat_id> <path>
+//	                                      send a file as a document
+//	download [-o path] <file_id>          download a file, to stdout by default
+//	config dump [-lang codes]             print the commands, name, descriptions and menu button of the bot
+//	config apply <path>                   apply a configuration printed by 'config dump', '-' reads stdin
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/NicoNex/echotron/v3"
+)
+
+// defaultLanguage is the key of the configuration used for the users whose language
+// has no dedicated one.
+const defaultLanguage = "default"
+
+var errUsage = errors.New("invalid usage")
+
+// tokenRegexp matches the token of the bot in the URLs of the Bot API, which end up in
+// the errors of the HTTP client.
+var tokenRegexp = regexp.MustCompile(`/bot[0-9]+:[A-Za-z0-9_-]+`)
+
+// botConfig is the configuration of the bot read and written by the config commands.
+type botConfig struct {
+	MenuButton *echotron.MenuButton       `json:"menu_button,omitempty"`
+	Languages  map[string]*languageConfig `json:"languages"`
+}
+
+// languageConfig is the configuration of the bot for the users with a given language.
+type languageConfig struct {
+	Name             string                `json:"name"`
+	Description      string                `json:"description"`
+	ShortDescription string                `json:"short_description"`
+	Commands         []echotron.BotCommand `json:"commands"`
+}
+
+// cli contains the state shared by the commands.
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+	api    echotron.API
+	token  string
+}
+
+func main() {
+	c := &cli{
+		stdin:  os.Stdin,
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+		getenv: os.Getenv,
+	}
+
+	if err := c.run(os.Args[1:]); err != nil {
+		c.report(err)
+		os.Exit(1)
+	}
+}
+
+// report prints the error returned by run, without the token of the bot.
+func (c *cli) report(err error) {
+	if errors.Is(err, errUsage) || errors.Is(err, flag.ErrHelp) {
+		return
+	}
+
+	msg := tokenRegexp.ReplaceAllString(err.Error(), "/bot<token>")
+	if c.token != "" {
+		msg = strings.ReplaceAll(msg, c.token, "<token>")
+	}
+	fmt.Fprintln(c.stderr, "echotron:", msg)
+}
+
+func (c *cli) usage(fs *flag.FlagSet, args string) func() {
+	return func() {
+		fmt.Fprintf(c.stderr, "Usage: echotron %s %s\n", fs.Name(), args)
+		fs.PrintDefaults()
+	}
+}
+
+// parse parses the flags of a command and checks that it received n positional arguments.
+func (c *cli) parse(fs *flag.FlagSet, args []string, n int) error {
+	fs.SetOutput(c.stderr)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != n {
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+func (c *cli) run(args []string) error {
+	var (
+		fs        = flag.NewFlagSet("echotron", flag.ContinueOnError)
+		tokenFile = fs.String("token-file", "", "read the token of the bot from the given file instead of $ECHOTRON_TOKEN")
+		baseURL   = fs.String("base-url", c.getenv("ECHOTRON_BASE_URL"), "the base URL of the Bot API server (default "+echotron.DefaultBaseURL+")")
+	)
+
+	fs.SetOutput(c.stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(c.stderr, "Usage: echotron [flags] <me|webhook|send|download|config> [arguments]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	token := c.getenv("ECHOTRON_TOKEN")
+	if *tokenFile != "" {
+		data, err := os.ReadFile(*tokenFile)
+		if err != nil {
+			return err
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	if token == "" {
+		return errors.New("missing token, set $ECHOTRON_TOKEN or use -token-file")
+	}
+	c.token = token
+	c.api = echotron.NewAPIOptions(token, &echotron.APIOptions{BaseURL: *baseURL})
+
+	cmd, args := fs.Arg(0), fs.Args()[1:]
+	switch cmd {
+	case "me":
+		res, err := c.api.GetMe()
+		return c.print(res.Result, err)
+	case "webhook":
+		return c.webhook(args)
+	case "send":
+		return c.send(args)
+	case "download":
+		return c.download(args)
+	case "config":
+		return c.config(args)
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown command %q", cmd)
+	}
+}
+
+// print prints v as indented JSON, unless err is not nil.
+func (c *cli) print(v any, err error) error {
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(c.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// subcommand returns the name and the arguments of the subcommand of cmd.
+func subcommand(cmd string, args []string, names ...string) (string, []string, error) {
+	if len(args) > 0 {
+		for _, n := range names {
+			if args[0] == n {
+				return n, args[1:], nil
+			}
+		}
+	}
+	return "", nil, fmt.Errorf("usage: echotron %s <%s>", cmd, strings.Join(names, "|"))
+}
+
+func (c *cli) webhook(args []string) error {
+	sub, args, err := subcommand("webhook", args, "info", "set", "delete")
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "info":
+		if err := c.parse(flag.NewFlagSet("webhook info", flag.ContinueOnError), args, 0); err != nil {
+			return err
+		}
+		res, err := c.api.GetWebhookInfo()
+		return c.print(res.Result, err)
+
+	case "set":
+		var (
+			fs      = flag.NewFlagSet("webhook set", flag.ContinueOnError)
+			secret  = fs.String("secret", c.getenv("ECHOTRON_WEBHOOK_SECRET"), "the secret token sent by Telegram in each request, $ECHOTRON_WEBHOOK_SECRET by default")
+			cert    = fs.String("cert", "", "the path of the public key certificate to upload, for self-signed certificates")
+			ip      = fs.String("ip", "", "the fixed IP address Telegram sends the updates to instead of resolving the host")
+			maxConn = fs.Int("max-connections", 0, "the maximum number of simultaneous connections, between 1 and 100")
+			allowed = fs.String("allowed-updates", "", "the comma-separated list of the update types to receive, eg: message,callback_query")
+			drop    = fs.Bool("drop-pending", false, "drop the pending updates")
+		)
+
+		fs.Usage = c.usage(fs, "[flags] <url>")
+		if err := c.parse(fs, args, 1); err != nil {
+			return err
+		}
+
+		opts := &echotron.WebhookOptions{
+			IPAddress:      *ip,
+			SecretToken:    *secret,
+			MaxConnections: *maxConn,
+		}
+		if *cert != "" {
+			opts.Certificate = echotron.NewInputFilePath(*cert)
+		}
+		if *allowed != "" {
+			for _, t := range strings.Split(*allowed, ",") {
+				opts.AllowedUpdates = append(opts.AllowedUpdates, echotron.UpdateType(strings.TrimSpace(t)))
+			}
+		}
+
+		res, err := c.api.SetWebhook(fs.Arg(0), *drop, opts)
+		return c.print(res, err)
+
+	default:
+		var (
+			fs   = flag.NewFlagSet("webhook delete", flag.ContinueOnError)
+			drop = fs.Bool("drop-pending", false, "drop the pending updates")
+		)
+
+		fs.Usage = c.usage(fs, "[-drop-pending]")
+		if err := c.parse(fs, args, 0); err != nil {
+			return err
+		}
+		res, err := c.api.DeleteWebhook(*drop)
+		return c.print(res, err)
+	}
+}
+
+func (c *cli) send(args []string) error {
+	sub, args, err := subcommand("send", args, "message", "file")
+	if err != nil {
+		return err
+	}
+
+	if sub == "message" {
+		fs := flag.NewFlagSet("send message", flag.ContinueOnError)
+		fs.Usage = c.usage(fs, "<chat_id> <text>")
+		if err := c.parse(fs, args, 2); err != nil {
+			return err
+		}
+
+		chatID, err := strconv.ParseInt(fs.Arg(0), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid chat_id %q", fs.Arg(0))
+		}
+		res, err := c.api.SendMessage(fs.Arg(1), chatID, nil)
+		return c.print(res.Result, err)
+	}
+
+	var (
+		fs      = flag.NewFlagSet("send file", flag.ContinueOnError)
+		caption = fs.String("caption", "", "the caption of the file")
+	)
+
+	fs.Usage = c.usage(fs, "[-caption text] <chat_id> <path>")
+	if err := c.parse(fs, args, 2); err != nil {
+		return err
+	}
+
+	chatID, err := strconv.ParseInt(fs.Arg(0), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid chat_id %q", fs.Arg(0))
+	}
+	if _, err := os.Stat(fs.Arg(1)); err != nil {
+		return err
+	}
+
+	res, err := c.api.SendDocument(echotron.NewInputFilePath(fs.Arg(1)), chatID, &echotron.DocumentOptions{Caption: *caption})
+	return c.print(res.Result, err)
+}
+
+func (c *cli) download(args []string) error {
+	var (
+		fs  = flag.NewFlagSet("download", flag.ContinueOnError)
+		out = fs.String("o", "", "the path the file is written to, '.' for the current directory with its original name")
+	)
+
+	fs.Usage = c.usage(fs, "[-o path] <file_id>")
+	if err := c.parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	res, err := c.api.GetFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	data, err := c.api.DownloadFile(res.Result.FilePath)
+	if err != nil {
+		return err
+	}
+
+	switch *out {
+	case "":
+		_, err = c.stdout.Write(data)
+		return err
+	case ".":
+		*out = filepath.Base(res.Result.FilePath)
+	}
+	return os.WriteFile(*out, data, 0o644)
+}
+
+func (c *cli) config(args []string) error {
+	sub, args, err := subcommand("config", args, "dump", "apply")
+	if err != nil {
+		return err
+	}
+
+	if sub == "dump" {
+		var (
+			fs    = flag.NewFlagSet("config dump", flag.ContinueOnError)
+			langs = fs.String("lang", "", "the comma-separated list of the language codes to dump besides the default one, eg: en,it")
+		)
+
+		fs.Usage = c.usage(fs, "[-lang codes]")
+		if err := c.parse(fs, args, 0); err != nil {
+			return err
+		}
+
+		codes := []string{defaultLanguage}
+		if *langs != "" {
+			codes = append(codes, strings.Split(*langs, ",")...)
+		}
+		cfg, err := c.dump(codes)
+		return c.print(cfg, err)
+	}
+
+	fs := flag.NewFlagSet("config apply", flag.ContinueOnError)
+	fs.Usage = c.usage(fs, "<path>")
+	if err := c.parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	var r = c.stdin
+	if fs.Arg(0) != "-" {
+		file, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+
+	var cfg botConfig
+	if err := json.NewDecoder(r).Decode(&cfg); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return c.apply(cfg)
+}
+
+// languageCode returns the language code used by the API for the given key of botConfig.Languages.
+func languageCode(lang string) string {
+	if lang == defaultLanguage {
+		return ""
+	}
+	return lang
+}
+
+func (c *cli) dump(langs []string) (*botConfig, error) {
+	cfg := &botConfig{Languages: make(map[string]*languageConfig)}
+
+	for _, lang := range langs {
+		var (
+			lc   languageConfig
+			code = languageCode(strings.TrimSpace(lang))
+		)
+
+		name, err := c.api.GetMyName(code)
+		if err != nil {
+			return nil, err
+		}
+		lc.Name = name.Result.Name
+
+		desc, err := c.api.GetMyDescription(code)
+		if err != nil {
+			return nil, err
+		}
+		lc.Description = desc.Result.Description
+
+		short, err := c.api.GetMyShortDescription(code)
+		if err != nil {
+			return nil, err
+		}
+		lc.ShortDescription = short.Result.ShortDescription
+
+		cmds, err := c.api.GetMyCommands(&echotron.CommandOptions{LanguageCode: code})
+		if err != nil {
+			return nil, err
+		}
+		lc.Commands = []echotron.BotCommand{}
+		for _, cmd := range cmds.Result {
+			lc.Commands = append(lc.Commands, *cmd)
+		}
+
+		cfg.Languages[strings.TrimSpace(lang)] = &lc
+	}
+
+	btn, err := c.api.GetChatMenuButton(echotron.GetChatMenuButtonOptions{})
+	if err != nil {
+		return nil, err
+	}
+	cfg.MenuButton = btn.Result
+	return cfg, nil
+}
+
+func (c *cli) apply(cfg botConfig) error {
+	langs := make([]string, 0, len(cfg.Languages))
+	for lang := range cfg.Languages {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	for _, lang := range langs {
+		var (
+			lc   = cfg.Languages[lang]
+			code = languageCode(lang)
+		)
+
+		if lc == nil {
+			continue
+		}
+		if _, err := c.api.SetMyName(lc.Name, code); err != nil {
+			return fmt.Errorf("%s: name: %w", lang, err)
+		}
+		if _, err := c.api.SetMyDescription(lc.Description, code); err != nil {
+			return fmt.Errorf("%s: description: %w", lang, err)
+		}
+		if _, err := c.api.SetMyShortDescription(lc.ShortDescription, code); err != nil {
+			return fmt.Errorf("%s: short description: %w", lang, err)
+		}
+
+		opts := &echotron.CommandOptions{LanguageCode: code}
+		if len(lc.Commands) == 0 {
+			if _, err := c.api.DeleteMyCommands(opts); err != nil {
+				return fmt.Errorf("%s: commands: %w", lang, err)
+			}
+		} else if _, err := c.api.SetMyCommands(opts, lc.Commands...); err != nil {
+			return fmt.Errorf("%s: commands: %w", lang, err)
+		}
+		fmt.Fprintf(c.stderr, "applied the configuration of the %q language\n", lang)
+	}
+
+	if cfg.MenuButton != nil {
+		if _, err := c.api.SetChatMenuButton(echotron.SetChatMenuButtonOptions{MenuButton: *cfg.MenuButton}); err != nil {
+			return fmt.Errorf("menu button: %w", err)
+		}
+		fmt.Fprintln(c.stderr, "applied the menu button")
+	}
+	return nil
+}
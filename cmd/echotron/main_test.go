@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NicoNex/echotron/v3"
+	"github.com/NicoNex/echotron/v3/echotrontest"
+)
+
+func newTestCLI(t *testing.T) (*cli, *echotrontest.Server, *bytes.Buffer) {
+	srv := echotrontest.NewServer()
+	t.Cleanup(srv.Close)
+
+	var (
+		out bytes.Buffer
+		env = map[string]string{
+			"ECHOTRON_TOKEN":    srv.Token,
+			"ECHOTRON_BASE_URL": srv.URL,
+		}
+	)
+
+	return &cli{
+		stdin:  strings.NewReader(""),
+		stdout: &out,
+		stderr: &bytes.Buffer{},
+		getenv: func(k string) string { return env[k] },
+	}, srv, &out
+}
+
+func TestMe(t *testing.T) {
+	c, srv, out := newTestCLI(t)
+
+	if err := c.run([]string{"me"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var u echotron.User
+	if err := json.Unmarshal(out.Bytes(), &u); err != nil {
+		t.Fatal(err)
+	}
+	if u.ID != srv.Bot().ID {
+		t.Fatalf("unexpected user %+v", u)
+	}
+}
+
+func TestMissingToken(t *testing.T) {
+	c, _, _ := newTestCLI(t)
+	c.getenv = func(string) string { return "" }
+
+	if err := c.run([]string{"me"}); err == nil || !strings.Contains(err.Error(), "missing token") {
+		t.Fatalf("expected a missing token error, got %v", err)
+	}
+}
+
+func TestReportHidesToken(t *testing.T) {
+	c, srv, _ := newTestCLI(t)
+	srv.Close()
+
+	err := c.run([]string{"me"})
+	if err == nil || strings.Contains(err.Error(), srv.Token) {
+		t.Fatalf("expected a transport error without the token, got %v", err)
+	}
+
+	c.report(fmt.Errorf("%w: /bot%s/getMe", err, srv.Token))
+	stderr := c.stderr.(*bytes.Buffer).String()
+	if strings.Contains(stderr, srv.Token) || !strings.Contains(stderr, "/bot<token>/getMe") {
+		t.Fatalf("the token has not been removed from %q", stderr)
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	var (
+		c, srv, out = newTestCLI(t)
+		cert        = filepath.Join(t.TempDir(), "cert.pem")
+	)
+
+	os.WriteFile(cert, []byte("-----BEGIN CERTIFICATE-----"), 0o644)
+	err := c.run([]string{"webhook", "set", "-secret", "s3cr3t", "-cert", cert, "-max-connections", "10", "https://example.com/bot"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	call := srv.CallsTo("setWebhook")[0]
+	if call.Params.Get("secret_token") != "s3cr3t" || call.Params.Get("url") != "https://example.com/bot" {
+		t.Fatalf("unexpected params %v", call.Params)
+	}
+
+	out.Reset()
+	if err := c.run([]string{"webhook", "info"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var info echotron.WebhookInfo
+	if err := json.Unmarshal(out.Bytes(), &info); err != nil {
+		t.Fatal(err)
+	}
+	if info.URL != "https://example.com/bot" || info.MaxConnections != 10 || !info.HasCustomCertificate {
+		t.Fatalf("unexpected webhook info %+v", info)
+	}
+
+	if err := c.run([]string{"webhook", "delete"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(srv.CallsTo("deleteWebhook")) != 1 {
+		t.Fatal("webhook not deleted")
+	}
+}
+
+func TestSendAndDownload(t *testing.T) {
+	var (
+		c, srv, out = newTestCLI(t)
+		path        = filepath.Join(t.TempDir(), "report.txt")
+	)
+
+	if err := c.run([]string{"send", "message", "42", "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	if msgs := srv.Messages(42); len(msgs) != 1 || msgs[0].Text != "hello" {
+		t.Fatalf("unexpected messages %+v", msgs)
+	}
+
+	os.WriteFile(path, []byte("report"), 0o644)
+	out.Reset()
+	if err := c.run([]string{"send", "file", "-caption", "daily", "42", path}); err != nil {
+		t.Fatal(err)
+	}
+
+	var msg echotron.Message
+	if err := json.Unmarshal(out.Bytes(), &msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.Document == nil || msg.Caption != "daily" {
+		t.Fatalf("unexpected message %+v", msg)
+	}
+
+	out.Reset()
+	if err := c.run([]string{"download", msg.Document.FileID}); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "report" {
+		t.Fatalf("unexpected file content %q", out.String())
+	}
+
+	if err := c.run([]string{"send", "message", "not_a_chat", "hello"}); err == nil {
+		t.Fatal("expected an invalid chat_id error")
+	}
+}
+
+func TestConfig(t *testing.T) {
+	var (
+		c, _, out = newTestCLI(t)
+		cfg       = `{
+			"menu_button": {"type": "commands"},
+			"languages": {
+				"default": {"name": "Bot", "description": "A bot", "short_description": "Bot", "commands": [{"command": "start", "description": "Start"}]},
+				"it": {"name": "Bot IT", "description": "Un bot", "short_description": "Bot", "commands": [{"command": "start", "description": "Inizia"}]}
+			}
+		}`
+	)
+
+	c.stdin = strings.NewReader(cfg)
+	if err := c.run([]string{"config", "apply", "-"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.run([]string{"config", "dump", "-lang", "it"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got, want botConfig
+	json.Unmarshal([]byte(cfg), &want)
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(want)
+	if !bytes.Equal(gotJSON, wantJSON) {
+		t.Fatalf("expected %s, got %s", wantJSON, gotJSON)
+	}
+}
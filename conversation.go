@@ -0,0 +1,398 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package echotron
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// ConversationState is the state of a conversation in a session.
+// It can be serialized to JSON in order to persist it and restore it later with Conversation.Restore.
+type ConversationState struct {
+	Deadline time.Time         `json:"deadline,omitempty"`
+	Data     map[string]string `json:"data,omitempty"`
+	State    string            `json:"state"`
+}
+
+// ConversationStep contains the handlers of a named state of a Conversation.
+type ConversationStep struct {
+	// Enter is called when the conversation moves to this state, usually to prompt the user.
+	Enter func(sessionKey int64, state *ConversationState)
+	// Validate is called before Handle, if it returns an error the update is passed to Invalid
+	// and the conversation stays in this state.
+	Validate func(update *Update) error
+	// Invalid is called with the error returned by Validate, usually to prompt the user again.
+	Invalid func(sessionKey int64, state *ConversationState, err error)
+	// Handle is called with each valid update and returns the name of the next state.
+	// Returning the current state keeps the conversation in it without calling Enter again,
+	// while returning an empty string ends the conversation.
+	Handle func(sessionKey int64, state *ConversationState, update *Update) string
+	// Timeout is the time the user has to reply once the conversation enters this state,
+	// 0 means no timeout.
+	Timeout time.Duration
+}
+
+// Conversation is a finite-state machine that drives multi-step dialogs, with a separate state for each session.
+// Once started with Begin, all the updates of a session are consumed by the conversation until it ends,
+// it times out or the user sends the cancel command.
+// The steps must be registered before the Conversation starts handling updates.
+// The handlers are called without holding any lock, so they can call the methods of the Conversation.
+type Conversation struct {
+	// OnCancel is called when the user cancels the conversation with the cancel command.
+	OnCancel func(sessionKey int64, state *ConversationState)
+	// OnTimeout is called when the user doesn't reply within the Timeout of the current step.
+	OnTimeout func(sessionKey int64, state *ConversationState)
+	// Logger is the Logger the errors are logged to, eg: a handler returning the name of a state
+	// that doesn't exist, if nil the one set with the SetLogger function is used.
+	Logger Logger
+	// CancelCommand is the command that cancels the conversation, defaults to "cancel".
+	CancelCommand string
+	// BotName is the username of the bot, the cancel command in the form /cancel@BotName
+	// cancels the conversation only if addressed to this bot, as for the commands of a Router.
+	BotName  string
+	steps    map[string]ConversationStep
+	sessions map[int64]*convSession
+	start    string
+	mu       sync.Mutex
+}
+
+type convSession struct {
+	timer *time.Timer
+	state ConversationState
+	// version is incremented on each change of the state, so that the changes made by the
+	// handlers are discarded if the state has changed while they were running.
+	version uint64
+	active  bool
+	mu      sync.Mutex
+}
+
+// NewConversation returns a new Conversation that begins in the state with the given name.
+func NewConversation(start string) *Conversation {
+	return &Conversation{
+		steps:         make(map[string]ConversationStep),
+		sessions:      make(map[int64]*convSession),
+		start:         start,
+		CancelCommand: "cancel",
+	}
+}
+
+// Step registers the handlers of the state with the given name.
+func (c *Conversation) Step(name string, step ConversationStep) {
+	c.steps[name] = step
+}
+
+// Begin starts the conversation in the given session, discarding any conversation already in progress.
+func (c *Conversation) Begin(sessionKey int64) {
+	cs := c.newSession(sessionKey)
+	cs.mu.Lock()
+	cs.active = true
+	cs.state = ConversationState{Data: make(map[string]string)}
+	enter := c.enter(sessionKey, cs, c.start)
+	cs.mu.Unlock()
+
+	enter()
+}
+
+// End ends the conversation in the given session without calling any callback.
+func (c *Conversation) End(sessionKey int64) {
+	if cs := c.session(sessionKey); cs != nil {
+		cs.mu.Lock()
+		c.finish(sessionKey, cs)
+		cs.mu.Unlock()
+	}
+}
+
+// Active reports whether a conversation is in progress in the given session.
+func (c *Conversation) Active(sessionKey int64) bool {
+	_, ok := c.Snapshot(sessionKey)
+	return ok
+}
+
+// Snapshot returns a copy of the state of the conversation in the given session,
+// ok is false if there's no conversation in progress.
+func (c *Conversation) Snapshot(sessionKey int64) (state ConversationState, ok bool) {
+	cs := c.session(sessionKey)
+	if cs == nil {
+		return
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if !cs.active {
+		return
+	}
+	return cs.state.clone(), true
+}
+
+// Restore resumes the conversation in the given session from a state previously returned by Snapshot.
+// The Enter handler of the state is not called again.
+func (c *Conversation) Restore(sessionKey int64, state ConversationState) {
+	cs := c.newSession(sessionKey)
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if state.Data == nil {
+		state.Data = make(map[string]string)
+	}
+	cs.active = true
+	cs.state = state
+	cs.version++
+	c.arm(sessionKey, cs)
+}
+
+// Handle passes the update to the current step of the conversation in the given session
+// and reports whether the update has been consumed by the conversation.
+func (c *Conversation) Handle(sessionKey int64, update *Update) bool {
+	cs := c.session(sessionKey)
+	if cs == nil {
+		return false
+	}
+
+	cs.mu.Lock()
+	switch {
+	case !cs.active:
+		cs.mu.Unlock()
+		return false
+
+	case !cs.state.Deadline.IsZero() && time.Now().After(cs.state.Deadline):
+		state := cs.state
+		c.finish(sessionKey, cs)
+		cs.mu.Unlock()
+		c.onTimeout(sessionKey, state)
+		return false
+
+	case c.isCancel(update):
+		state := cs.state
+		c.finish(sessionKey, cs)
+		cs.mu.Unlock()
+		if c.OnCancel != nil {
+			c.OnCancel(sessionKey, &state)
+		}
+		return true
+	}
+
+	var (
+		step    = c.steps[cs.state.State]
+		state   = cs.state.clone()
+		version = cs.version
+	)
+	cs.mu.Unlock()
+
+	if step.Validate != nil {
+		if err := step.Validate(update); err != nil {
+			if step.Invalid != nil {
+				step.Invalid(sessionKey, &state, err)
+				c.commit(cs, version, state)
+			}
+			return true
+		}
+	}
+
+	var next string
+	if step.Handle != nil {
+		next = step.Handle(sessionKey, &state, update)
+	}
+
+	cs.mu.Lock()
+	if !cs.active || cs.version != version {
+		// The conversation has been ended or moved by someone else in the meantime.
+		cs.mu.Unlock()
+		return true
+	}
+
+	cs.state.Data = state.Data
+	cs.version++
+
+	var enter = func() {}
+	switch next {
+	case "":
+		c.finish(sessionKey, cs)
+	case cs.state.State:
+	default:
+		enter = c.enter(sessionKey, cs, next)
+	}
+	cs.mu.Unlock()
+
+	enter()
+	return true
+}
+
+// Middleware returns a Middleware that passes to the conversation the updates of the sessions
+// with a conversation in progress and to next all the others.
+func (c *Conversation) Middleware() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(sessionKey int64, update *Update) {
+			if !c.Handle(sessionKey, update) {
+				next(sessionKey, update)
+			}
+		}
+	}
+}
+
+func (c *Conversation) session(sessionKey int64) *convSession {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sessions[sessionKey]
+}
+
+// newSession replaces the session with the given key with a new one.
+func (c *Conversation) newSession(sessionKey int64) *convSession {
+	cs := &convSession{}
+
+	c.mu.Lock()
+	old := c.sessions[sessionKey]
+	c.sessions[sessionKey] = cs
+	c.mu.Unlock()
+
+	if old != nil {
+		old.mu.Lock()
+		if old.timer != nil {
+			old.timer.Stop()
+		}
+		old.active = false
+		old.mu.Unlock()
+	}
+	return cs
+}
+
+// enter moves the conversation to the given state, cs.mu must be held.
+// It returns the function calling the Enter handler of the state, which must be called once cs.mu is released.
+// If the state doesn't exist the conversation ends and the error is logged.
+func (c *Conversation) enter(sessionKey int64, cs *convSession, name string) func() {
+	step, ok := c.steps[name]
+	if !ok {
+		c.finish(sessionKey, cs)
+		c.log().Log(LogError, "echotron.Conversation: unknown state", "state", name, "session_key", sessionKey)
+		return func() {}
+	}
+
+	cs.state.State = name
+	cs.state.Deadline = time.Time{}
+	if step.Timeout > 0 {
+		cs.state.Deadline = time.Now().Add(step.Timeout)
+	}
+	cs.version++
+	c.arm(sessionKey, cs)
+
+	if step.Enter == nil {
+		return func() {}
+	}
+
+	state, version := cs.state.clone(), cs.version
+	return func() {
+		step.Enter(sessionKey, &state)
+		c.commit(cs, version, state)
+	}
+}
+
+// commit stores the data changed by a handler, unless the state has changed while it was running.
+func (c *Conversation) commit(cs *convSession, version uint64, state ConversationState) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if cs.active && cs.version == version {
+		cs.state.Data = state.Data
+		cs.version++
+	}
+}
+
+// arm sets the timer for the deadline of the current state, cs.mu must be held.
+func (c *Conversation) arm(sessionKey int64, cs *convSession) {
+	if cs.timer != nil {
+		cs.timer.Stop()
+		cs.timer = nil
+	}
+
+	if deadline := cs.state.Deadline; !deadline.IsZero() {
+		cs.timer = time.AfterFunc(time.Until(deadline), func() {
+			cs.mu.Lock()
+			if !cs.active || !cs.state.Deadline.Equal(deadline) {
+				cs.mu.Unlock()
+				return
+			}
+
+			state := cs.state
+			c.finish(sessionKey, cs)
+			cs.mu.Unlock()
+			c.onTimeout(sessionKey, state)
+		})
+	}
+}
+
+// onTimeout calls the OnTimeout handler, if any.
+func (c *Conversation) onTimeout(sessionKey int64, state ConversationState) {
+	if c.OnTimeout != nil {
+		c.OnTimeout(sessionKey, &state)
+	}
+}
+
+// finish ends the conversation, cs.mu must be held.
+func (c *Conversation) finish(sessionKey int64, cs *convSession) {
+	if cs.timer != nil {
+		cs.timer.Stop()
+		cs.timer = nil
+	}
+	cs.active = false
+	cs.version++
+
+	c.mu.Lock()
+	if c.sessions[sessionKey] == cs {
+		delete(c.sessions, sessionKey)
+	}
+	c.mu.Unlock()
+}
+
+func (c *Conversation) log() Logger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+	return getLogger()
+}
+
+func (c *Conversation) isCancel(update *Update) bool {
+	if c.CancelCommand == "" || update.Message == nil {
+		return false
+	}
+
+	name, _, ok := ParseCommand(update.Message.Text)
+	if !ok {
+		return false
+	}
+
+	if n, bot, found := strings.Cut(name, "@"); found {
+		if !strings.EqualFold(bot, strings.TrimPrefix(c.BotName, "@")) {
+			return false
+		}
+		name = n
+	}
+	return name == strings.TrimPrefix(c.CancelCommand, "/")
+}
+
+// clone returns a copy of the state with its own Data.
+func (s ConversationState) clone() ConversationState {
+	data := make(map[string]string, len(s.Data))
+	for k, v := range s.Data {
+		data[k] = v
+	}
+	s.Data = data
+	return s
+}
@@ -0,0 +1,232 @@
+package echotron
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func newTestConversation(prompts *[]string) *Conversation {
+	prompt := func(text string) func(int64, *ConversationState) {
+		return func(_ int64, _ *ConversationState) {
+			*prompts = append(*prompts, text)
+		}
+	}
+
+	c := NewConversation("name")
+	c.Step("name", ConversationStep{
+		Enter: prompt("name?"),
+		Handle: func(_ int64, s *ConversationState, u *Update) string {
+			s.Data["name"] = u.Message.Text
+			return "age"
+		},
+	})
+	c.Step("age", ConversationStep{
+		Enter: prompt("age?"),
+		Validate: func(u *Update) error {
+			if _, err := strconv.Atoi(u.Message.Text); err != nil {
+				return errors.New("not a number")
+			}
+			return nil
+		},
+		Invalid: func(_ int64, _ *ConversationState, err error) {
+			*prompts = append(*prompts, err.Error())
+		},
+		Handle: func(_ int64, s *ConversationState, u *Update) string {
+			s.Data["age"] = u.Message.Text
+			return ""
+		},
+		Timeout: time.Hour,
+	})
+	return c
+}
+
+func msgUpdate(text string) *Update {
+	return &Update{Message: &Message{Text: text}}
+}
+
+func TestConversation(t *testing.T) {
+	var (
+		prompts []string
+		result  map[string]string
+		c       = newTestConversation(&prompts)
+	)
+
+	if c.Handle(1, msgUpdate("hello")) {
+		t.Fatal("update consumed without a conversation in progress")
+	}
+
+	c.Begin(1)
+	c.Handle(1, msgUpdate("Alice"))
+	if s, _ := c.Snapshot(1); s.State != "age" || s.Deadline.IsZero() {
+		t.Fatalf("unexpected state %+v", s)
+	}
+	c.Handle(1, msgUpdate("many"))
+	if s, _ := c.Snapshot(1); s.State != "age" {
+		t.Fatal("invalid update should not change state")
+	}
+
+	c.steps["age"] = ConversationStep{
+		Handle: func(_ int64, s *ConversationState, u *Update) string {
+			s.Data["age"] = u.Message.Text
+			result = s.Data
+			return ""
+		},
+	}
+	c.Handle(1, msgUpdate("42"))
+
+	if c.Active(1) {
+		t.Fatal("conversation should have ended")
+	}
+	if expected := []string{"name?", "age?", "not a number"}; !reflect.DeepEqual(prompts, expected) {
+		t.Fatalf("expected prompts %v, got %v", expected, prompts)
+	}
+	if expected := map[string]string{"name": "Alice", "age": "42"}; !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected data %v, got %v", expected, result)
+	}
+}
+
+func TestConversationCancel(t *testing.T) {
+	var (
+		prompts   []string
+		cancelled bool
+		c         = newTestConversation(&prompts)
+	)
+
+	c.OnCancel = func(_ int64, _ *ConversationState) { cancelled = true }
+	c.BotName = "@EchotronBot"
+	c.Begin(1)
+	if c.Handle(1, msgUpdate("/cancel@OtherBot")); cancelled || !c.Active(1) {
+		t.Fatal("conversation shouldn't have been cancelled by a command addressed to another bot")
+	}
+	if !c.Handle(1, msgUpdate("/cancel@EchotronBot")) || !cancelled || c.Active(1) {
+		t.Fatal("conversation should have been cancelled")
+	}
+}
+
+func TestConversationTimeout(t *testing.T) {
+	var (
+		prompts []string
+		done    = make(chan string, 1)
+		c       = newTestConversation(&prompts)
+	)
+
+	c.steps["name"] = ConversationStep{Timeout: 10 * time.Millisecond}
+	c.OnTimeout = func(_ int64, s *ConversationState) { done <- s.State }
+	c.Begin(1)
+
+	select {
+	case state := <-done:
+		if state != "name" {
+			t.Fatalf("unexpected timed out state %q", state)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("conversation didn't time out")
+	}
+
+	if c.Active(1) {
+		t.Fatal("conversation should have ended")
+	}
+}
+
+func TestConversationRestore(t *testing.T) {
+	var (
+		prompts []string
+		c       = newTestConversation(&prompts)
+	)
+
+	c.Begin(1)
+	c.Handle(1, msgUpdate("Alice"))
+	state, _ := c.Snapshot(1)
+
+	jsn, err := json.Marshal(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var restored ConversationState
+	if err := json.Unmarshal(jsn, &restored); err != nil {
+		t.Fatal(err)
+	}
+
+	c2 := newTestConversation(&prompts)
+	c2.Restore(2, restored)
+	if s, ok := c2.Snapshot(2); !ok || s.State != "age" || s.Data["name"] != "Alice" {
+		t.Fatalf("unexpected restored state %+v", s)
+	}
+}
+
+func TestConversationReentrant(t *testing.T) {
+	var (
+		prompts []string
+		c       = newTestConversation(&prompts)
+		done    = make(chan struct{})
+	)
+
+	c.steps["name"] = ConversationStep{
+		Enter: func(sessionKey int64, _ *ConversationState) {
+			if !c.Active(sessionKey) {
+				t.Error("conversation should be active in Enter")
+			}
+		},
+		Handle: func(sessionKey int64, _ *ConversationState, u *Update) string {
+			if _, ok := c.Snapshot(sessionKey); !ok {
+				t.Error("conversation should be active in Handle")
+			}
+			if u.Message.Text == "restart" {
+				c.Begin(sessionKey)
+				return "age"
+			}
+			c.End(sessionKey)
+			return "age"
+		},
+	}
+
+	go func() {
+		defer close(done)
+		c.Begin(1)
+		c.Handle(1, msgUpdate("restart"))
+		if s, ok := c.Snapshot(1); !ok || s.State != "name" {
+			t.Errorf("the state set by Begin has been overwritten: %+v", s)
+		}
+		c.Handle(1, msgUpdate("stop"))
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("deadlock calling the Conversation from its handlers")
+	}
+
+	if c.Active(1) {
+		t.Fatal("conversation should have been ended by the handler")
+	}
+}
+
+func TestConversationUnknownState(t *testing.T) {
+	var (
+		prompts []string
+		logged  []string
+		c       = newTestConversation(&prompts)
+	)
+
+	c.Logger = LoggerFunc(func(level LogLevel, msg string, _ ...any) {
+		if level == LogError {
+			logged = append(logged, msg)
+		}
+	})
+	c.steps["name"] = ConversationStep{
+		Handle: func(_ int64, _ *ConversationState, _ *Update) string { return "missing" },
+	}
+
+	c.Begin(1)
+	if !c.Handle(1, msgUpdate("Alice")) {
+		t.Fatal("update should have been consumed")
+	}
+	if c.Active(1) || len(logged) != 1 {
+		t.Fatalf("conversation should have ended logging an error, logged %v", logged)
+	}
+}
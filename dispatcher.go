@@ -20,13 +20,12 @@ package echotron
 
 import (
 	"compress/gzip"
-	"encoding/json"
-	"fmt"
+	"context"
 	"io"
-	"log"
 	"net/http"
-	"net/url"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Bot is the interface that must be implemented by your definition of
@@ -44,12 +43,21 @@ type NewBotFn func(chatId int64) Bot
 // associated with each chatID. When a new chat ID is found, the provided function
 // of type NewBotFn will be called.
 type Dispatcher struct {
-	sessionMap map[int64]Bot
-	newBot     NewBotFn
-	updates    chan *Update
-	httpServer *http.Server
-	api        API
-	mu         sync.Mutex
+	sessionMap  map[int64]Bot
+	waiters     map[int64][]*waiter
+	newBot      NewBotFn
+	handler     HandlerFunc
+	updates     chan *Update
+	httpServer  *http.Server
+	logger      Logger
+	tracer      Tracer
+	recorder    *UpdateRecorder
+	whOpts      *WebhookOptions
+	whURL       string
+	middlewares []Middleware
+	api         API
+	inFlight    atomic.Int64
+	mu          sync.Mutex
 }
 
 // NewDispatcher returns a new instance of the Dispatcher object.
@@ -59,9 +67,11 @@ func NewDispatcher(token string, newBotFn NewBotFn) *Dispatcher {
 	d := &Dispatcher{
 		api:        NewAPI(token),
 		sessionMap: make(map[int64]Bot),
+		waiters:    make(map[int64][]*waiter),
 		newBot:     newBotFn,
 		updates:    make(chan *Update),
 	}
+	d.handler = d.updateBot
 	go d.listen()
 	return d
 }
@@ -91,58 +101,155 @@ func (d *Dispatcher) Poll() error {
 // PollOptions starts the polling loop so that the dispatcher calls the function Update
 // upon receiving any update from Telegram.
 func (d *Dispatcher) PollOptions(dropPendingUpdates bool, opts UpdateOptions) error {
+	return d.Run(context.Background(), &PollingSource{
+		API:                d.getAPI(),
+		Options:            opts,
+		DropPendingUpdates: dropPendingUpdates,
+	})
+}
+
+// Use appends the given middlewares to the ones applied to each update.
+// The middlewares are applied in order, so the first one is the outermost.
+func (d *Dispatcher) Use(mw ...Middleware) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.middlewares = append(d.middlewares, mw...)
+	d.handler = chain(d.updateBot, d.middlewares...)
+}
+
+func (d *Dispatcher) instance(chatID int64) Bot {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	bot, ok := d.sessionMap[chatID]
+	if !ok {
+		bot = d.newBot(chatID)
+		d.sessionMap[chatID] = bot
+	}
+	return bot
+}
+
+// updateBot is the innermost HandlerFunc, it passes the update to the goroutine of the session
+// waiting for it, if any, or to the Bot instance of the session.
+func (d *Dispatcher) updateBot(sessionKey int64, update *Update) {
+	if !d.wake(sessionKey, update) {
+		d.instance(sessionKey).Update(update)
+	}
+}
+
+func (d *Dispatcher) handle(update *Update) {
+	d.inFlight.Add(1)
+
+	d.mu.Lock()
+	handler, tracer := d.handler, d.tracer
+	d.mu.Unlock()
+
 	var (
-		timeout    = opts.Timeout
-		isFirstRun = true
+		key   = update.ChatID()
+		span  Span
+		ctx   = update.ctx
+		scope = &updateScope{}
 	)
 
-	// deletes webhook if present to run in long polling mode
-	if _, err := d.api.DeleteWebhook(dropPendingUpdates); err != nil {
-		return err
+	if ctx == nil {
+		ctx = context.Background()
 	}
+	ctx = ContextWithUpdateMeta(ctx, UpdateMeta{
+		Start:      time.Now(),
+		UpdateID:   update.ID,
+		SessionKey: key,
+	})
 
-	for {
-		if isFirstRun {
-			opts.Timeout = 0
-		}
+	if tracer != nil {
+		ctx, span = tracer.Start(ctx, "echotron.update")
+		span.SetAttribute("update_id", update.ID)
+		span.SetAttribute("update_type", string(update.Type()))
+		span.SetAttribute("session_key", key)
+	}
 
-		response, err := d.api.GetUpdates(&opts)
-		if err != nil {
-			return err
+	scope.refs.Store(1)
+	scope.done = func() {
+		if span != nil {
+			span.End(nil)
 		}
+		d.inFlight.Add(-1)
+	}
+	defer scope.release()
 
-		if !dropPendingUpdates || !isFirstRun {
-			for _, u := range response.Result {
-				d.updates <- u
-			}
-		}
+	update.ctx = context.WithValue(ctx, updateScopeKey{}, scope)
+	handler(key, update)
+}
 
-		if l := len(response.Result); l > 0 {
-			opts.Offset = response.Result[l-1].ID + 1
-		}
+// updateScope tracks the handling of an update, which ends when the handler returns
+// unless a middleware holds the update to handle it later, see holdUpdate.
+type updateScope struct {
+	done func()
+	refs atomic.Int64
+}
 
-		if isFirstRun {
-			isFirstRun = false
-			opts.Timeout = timeout
-		}
+type updateScopeKey struct{}
+
+func (s *updateScope) release() {
+	if s.refs.Add(-1) == 0 {
+		s.done()
 	}
 }
 
-func (d *Dispatcher) instance(chatID int64) Bot {
-	bot, ok := d.sessionMap[chatID]
-	if !ok {
-		bot = d.newBot(chatID)
-		d.mu.Lock()
-		d.sessionMap[chatID] = bot
-		d.mu.Unlock()
+// holdUpdate keeps the update in flight, with its span open, until the returned function is called,
+// so that the middlewares delivering an update after their handler has returned, such as MediaGroups,
+// don't handle it outside of its context.
+func holdUpdate(update *Update) (release func()) {
+	scope, _ := update.Context().Value(updateScopeKey{}).(*updateScope)
+	if scope == nil {
+		return func() {}
 	}
-	return bot
+
+	var once sync.Once
+	scope.refs.Add(1)
+	return func() { once.Do(scope.release) }
+}
+
+// Dispatch handles the update synchronously, passing it through the middlewares to its session.
+// It allows to feed the Dispatcher with updates coming from other sources than polling and webhooks,
+// eg: in tests.
+func (d *Dispatcher) Dispatch(update *Update) {
+	d.handle(update)
+}
+
+// sessionCount returns the number of sessions in the Dispatcher.
+func (d *Dispatcher) sessionCount() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.sessionMap)
 }
 
 func (d *Dispatcher) listen() {
 	for update := range d.updates {
-		bot := d.instance(update.ChatID())
-		go bot.Update(update)
+		d.record(update)
+		go d.handle(update)
+	}
+}
+
+// SetRecorder sets the UpdateRecorder the Dispatcher records the updates to as it receives them,
+// from polling, webhooks or any other UpdateSource. The updates passed to Dispatch or Replay
+// are not recorded. If nil the updates are not recorded.
+func (d *Dispatcher) SetRecorder(r *UpdateRecorder) {
+	d.mu.Lock()
+	d.recorder = r
+	d.mu.Unlock()
+}
+
+func (d *Dispatcher) record(update *Update) {
+	d.mu.Lock()
+	r := d.recorder
+	d.mu.Unlock()
+
+	if r == nil {
+		return
+	}
+	if err := r.Record(update); err != nil {
+		d.log().Log(LogError, "echotron.Dispatcher: cannot record update", "update_id", update.ID, "error", err)
 	}
 }
 
@@ -157,25 +264,68 @@ func (d *Dispatcher) ListenWebhook(webhookURL string) error {
 // ListenWebhook will then proceed to communicate the webhook url '<hostname>/<path>' to Telegram
 // and run a webserver that listens to ':<port>' and handles the path.
 func (d *Dispatcher) ListenWebhookOptions(webhookURL string, dropPendingUpdates bool, opts *WebhookOptions) error {
-	u, err := url.Parse(webhookURL)
+	whURL, _, _, err := webhookAddress(webhookURL)
 	if err != nil {
 		return err
 	}
 
-	whURL := fmt.Sprintf("%s%s", u.Hostname(), u.EscapedPath())
-	if _, err = d.api.SetWebhook(whURL, dropPendingUpdates, opts); err != nil {
+	src := &WebhookSource{
+		Options:            opts,
+		Server:             d.httpServer,
+		API:                d.getAPI(),
+		URL:                webhookURL,
+		DropPendingUpdates: dropPendingUpdates,
+	}
+	if err := src.setWebhook(); err != nil {
 		return err
 	}
 
-	if d.httpServer != nil {
-		mux := http.NewServeMux()
-		mux.Handle("/", d.httpServer.Handler)
-		mux.HandleFunc(u.EscapedPath(), d.HandleWebhook)
-		d.httpServer.Handler = mux
-		return d.httpServer.ListenAndServe()
+	d.mu.Lock()
+	d.whURL, d.whOpts = whURL, opts
+	d.mu.Unlock()
+
+	src.Logger = d.log()
+	return src.serve(context.Background(), d.updates)
+}
+
+// SetLogger sets the Logger used by the Dispatcher, if nil the one set with the SetLogger function is used.
+func (d *Dispatcher) SetLogger(l Logger) {
+	d.mu.Lock()
+	d.logger = l
+	d.mu.Unlock()
+}
+
+// SetAPI sets the API object used by the Dispatcher to receive the updates, eg: to use
+// a local Bot API server. It must be called before the Dispatcher starts polling or listening.
+func (d *Dispatcher) SetAPI(a API) {
+	d.mu.Lock()
+	d.api = a
+	d.mu.Unlock()
+}
+
+// SetTracer sets the Tracer used to start a span for each update handled by the Dispatcher.
+// The span is carried by the context of the update, see Update.Context.
+func (d *Dispatcher) SetTracer(t Tracer) {
+	d.mu.Lock()
+	d.tracer = t
+	d.mu.Unlock()
+}
+
+// getAPI returns the API object set with SetAPI.
+func (d *Dispatcher) getAPI() API {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.api
+}
+
+func (d *Dispatcher) log() Logger {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.logger != nil {
+		return d.logger
 	}
-	http.HandleFunc(u.EscapedPath(), d.HandleWebhook)
-	return http.ListenAndServe(fmt.Sprintf(":%s", u.Port()), nil)
+	return getLogger()
 }
 
 // SetHTTPServer allows to set a custom http.Server for ListenWebhook and ListenWebhookOptions.
@@ -186,20 +336,13 @@ func (d *Dispatcher) SetHTTPServer(s *http.Server) {
 // HandleWebhook is the http.HandlerFunc for the webhook URL.
 // Useful if you've already a http server running and want to handle the request yourself.
 func (d *Dispatcher) HandleWebhook(w http.ResponseWriter, r *http.Request) {
-	var update Update
-
-	jsn, err := readRequest(r)
+	update, err := decodeUpdate(r)
 	if err != nil {
-		log.Println("echotron.Dispatcher", "HandleWebhook", err)
-		return
-	}
-
-	if err := json.Unmarshal(jsn, &update); err != nil {
-		log.Println("echotron.Dispatcher", "HandleWebhook", err)
+		d.log().Log(LogError, "echotron.Dispatcher HandleWebhook: invalid update", "error", err)
 		return
 	}
 
-	d.updates <- &update
+	d.updates <- update
 }
 
 func readRequest(r *http.Request) ([]byte, error) {
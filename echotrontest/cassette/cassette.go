@@ -0,0 +1,421 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package cassette provides an http.RoundTripper that records the exchanges with the
+// Telegram Bot API to cassette files and replays them later, so that the tests calling
+// the API can run offline and deterministically after a one-time recording.
+//
+// The bot token is removed from the recorded URLs and the requests are matched by
+// HTTP method, path and normalized parameters: the multipart uploads are matched by
+// their form fields and by the name and digest of their files, regardless of the boundary,
+// and the parameters holding JSON objects are matched regardless of the key order.
+//
+// The package doesn't depend on echotron, so it can be used by the tests of echotron itself.
+package cassette
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Mode is the mode a Recorder works in.
+type Mode int
+
+// These are all the modes of a Recorder.
+const (
+	// ModeReplay replays the recorded exchanges and fails the requests without a match.
+	ModeReplay Mode = iota
+	// ModeRecord sends the requests to the real server and records the exchanges.
+	ModeRecord
+	// ModeAuto replays the cassette if it exists and records it otherwise.
+	ModeAuto
+)
+
+// Redacted replaces the bot token and the secret parameters in the cassettes.
+const Redacted = "REDACTED"
+
+// ErrNoMatch is the error returned in replay mode when a request has not been recorded.
+var ErrNoMatch = errors.New("cassette: no recorded interaction matches the request")
+
+// ErrExhausted is the error returned in replay mode when a request is sent more times than recorded.
+var ErrExhausted = errors.New("cassette: all the recorded interactions matching the request have been replayed")
+
+var tokenRegexp = regexp.MustCompile(`/bot[0-9]+:[A-Za-z0-9_-]+`)
+
+// secretParams contains the parameters whose values are redacted.
+var secretParams = []string{"secret_token", "provider_token"}
+
+// Request is a recorded request.
+type Request struct {
+	// Params contains the normalized parameters of the request, from both the query and the body.
+	Params map[string][]string `json:"params,omitempty"`
+	// Method is the HTTP method of the request.
+	Method string `json:"method"`
+	// URL is the URL of the request, without the bot token and the query.
+	URL string `json:"url"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	Header http.Header `json:"header,omitempty"`
+	// Body is the body of the response, base64 encoded if Encoding is "base64".
+	Body       string `json:"body"`
+	Encoding   string `json:"encoding,omitempty"`
+	StatusCode int    `json:"status_code"`
+}
+
+// Interaction is a recorded exchange.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Options contains the optional parameters used by the New function.
+type Options struct {
+	// Transport is the http.RoundTripper used to send the requests in record mode,
+	// if nil http.DefaultTransport is used.
+	Transport http.RoundTripper
+	// IgnoreParams contains the parameters not taken into account when matching the requests,
+	// eg: because they change at each run.
+	// A parameter in the form "method.param" is ignored only in the requests to that method,
+	// eg: "setMyName.name".
+	IgnoreParams []string
+	// Mode is the mode of the Recorder, defaults to ModeReplay.
+	Mode Mode
+}
+
+// Recorder is an http.RoundTripper recording and replaying the exchanges of a cassette file.
+type Recorder struct {
+	transport    http.RoundTripper
+	ignore       map[string]bool
+	path         string
+	interactions []Interaction
+	used         []bool
+	recording    bool
+	mu           sync.Mutex
+}
+
+// New returns a new Recorder for the cassette file at the given path.
+// In replay mode the cassette is loaded from the file, which must exist.
+func New(path string, opts *Options) (*Recorder, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	r := &Recorder{
+		path:      path,
+		transport: opts.Transport,
+		ignore:    make(map[string]bool),
+	}
+	if r.transport == nil {
+		r.transport = http.DefaultTransport
+	}
+	for _, p := range opts.IgnoreParams {
+		r.ignore[p] = true
+	}
+
+	switch opts.Mode {
+	case ModeRecord:
+		r.recording = true
+		return r, nil
+
+	case ModeAuto:
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			r.recording = true
+			return r, nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &r.interactions); err != nil {
+		return nil, fmt.Errorf("cassette: %s: %w", path, err)
+	}
+	r.used = make([]bool, len(r.interactions))
+	return r, nil
+}
+
+// Recording reports whether the Recorder is recording the exchanges rather than replaying them.
+func (r *Recorder) Recording() bool {
+	return r.recording
+}
+
+// Client returns an HTTP client using the Recorder as its transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Interactions returns a copy of the interactions of the cassette.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// Save writes the recorded interactions to the cassette file, creating its directory if needed.
+// It does nothing in replay mode.
+func (r *Recorder) Save() error {
+	if !r.recording {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.interactions, "", "\t")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0644)
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rreq, err := newRequest(req, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.recording {
+		return r.record(req, rreq, body)
+	}
+	return r.replay(req, rreq)
+}
+
+func (r *Recorder) record(req *http.Request, rreq Request, body []byte) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+
+	resp, err := r.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	rresp := Response{
+		StatusCode: resp.StatusCode,
+		Header:     http.Header{"Content-Type": resp.Header.Values("Content-Type")},
+		Body:       string(data),
+	}
+	if !utf8.Valid(data) {
+		rresp.Body = base64.StdEncoding.EncodeToString(data)
+		rresp.Encoding = "base64"
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, Interaction{rreq, rresp})
+	r.used = append(r.used, true)
+	r.mu.Unlock()
+
+	return rresp.httpResponse(req)
+}
+
+func (r *Recorder) replay(req *http.Request, rreq Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var matched bool
+	for i, in := range r.interactions {
+		if !r.match(in.Request, rreq) {
+			continue
+		}
+		if !r.used[i] {
+			r.used[i] = true
+			return in.Response.httpResponse(req)
+		}
+		matched = true
+	}
+
+	if matched {
+		return nil, fmt.Errorf("%w: %s %s", ErrExhausted, rreq.Method, rreq.URL)
+	}
+	return nil, fmt.Errorf("%w: %s %s %s", ErrNoMatch, rreq.Method, rreq.URL, url.Values(rreq.Params).Encode())
+}
+
+func (r *Recorder) match(recorded, req Request) bool {
+	if recorded.Method != req.Method || recorded.URL != req.URL {
+		return false
+	}
+	method := path.Base(req.URL)
+	return reflect.DeepEqual(r.filter(method, recorded.Params), r.filter(method, req.Params))
+}
+
+func (r *Recorder) filter(method string, params map[string][]string) map[string][]string {
+	var ret = make(map[string][]string, len(params))
+
+	for k, v := range params {
+		if !r.ignore[k] && !r.ignore[method+"."+k] {
+			ret[k] = v
+		}
+	}
+	return ret
+}
+
+func (r Response) httpResponse(req *http.Request) (*http.Response, error) {
+	body := []byte(r.Body)
+
+	if r.Encoding == "base64" {
+		var err error
+		if body, err = base64.StdEncoding.DecodeString(r.Body); err != nil {
+			return nil, err
+		}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	return io.ReadAll(req.Body)
+}
+
+// newRequest returns the normalized version of the request.
+func newRequest(req *http.Request, body []byte) (Request, error) {
+	u := *req.URL
+	u.RawQuery = ""
+	u.Path = tokenRegexp.ReplaceAllString(u.Path, "/bot"+Redacted)
+	u.RawPath = ""
+
+	params, err := readParams(req, body)
+	if err != nil {
+		return Request{}, err
+	}
+
+	for k, v := range params {
+		for i := range v {
+			v[i] = normalize(v[i])
+		}
+		if len(v) == 0 {
+			delete(params, k)
+		}
+	}
+	for _, k := range secretParams {
+		if _, ok := params[k]; ok {
+			params[k] = []string{Redacted}
+		}
+	}
+
+	if len(params) == 0 {
+		params = nil
+	}
+	return Request{Method: req.Method, URL: u.String(), Params: params}, nil
+}
+
+func readParams(req *http.Request, body []byte) (map[string][]string, error) {
+	params := map[string][]string(req.URL.Query())
+
+	mt, mparams, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	switch mt {
+	case "application/x-www-form-urlencoded":
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range form {
+			params[k] = append(params[k], v...)
+		}
+
+	case "multipart/form-data":
+		mr := multipart.NewReader(bytes.NewReader(body), mparams["boundary"])
+		for {
+			p, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			data, err := io.ReadAll(p)
+			if err != nil {
+				return nil, err
+			}
+
+			v := string(data)
+			if p.FileName() != "" {
+				sum := sha256.Sum256(data)
+				v = fmt.Sprintf("file:%s:sha256:%s", p.FileName(), hex.EncodeToString(sum[:]))
+			}
+			params[p.FormName()] = append(params[p.FormName()], v)
+		}
+	}
+	return params, nil
+}
+
+// normalize returns the JSON objects and arrays re-encoded with sorted keys, and the other values as they are.
+func normalize(v string) string {
+	if !strings.HasPrefix(v, "{") && !strings.HasPrefix(v, "[") {
+		return v
+	}
+
+	var tmp any
+	if err := json.Unmarshal([]byte(v), &tmp); err != nil {
+		return v
+	}
+	data, err := json.Marshal(tmp)
+	if err != nil {
+		return v
+	}
+	return string(data)
+}
@@ -0,0 +1,158 @@
+package cassette
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const token = "123456:SECRET-token"
+
+func upload(t *testing.T, c *http.Client, base string, data []byte) string {
+	var (
+		buf = new(bytes.Buffer)
+		w   = multipart.NewWriter(buf)
+	)
+
+	part, _ := w.CreateFormFile("photo", "photo.png")
+	part.Write(data)
+	w.Close()
+
+	req, _ := http.NewRequest("POST", base+"/bot"+token+"/sendPhoto?chat_id=42", buf)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return do(t, c, req)
+}
+
+func get(t *testing.T, c *http.Client, rawURL string) string {
+	req, _ := http.NewRequest("GET", rawURL, nil)
+	return do(t, c, req)
+}
+
+func do(t *testing.T, c *http.Client, req *http.Request) string {
+	t.Helper()
+
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(resp.Body)
+	return string(data)
+}
+
+func TestRecordReplay(t *testing.T) {
+	var (
+		calls int
+		path  = filepath.Join(t.TempDir(), "cassettes", "api.json")
+		srv   = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			r.ParseMultipartForm(1 << 20)
+			w.Write([]byte(`{"ok":true,"result":"` + strings.TrimPrefix(r.URL.Path, "/bot"+token+"/") + `"}`))
+		}))
+	)
+
+	rec, err := New(path, &Options{Mode: ModeRecord})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := rec.Client()
+	first := get(t, c, srv.URL+`/bot`+token+`/sendMessage?chat_id=42&reply_markup={"b":1,"a":2}`)
+	photo := upload(t, c, srv.URL, []byte("png"))
+	srv.Close()
+
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "SECRET") {
+		t.Fatal("the token has been recorded")
+	}
+
+	rec, err = New(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c = rec.Client()
+	if got := get(t, c, srv.URL+`/bot`+token+`/sendMessage?chat_id=42&reply_markup={"a":2,"b":1}`); got != first {
+		t.Fatalf("expected %q, got %q", first, got)
+	}
+	if got := upload(t, c, srv.URL, []byte("png")); got != photo {
+		t.Fatalf("expected %q, got %q", photo, got)
+	}
+	if calls != 2 {
+		t.Fatalf("the server has been called %d times instead of 2", calls)
+	}
+
+	req, _ := http.NewRequest("GET", srv.URL+"/bot"+token+"/sendMessage?chat_id=43", nil)
+	if _, err := c.Do(req); !errors.Is(err, ErrNoMatch) {
+		t.Fatalf("expected ErrNoMatch, got %v", err)
+	}
+
+	var buf = new(bytes.Buffer)
+	w := multipart.NewWriter(buf)
+	part, _ := w.CreateFormFile("photo", "photo.png")
+	part.Write([]byte("jpg"))
+	w.Close()
+	req, _ = http.NewRequest("POST", srv.URL+"/bot"+token+"/sendPhoto?chat_id=42", buf)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	if _, err := c.Do(req); !errors.Is(err, ErrNoMatch) {
+		t.Fatalf("a different upload should not match, got %v", err)
+	}
+}
+
+func TestAutoMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.json")
+
+	rec, err := New(path, &Options{Mode: ModeAuto})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rec.Recording() {
+		t.Fatal("a missing cassette should be recorded")
+	}
+
+	if _, err := New(path, nil); err == nil {
+		t.Fatal("replaying a missing cassette should fail")
+	}
+}
+
+func TestReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.json")
+	os.WriteFile(path, []byte(`[
+		{"request": {"method": "GET", "url": "https://api.telegram.org/botREDACTED/setMyName", "params": {"name": ["Bot 1"]}},
+		 "response": {"body": "{\"ok\":true,\"result\":true}", "status_code": 200}},
+		{"request": {"method": "GET", "url": "https://api.telegram.org/botREDACTED/setMyDescription", "params": {"description": ["Bot 1"]}},
+		 "response": {"body": "{\"ok\":true,\"result\":true}", "status_code": 200}}
+	]`), 0644)
+
+	rec, err := New(path, &Options{IgnoreParams: []string{"setMyName.name"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := rec.Client()
+	if got := get(t, c, "https://api.telegram.org/bot"+token+"/setMyName?name=Bot+2"); got != `{"ok":true,"result":true}` {
+		t.Fatalf("unexpected response %q", got)
+	}
+
+	req, _ := http.NewRequest("GET", "https://api.telegram.org/bot"+token+"/setMyName?name=Bot+2", nil)
+	if _, err := c.Do(req); !errors.Is(err, ErrExhausted) {
+		t.Fatalf("expected ErrExhausted, got %v", err)
+	}
+
+	req, _ = http.NewRequest("GET", "https://api.telegram.org/bot"+token+"/setMyDescription?description=Bot+2", nil)
+	if _, err := c.Do(req); !errors.Is(err, ErrNoMatch) {
+		t.Fatalf("the parameters of the other methods should not be ignored, got %v", err)
+	}
+}
@@ -0,0 +1,224 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Mockgen generates the methods of the echotrontest.Mock object from the interfaces
+// embedded in echotron.BotAPI, so that the mock is kept in sync with the API.
+//
+// Usage:
+//
+//	go run ./internal/mockgen -src ../interfaces.go,../interfaces_gen.go -o mock_gen.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"strings"
+)
+
+type param struct {
+	name     string
+	typ      string
+	variadic bool
+}
+
+type method struct {
+	name    string
+	params  []param
+	results []string
+}
+
+func main() {
+	var (
+		src = flag.String("src", "../interfaces.go,../interfaces_gen.go", "the comma-separated list of the files declaring the echotron.BotAPI interface and the ones it embeds")
+		out = flag.String("o", "mock_gen.go", "the output file")
+	)
+	flag.Parse()
+
+	methods, err := parseMethods(strings.Split(*src, ",")...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	code, err := generate(methods)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(*out, code, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parseMethods returns the methods of the interfaces embedded in BotAPI, in order.
+func parseMethods(paths ...string) ([]method, error) {
+	ifaces := make(map[string]*ast.InterfaceType)
+
+	for _, path := range paths {
+		f, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range f.Decls {
+			gd, ok := d.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, s := range gd.Specs {
+				ts := s.(*ast.TypeSpec)
+				if it, ok := ts.Type.(*ast.InterfaceType); ok {
+					ifaces[ts.Name.Name] = it
+				}
+			}
+		}
+	}
+
+	root, ok := ifaces["BotAPI"]
+	if !ok {
+		return nil, fmt.Errorf("BotAPI not found in %s", strings.Join(paths, ", "))
+	}
+	return collect(root, ifaces)
+}
+
+func collect(it *ast.InterfaceType, ifaces map[string]*ast.InterfaceType) ([]method, error) {
+	var ret []method
+
+	for _, field := range it.Methods.List {
+		switch t := field.Type.(type) {
+		case *ast.Ident:
+			embedded, ok := ifaces[t.Name]
+			if !ok {
+				return nil, fmt.Errorf("interface %s not found", t.Name)
+			}
+			methods, err := collect(embedded, ifaces)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, methods...)
+
+		case *ast.FuncType:
+			ret = append(ret, newMethod(field.Names[0].Name, t))
+		}
+	}
+	return ret, nil
+}
+
+func newMethod(name string, ft *ast.FuncType) method {
+	m := method{name: name}
+
+	for _, p := range ft.Params.List {
+		_, variadic := p.Type.(*ast.Ellipsis)
+		for _, n := range p.Names {
+			m.params = append(m.params, param{name: n.Name, typ: qualify(p.Type), variadic: variadic})
+		}
+	}
+
+	for _, r := range ft.Results.List {
+		m.results = append(m.results, qualify(r.Type))
+	}
+	return m
+}
+
+// qualify returns the type expression with the types declared in the echotron package qualified.
+func qualify(e ast.Expr) string {
+	switch t := e.(type) {
+	case *ast.Ident:
+		if ast.IsExported(t.Name) {
+			return "echotron." + t.Name
+		}
+		return t.Name
+	case *ast.StarExpr:
+		return "*" + qualify(t.X)
+	case *ast.ArrayType:
+		return "[]" + qualify(t.Elt)
+	case *ast.Ellipsis:
+		return "..." + qualify(t.Elt)
+	case *ast.MapType:
+		return "map[" + qualify(t.Key) + "]" + qualify(t.Value)
+	case *ast.SelectorExpr:
+		return qualify(t.X) + "." + t.Sel.Name
+	default:
+		panic(fmt.Sprintf("unsupported type expression %T", e))
+	}
+}
+
+func generate(methods []method) ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteString("// Code generated by mockgen; DO NOT EDIT.\n\n")
+	buf.WriteString("package echotrontest\n\n")
+	buf.WriteString("import (\n\t\"sync\"\n\n\t\"github.com/NicoNex/echotron/v3\"\n)\n\n")
+
+	buf.WriteString(mockDoc)
+	buf.WriteString("type Mock struct {\n")
+	for _, m := range methods {
+		fmt.Fprintf(&buf, "\t%sFunc func(%s) (%s)\n", m.name, m.signature(), strings.Join(m.results, ", "))
+	}
+	buf.WriteString("\n\tcalls []MockCall\n\tmu    sync.Mutex\n}\n")
+
+	for _, m := range methods {
+		fmt.Fprintf(&buf, "\n// %s records the call and calls %sFunc, if set.\n", m.name, m.name)
+		fmt.Fprintf(&buf, "func (m *Mock) %s(%s) (%s) {\n", m.name, m.signature(), strings.Join(m.results, ", "))
+		fmt.Fprintf(&buf, "\tm.record(%q, %s)\n", m.name, m.args(false))
+		fmt.Fprintf(&buf, "\tif m.%sFunc != nil {\n\t\treturn m.%sFunc(%s)\n\t}\n", m.name, m.name, m.args(true))
+
+		if res := m.results[0]; strings.HasPrefix(res, "echotron.APIResponse") {
+			fmt.Fprintf(&buf, "\tvar res %s\n\tres.Ok = true\n\treturn res, nil\n", res)
+		} else {
+			buf.WriteString("\treturn nil, nil\n")
+		}
+		buf.WriteString("}\n")
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+const mockDoc = `// Mock is an in-memory implementation of echotron.BotAPI that records the calls made to it.
+// Each method calls the function in the field named after it with the Func suffix, eg: SendMessageFunc,
+// if set, otherwise it returns an empty successful response.
+// The zero value is ready to use.
+`
+
+func (m method) signature() string {
+	var s []string
+
+	for _, p := range m.params {
+		s = append(s, p.name+" "+p.typ)
+	}
+	return strings.Join(s, ", ")
+}
+
+func (m method) args(spread bool) string {
+	var s []string
+
+	for _, p := range m.params {
+		if p.variadic && spread {
+			s = append(s, p.name+"...")
+		} else {
+			s = append(s, p.name)
+		}
+	}
+	return strings.Join(s, ", ")
+}
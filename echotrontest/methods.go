@@ -0,0 +1,694 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package echotrontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NicoNex/echotron/v3"
+)
+
+// mediaTypes contains the types of files that can be sent with the send* methods, eg: sendPhoto.
+var mediaTypes = map[string]string{
+	"sendPhoto":     "photo",
+	"sendAudio":     "audio",
+	"sendDocument":  "document",
+	"sendVideo":     "video",
+	"sendAnimation": "animation",
+	"sendVoice":     "voice",
+	"sendVideoNote": "video_note",
+	"sendSticker":   "sticker",
+}
+
+// registerMethods sets the handlers of the methods implemented by the server.
+// The other methods fail with 404 Not Found unless they're set with Handle.
+func (s *Server) registerMethods() {
+	methods := map[string]HandlerFunc{
+		"getMe":                  s.getMe,
+		"getUpdates":             s.getUpdates,
+		"setWebhook":             s.setWebhook,
+		"deleteWebhook":          s.deleteWebhook,
+		"getWebhookInfo":         s.getWebhookInfo,
+		"sendMessage":            s.sendMessage,
+		"forwardMessage":         s.forwardMessage,
+		"copyMessage":            s.copyMessage,
+		"editMessageText":        s.editMessageText,
+		"editMessageCaption":     s.editMessageCaption,
+		"editMessageReplyMarkup": s.editMessageReplyMarkup,
+		"deleteMessage":          s.deleteMessage,
+		"sendChatAction":         s.sendChatAction,
+		"getChat":                s.getChat,
+		"getFile":                s.getFile,
+		"answerCallbackQuery":    s.answerCallbackQuery,
+		"answerInlineQuery":      s.answerInlineQuery,
+		"approveChatJoinRequest": s.chatJoinRequest,
+		"declineChatJoinRequest": s.chatJoinRequest,
+		"setMyCommands":          s.setMyCommands,
+		"getMyCommands":          s.getMyCommands,
+		"deleteMyCommands":       s.deleteMyCommands,
+		"setMyName":              s.setBotInfo("name"),
+		"getMyName":              s.getBotInfo("name"),
+		"setMyDescription":       s.setBotInfo("description"),
+		"getMyDescription":       s.getBotInfo("description"),
+		"setMyShortDescription":  s.setBotInfo("short_description"),
+		"getMyShortDescription":  s.getBotInfo("short_description"),
+		"setChatMenuButton":      s.setChatMenuButton,
+		"getChatMenuButton":      s.getChatMenuButton,
+	}
+
+	for method, kind := range mediaTypes {
+		methods[method] = s.sendMedia(kind)
+	}
+
+	for method, h := range methods {
+		s.Handle(method, h)
+	}
+}
+
+func badRequest(format string, a ...any) *Error {
+	return &Error{Code: http.StatusBadRequest, Description: "Bad Request: " + fmt.Sprintf(format, a...)}
+}
+
+func intParam(c *Call, name string) (int64, error) {
+	v := c.Params.Get(name)
+	if v == "" {
+		return 0, nil
+	}
+
+	i, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, badRequest("invalid %s specified", name)
+	}
+	return i, nil
+}
+
+// chatIDParam returns the ID of the chat referenced by the parameter, which can also be the
+// @username of a chat added with AddChat, s.mu must not be held.
+func (s *Server) chatIDParam(c *Call, name string) (int64, error) {
+	if v := c.Params.Get(name); strings.HasPrefix(v, "@") {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		for id, chat := range s.chats {
+			if "@"+chat.Username == v {
+				return id, nil
+			}
+		}
+		return 0, badRequest("chat not found")
+	}
+
+	id, err := intParam(c, name)
+	if err != nil || id == 0 {
+		return 0, badRequest("chat not found")
+	}
+	return id, nil
+}
+
+func (s *Server) messageIDParam(c *Call) (chatID int64, messageID int, err error) {
+	if c.Params.Get("inline_message_id") != "" {
+		return 0, 0, badRequest("inline messages are not supported")
+	}
+
+	if chatID, err = s.chatIDParam(c, "chat_id"); err != nil {
+		return
+	}
+	id, err := intParam(c, "message_id")
+	return chatID, int(id), err
+}
+
+func replyMarkup(c *Call) *echotron.InlineKeyboardMarkup {
+	var m echotron.InlineKeyboardMarkup
+
+	if err := json.Unmarshal([]byte(c.Params.Get("reply_markup")), &m); err != nil || m.InlineKeyboard == nil {
+		return nil
+	}
+	return &m
+}
+
+// replyTo sets the message replied to by msg, s.mu must be held.
+func (s *Server) replyTo(msg *echotron.Message, c *Call) {
+	if id, _ := intParam(c, "reply_to_message_id"); id != 0 {
+		if m := s.message(msg.Chat.ID, int(id)); m != nil {
+			msg.ReplyToMessage = copyMessage(m)
+		}
+	}
+}
+
+func (s *Server) getMe(_ *Call) (any, error) {
+	return s.bot, nil
+}
+
+func (s *Server) getUpdates(c *Call) (any, error) {
+	var ret = make([]*echotron.Update, 0)
+
+	offset, err := intParam(c, "offset")
+	if err != nil {
+		return nil, err
+	}
+	limit, err := intParam(c, "limit")
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+	timeout, err := intParam(c, "timeout")
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if s.webhook.url != "" {
+		s.mu.Unlock()
+		return nil, &Error{
+			Code:        http.StatusConflict,
+			Description: "Conflict: can't use getUpdates method while webhook is active; use deleteWebhook to delete the webhook first",
+		}
+	}
+	// Like Telegram, a new getUpdates request terminates the one in progress.
+	if s.poll != nil {
+		close(s.poll)
+	}
+	poll := make(chan struct{})
+	s.poll = poll
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		if s.poll == poll {
+			s.poll = nil
+		}
+		s.mu.Unlock()
+	}()
+
+	timer := time.NewTimer(time.Duration(timeout) * time.Second)
+	defer timer.Stop()
+
+	for {
+		s.mu.Lock()
+		if offset > 0 {
+			for len(s.updates) > 0 && int64(s.updates[0].ID) < offset {
+				s.updates = s.updates[1:]
+			}
+		}
+		if len(s.updates) > 0 || timeout <= 0 {
+			for i := 0; i < len(s.updates) && i < int(limit); i++ {
+				ret = append(ret, s.updates[i])
+			}
+			s.mu.Unlock()
+			return ret, nil
+		}
+		notify := s.notify
+		s.mu.Unlock()
+
+		select {
+		case <-notify:
+		case <-timer.C:
+			return ret, nil
+		case <-s.closed:
+			return ret, nil
+		case <-c.Context().Done():
+			return nil, c.Context().Err()
+		case <-poll:
+			return nil, &Error{
+				Code:        http.StatusConflict,
+				Description: "Conflict: terminated by other getUpdates request; make sure that only one bot instance is running",
+			}
+		}
+	}
+}
+
+func (s *Server) setWebhook(c *Call) (any, error) {
+	maxConn, err := intParam(c, "max_connections")
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.webhook = webhook{
+		url:            c.Params.Get("url"),
+		secretToken:    c.Params.Get("secret_token"),
+		maxConnections: int(maxConn),
+		certificate:    c.Files["certificate"] != nil,
+	}
+	if c.Params.Get("drop_pending_updates") == "true" {
+		s.updates = nil
+	}
+	// Terminate the getUpdates request in progress, if any.
+	if s.poll != nil {
+		close(s.poll)
+		s.poll = nil
+	}
+	s.mu.Unlock()
+
+	go s.deliver()
+	return true, nil
+}
+
+func (s *Server) deleteWebhook(c *Call) (any, error) {
+	s.mu.Lock()
+	s.webhook = webhook{}
+	if c.Params.Get("drop_pending_updates") == "true" {
+		s.updates = nil
+	}
+	s.mu.Unlock()
+	return true, nil
+}
+
+func (s *Server) getWebhookInfo(_ *Call) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return echotron.WebhookInfo{
+		URL:                  s.webhook.url,
+		LastErrorMessage:     s.webhook.lastError,
+		LastErrorDate:        s.webhook.lastErrorDate,
+		MaxConnections:       s.webhook.maxConnections,
+		PendingUpdateCount:   len(s.updates),
+		HasCustomCertificate: s.webhook.certificate,
+	}, nil
+}
+
+func (s *Server) sendMessage(c *Call) (any, error) {
+	chatID, err := s.chatIDParam(c, "chat_id")
+	if err != nil {
+		return nil, err
+	}
+
+	text := c.Params.Get("text")
+	if text == "" {
+		return nil, badRequest("message text is empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg := s.newMessage(chatID, &s.bot)
+	msg.Text = text
+	msg.ReplyMarkup = replyMarkup(c)
+	s.replyTo(msg, c)
+	return copyMessage(msg), nil
+}
+
+// sourceMessage returns the message of the chat fromChatID referenced by the message_id parameter,
+// s.mu must be held.
+func (s *Server) sourceMessage(fromChatID int64, c *Call) (*echotron.Message, error) {
+	id, err := intParam(c, "message_id")
+	if err != nil {
+		return nil, err
+	}
+
+	src := s.message(fromChatID, int(id))
+	if src == nil {
+		return nil, badRequest("message to copy not found")
+	}
+	return src, nil
+}
+
+// cloneMessage stores and returns a new message in the chat with the content of src, s.mu must be held.
+func (s *Server) cloneMessage(chatID int64, src *echotron.Message, from *echotron.User) *echotron.Message {
+	var (
+		msg            = s.newMessage(chatID, from)
+		id, chat, date = msg.ID, msg.Chat, msg.Date
+	)
+
+	*msg = *src
+	msg.ID, msg.Chat, msg.Date, msg.From = id, chat, date, from
+	msg.ReplyMarkup = nil
+	msg.ReplyToMessage = nil
+	msg.EditDate = 0
+	return msg
+}
+
+func (s *Server) forwardMessage(c *Call) (any, error) {
+	chatID, err := s.chatIDParam(c, "chat_id")
+	if err != nil {
+		return nil, err
+	}
+	fromChatID, err := s.chatIDParam(c, "from_chat_id")
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	src, err := s.sourceMessage(fromChatID, c)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := s.cloneMessage(chatID, src, src.From)
+	msg.ForwardFrom = src.From
+	msg.ForwardDate = src.Date
+	return copyMessage(msg), nil
+}
+
+func (s *Server) copyMessage(c *Call) (any, error) {
+	chatID, err := s.chatIDParam(c, "chat_id")
+	if err != nil {
+		return nil, err
+	}
+	fromChatID, err := s.chatIDParam(c, "from_chat_id")
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	src, err := s.sourceMessage(fromChatID, c)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := s.cloneMessage(chatID, src, &s.bot)
+	if _, ok := c.Params["caption"]; ok {
+		msg.Caption = c.Params.Get("caption")
+	}
+	msg.ReplyMarkup = replyMarkup(c)
+	s.replyTo(msg, c)
+	return echotron.MessageID{MessageID: msg.ID}, nil
+}
+
+// edit applies fn to the message referenced by the call, and fails if the message
+// is not found or is not modified.
+func (s *Server) edit(c *Call, fn func(msg *echotron.Message)) (any, error) {
+	chatID, messageID, err := s.messageIDParam(c)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg := s.message(chatID, messageID)
+	if msg == nil {
+		return nil, badRequest("message to edit not found")
+	}
+
+	edited := copyMessage(msg)
+	fn(edited)
+	if reflect.DeepEqual(edited, msg) {
+		return nil, badRequest("message is not modified: specified new message content and reply markup are exactly the same as a current content and reply markup of the message")
+	}
+
+	edited.EditDate = int(time.Now().Unix())
+	*msg = *edited
+	return copyMessage(msg), nil
+}
+
+func (s *Server) editMessageText(c *Call) (any, error) {
+	text := c.Params.Get("text")
+	if text == "" {
+		return nil, badRequest("message text is empty")
+	}
+
+	return s.edit(c, func(msg *echotron.Message) {
+		msg.Text = text
+		msg.ReplyMarkup = replyMarkup(c)
+	})
+}
+
+func (s *Server) editMessageCaption(c *Call) (any, error) {
+	return s.edit(c, func(msg *echotron.Message) {
+		msg.Caption = c.Params.Get("caption")
+		msg.ReplyMarkup = replyMarkup(c)
+	})
+}
+
+func (s *Server) editMessageReplyMarkup(c *Call) (any, error) {
+	return s.edit(c, func(msg *echotron.Message) {
+		msg.ReplyMarkup = replyMarkup(c)
+	})
+}
+
+func (s *Server) deleteMessage(c *Call) (any, error) {
+	chatID, messageID, err := s.messageIDParam(c)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, m := range s.messages[chatID] {
+		if m.ID == messageID {
+			s.messages[chatID] = append(s.messages[chatID][:i:i], s.messages[chatID][i+1:]...)
+			return true, nil
+		}
+	}
+	return nil, badRequest("message to delete not found")
+}
+
+func (s *Server) sendChatAction(c *Call) (any, error) {
+	if _, err := s.chatIDParam(c, "chat_id"); err != nil {
+		return nil, err
+	}
+	return true, nil
+}
+
+func (s *Server) getChat(c *Call) (any, error) {
+	chatID, err := s.chatIDParam(c, "chat_id")
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.chat(chatID), nil
+}
+
+func (s *Server) getFile(c *Call) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if f, ok := s.files[c.Params.Get("file_id")]; ok {
+		return f.File, nil
+	}
+	return nil, badRequest("invalid file_id")
+}
+
+func (s *Server) answerCallbackQuery(c *Call) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cb, ok := s.callbacks[c.Params.Get("callback_query_id")]
+	if !ok || cb.answer != nil {
+		return nil, badRequest("query is too old and response timeout expired or query ID is invalid")
+	}
+	cb.answer = c.Params
+	return true, nil
+}
+
+func (s *Server) answerInlineQuery(c *Call) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ans, ok := s.inlineQueries[c.Params.Get("inline_query_id")]
+	if !ok || ans != nil {
+		return nil, badRequest("query is too old and response timeout expired or query ID is invalid")
+	}
+	s.inlineQueries[c.Params.Get("inline_query_id")] = c.Params
+	return true, nil
+}
+
+func (s *Server) chatJoinRequest(c *Call) (any, error) {
+	if _, err := s.chatIDParam(c, "chat_id"); err != nil {
+		return nil, err
+	}
+	if id, err := intParam(c, "user_id"); err != nil || id == 0 {
+		return nil, badRequest("user not found")
+	}
+	return true, nil
+}
+
+// setMyCommands keeps the commands by language, regardless of their scope.
+func (s *Server) setMyCommands(c *Call) (any, error) {
+	var cmds []echotron.BotCommand
+
+	if err := json.Unmarshal([]byte(c.Params.Get("commands")), &cmds); err != nil {
+		return nil, badRequest("can't parse commands JSON object")
+	}
+
+	s.mu.Lock()
+	s.commands[c.Params.Get("language_code")] = cmds
+	s.mu.Unlock()
+	return true, nil
+}
+
+func (s *Server) getMyCommands(c *Call) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]echotron.BotCommand{}, s.commands[c.Params.Get("language_code")]...), nil
+}
+
+func (s *Server) deleteMyCommands(c *Call) (any, error) {
+	s.mu.Lock()
+	delete(s.commands, c.Params.Get("language_code"))
+	s.mu.Unlock()
+	return true, nil
+}
+
+// setBotInfo returns the handler of the method setting the given property of the bot
+// for a language, eg: "name" for setMyName.
+func (s *Server) setBotInfo(key string) HandlerFunc {
+	return func(c *Call) (any, error) {
+		lang := c.Params.Get("language_code")
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if s.botInfo[lang] == nil {
+			s.botInfo[lang] = make(map[string]string)
+		}
+		s.botInfo[lang][key] = c.Params.Get(key)
+		return true, nil
+	}
+}
+
+// getBotInfo returns the handler of the method getting the given property of the bot, eg: "name" for getMyName.
+// The property in the default language is returned if it isn't set for the requested one.
+func (s *Server) getBotInfo(key string) HandlerFunc {
+	return func(c *Call) (any, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		v, ok := s.botInfo[c.Params.Get("language_code")][key]
+		if !ok || v == "" {
+			v = s.botInfo[""][key]
+		}
+		return map[string]string{key: v}, nil
+	}
+}
+
+// setChatMenuButton keeps only the default menu button, the one of the private chats is ignored.
+func (s *Server) setChatMenuButton(c *Call) (any, error) {
+	var btn echotron.MenuButton
+
+	if v := c.Params.Get("menu_button"); v != "" {
+		if err := json.Unmarshal([]byte(v), &btn); err != nil {
+			return nil, badRequest("can't parse menu button JSON object")
+		}
+	} else {
+		btn.Type = echotron.MenuButtonTypeDefault
+	}
+
+	if c.Params.Get("chat_id") == "" {
+		s.mu.Lock()
+		s.menuButton = btn
+		s.mu.Unlock()
+	}
+	return true, nil
+}
+
+func (s *Server) getChatMenuButton(_ *Call) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.menuButton, nil
+}
+
+// sendMedia returns the handler of the method sending the given kind of file, eg: "photo" for sendPhoto.
+func (s *Server) sendMedia(kind string) HandlerFunc {
+	return func(c *Call) (any, error) {
+		chatID, err := s.chatIDParam(c, "chat_id")
+		if err != nil {
+			return nil, err
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		f, err := s.inputFile(c, kind)
+		if err != nil {
+			return nil, err
+		}
+
+		msg := s.newMessage(chatID, &s.bot)
+		msg.Caption = c.Params.Get("caption")
+		msg.ReplyMarkup = replyMarkup(c)
+		s.replyTo(msg, c)
+		setMedia(msg, kind, f, path.Base(f.FilePath))
+		return copyMessage(msg), nil
+	}
+}
+
+// inputFile returns the file sent in the parameter with the given name, which is either
+// uploaded, the ID of a known file or a URL, s.mu must be held.
+func (s *Server) inputFile(c *Call, name string) (*file, error) {
+	if data, ok := c.Files[name]; ok {
+		return s.addFile(fmt.Sprintf("%ss/file_%d%s", name, s.lastFile+1, path.Ext(c.fileNames[name])), data), nil
+	}
+
+	v := c.Params.Get(name)
+	if v == "" {
+		return nil, badRequest("there is no %s in the request", name)
+	}
+
+	if f, ok := s.files[v]; ok {
+		return f, nil
+	}
+
+	if u, err := url.Parse(v); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		return s.addFile(fmt.Sprintf("%ss/file_%d%s", name, s.lastFile+1, path.Ext(u.Path)), nil), nil
+	}
+	return nil, badRequest("wrong remote file identifier specified: wrong string length")
+}
+
+// setMedia sets the field of msg corresponding to the given kind of file.
+func setMedia(msg *echotron.Message, kind string, f *file, fileName string) {
+	var (
+		target any
+		jsn, _ = json.Marshal(map[string]any{
+			"file_id":        f.FileID,
+			"file_unique_id": f.FileUniqueID,
+			"file_size":      f.FileSize,
+			"file_name":      fileName,
+		})
+	)
+
+	switch kind {
+	case "photo":
+		target = &msg.Photo
+		jsn = append(append([]byte("["), jsn...), ']')
+	case "audio":
+		target = &msg.Audio
+	case "document":
+		target = &msg.Document
+	case "video":
+		target = &msg.Video
+	case "animation":
+		target = &msg.Animation
+	case "voice":
+		target = &msg.Voice
+	case "video_note":
+		target = &msg.VideoNote
+	case "sticker":
+		target = &msg.Sticker
+	}
+	json.Unmarshal(jsn, target)
+}
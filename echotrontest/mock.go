@@ -0,0 +1,69 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package echotrontest
+
+//go:generate go run ./internal/mockgen -src ../interfaces.go,../interfaces_gen.go -o mock_gen.go
+
+import (
+	"strings"
+
+	"github.com/NicoNex/echotron/v3"
+)
+
+var _ echotron.BotAPI = (*Mock)(nil)
+
+// MockCall represents a call to a method of the Mock.
+type MockCall struct {
+	// Method is the name of the called method, eg: "SendMessage".
+	Method string
+	// Args contains the arguments of the call, in order.
+	Args []any
+}
+
+func (m *Mock) record(method string, args ...any) {
+	m.mu.Lock()
+	m.calls = append(m.calls, MockCall{Method: method, Args: args})
+	m.mu.Unlock()
+}
+
+// Calls returns all the calls made to the Mock, in order.
+func (m *Mock) Calls() []MockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]MockCall(nil), m.calls...)
+}
+
+// CallsTo returns the calls made to the given method of the Mock, in order.
+func (m *Mock) CallsTo(method string) []MockCall {
+	var ret []MockCall
+
+	for _, c := range m.Calls() {
+		if strings.EqualFold(c.Method, method) {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+// Reset forgets the calls made to the Mock.
+func (m *Mock) Reset() {
+	m.mu.Lock()
+	m.calls = nil
+	m.mu.Unlock()
+}
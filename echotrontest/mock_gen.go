@@ -0,0 +1,1481 @@
+// Code generated by mockgen; DO NOT EDIT.
+
+package echotrontest
+
+import (
+	"sync"
+
+	"github.com/NicoNex/echotron/v3"
+)
+
+// Mock is an in-memory implementation of echotron.BotAPI that records the calls made to it.
+// Each method calls the function in the field named after it with the Func suffix, eg: SendMessageFunc,
+// if set, otherwise it returns an empty successful response.
+// The zero value is ready to use.
+type Mock struct {
+	GetUpdatesFunc                        func(opts *echotron.UpdateOptions) (echotron.APIResponseUpdate, error)
+	SetWebhookFunc                        func(webhookURL string, dropPendingUpdates bool, opts *echotron.WebhookOptions) (echotron.APIResponseBase, error)
+	DeleteWebhookFunc                     func(dropPendingUpdates bool) (echotron.APIResponseBase, error)
+	GetWebhookInfoFunc                    func() (echotron.APIResponseWebhook, error)
+	GetMeFunc                             func() (echotron.APIResponseUser, error)
+	LogOutFunc                            func() (echotron.APIResponseBool, error)
+	CloseFunc                             func() (echotron.APIResponseBool, error)
+	SetMyCommandsFunc                     func(opts *echotron.CommandOptions, commands ...echotron.BotCommand) (echotron.APIResponseBool, error)
+	DeleteMyCommandsFunc                  func(opts *echotron.CommandOptions) (echotron.APIResponseBool, error)
+	GetMyCommandsFunc                     func(opts *echotron.CommandOptions) (echotron.APIResponseCommands, error)
+	SetMyNameFunc                         func(name string, languageCode string) (echotron.APIResponseBool, error)
+	GetMyNameFunc                         func(languageCode string) (echotron.APIResponseBotName, error)
+	SetMyDescriptionFunc                  func(description string, languageCode string) (echotron.APIResponseBool, error)
+	GetMyDescriptionFunc                  func(languageCode string) (echotron.APIResponseBotDescription, error)
+	SetMyShortDescriptionFunc             func(shortDescription string, languageCode string) (echotron.APIResponseBool, error)
+	GetMyShortDescriptionFunc             func(languageCode string) (echotron.APIResponseBotShortDescription, error)
+	SetMyDefaultAdministratorRightsFunc   func(opts echotron.SetMyDefaultAdministratorRightsOptions) (echotron.APIResponseBool, error)
+	GetMyDefaultAdministratorRightsFunc   func(opts echotron.GetMyDefaultAdministratorRightsOptions) (echotron.APIResponseChatAdministratorRights, error)
+	SetChatMenuButtonFunc                 func(opts echotron.SetChatMenuButtonOptions) (echotron.APIResponseBool, error)
+	GetChatMenuButtonFunc                 func(opts echotron.GetChatMenuButtonOptions) (echotron.APIResponseMenuButton, error)
+	SendMessageFunc                       func(text string, chatID int64, opts *echotron.MessageOptions) (echotron.APIResponseMessage, error)
+	SendMessageWithUserNameFunc           func(text string, userName string, opts *echotron.MessageOptions) (echotron.APIResponseMessage, error)
+	ForwardMessageFunc                    func(chatID int64, fromChatID int64, messageID int, opts *echotron.ForwardOptions) (echotron.APIResponseMessage, error)
+	CopyMessageFunc                       func(chatID int64, fromChatID int64, messageID int, opts *echotron.CopyOptions) (echotron.APIResponseMessageID, error)
+	SendPhotoFunc                         func(file echotron.InputFile, chatID int64, opts *echotron.PhotoOptions) (echotron.APIResponseMessage, error)
+	SendAudioFunc                         func(file echotron.InputFile, chatID int64, opts *echotron.AudioOptions) (echotron.APIResponseMessage, error)
+	SendDocumentFunc                      func(file echotron.InputFile, chatID int64, opts *echotron.DocumentOptions) (echotron.APIResponseMessage, error)
+	SendVideoFunc                         func(file echotron.InputFile, chatID int64, opts *echotron.VideoOptions) (echotron.APIResponseMessage, error)
+	SendAnimationFunc                     func(file echotron.InputFile, chatID int64, opts *echotron.AnimationOptions) (echotron.APIResponseMessage, error)
+	SendVoiceFunc                         func(file echotron.InputFile, chatID int64, opts *echotron.VoiceOptions) (echotron.APIResponseMessage, error)
+	SendVideoNoteFunc                     func(file echotron.InputFile, chatID int64, opts *echotron.VideoNoteOptions) (echotron.APIResponseMessage, error)
+	SendMediaGroupFunc                    func(chatID int64, media []echotron.GroupableInputMedia, opts *echotron.MediaGroupOptions) (echotron.APIResponseMessageArray, error)
+	SendLocationFunc                      func(chatID int64, latitude float64, longitude float64, opts *echotron.LocationOptions) (echotron.APIResponseMessage, error)
+	EditMessageLiveLocationFunc           func(msg echotron.MessageIDOptions, latitude float64, longitude float64, opts *echotron.EditLocationOptions) (echotron.APIResponseMessage, error)
+	StopMessageLiveLocationFunc           func(msg echotron.MessageIDOptions, opts *echotron.MessageReplyMarkup) (echotron.APIResponseMessage, error)
+	SendVenueFunc                         func(chatID int64, latitude float64, longitude float64, title string, address string, opts *echotron.VenueOptions) (echotron.APIResponseMessage, error)
+	SendContactFunc                       func(phoneNumber string, firstName string, chatID int64, opts *echotron.ContactOptions) (echotron.APIResponseMessage, error)
+	SendPollFunc                          func(chatID int64, question string, options []string, opts *echotron.PollOptions) (echotron.APIResponseMessage, error)
+	SendDiceFunc                          func(chatID int64, emoji echotron.DiceEmoji, opts *echotron.BaseOptions) (echotron.APIResponseMessage, error)
+	SendChatActionFunc                    func(action echotron.ChatAction, chatID int64, opts *echotron.ChatActionOptions) (echotron.APIResponseBool, error)
+	EditMessageTextFunc                   func(text string, msg echotron.MessageIDOptions, opts *echotron.MessageTextOptions) (echotron.APIResponseMessage, error)
+	EditMessageCaptionFunc                func(msg echotron.MessageIDOptions, opts *echotron.MessageCaptionOptions) (echotron.APIResponseMessage, error)
+	EditMessageMediaFunc                  func(msg echotron.MessageIDOptions, media echotron.InputMedia, opts *echotron.MessageReplyMarkup) (echotron.APIResponseMessage, error)
+	EditMessageReplyMarkupFunc            func(msg echotron.MessageIDOptions, opts *echotron.MessageReplyMarkup) (echotron.APIResponseMessage, error)
+	StopPollFunc                          func(chatID int64, messageID int, opts *echotron.MessageReplyMarkup) (echotron.APIResponsePoll, error)
+	DeleteMessageFunc                     func(chatID int64, messageID int) (echotron.APIResponseBase, error)
+	AnswerCallbackQueryFunc               func(callbackID string, opts *echotron.CallbackQueryOptions) (echotron.APIResponseBool, error)
+	GetFileFunc                           func(fileID string) (echotron.APIResponseFile, error)
+	DownloadFileFunc                      func(filePath string) ([]byte, error)
+	GetUserProfilePhotosFunc              func(userID int64, opts *echotron.UserProfileOptions) (echotron.APIResponseUserProfile, error)
+	BanChatMemberFunc                     func(chatID int64, userID int64, opts *echotron.BanOptions) (echotron.APIResponseBool, error)
+	UnbanChatMemberFunc                   func(chatID int64, userID int64, opts *echotron.UnbanOptions) (echotron.APIResponseBool, error)
+	RestrictChatMemberFunc                func(chatID int64, userID int64, permissions echotron.ChatPermissions, opts *echotron.RestrictOptions) (echotron.APIResponseBool, error)
+	PromoteChatMemberFunc                 func(chatID int64, userID int64, opts *echotron.PromoteOptions) (echotron.APIResponseBool, error)
+	SetChatAdministratorCustomTitleFunc   func(chatID int64, userID int64, customTitle string) (echotron.APIResponseBool, error)
+	BanChatSenderChatFunc                 func(chatID int64, senderChatID int64) (echotron.APIResponseBool, error)
+	UnbanChatSenderChatFunc               func(chatID int64, senderChatID int64) (echotron.APIResponseBool, error)
+	SetChatPermissionsFunc                func(chatID int64, permissions echotron.ChatPermissions, opts *echotron.ChatPermissionsOptions) (echotron.APIResponseBool, error)
+	ExportChatInviteLinkFunc              func(chatID int64) (echotron.APIResponseString, error)
+	CreateChatInviteLinkFunc              func(chatID int64, opts *echotron.InviteLinkOptions) (echotron.APIResponseInviteLink, error)
+	EditChatInviteLinkFunc                func(chatID int64, inviteLink string, opts *echotron.InviteLinkOptions) (echotron.APIResponseInviteLink, error)
+	RevokeChatInviteLinkFunc              func(chatID int64, inviteLink string) (echotron.APIResponseInviteLink, error)
+	ApproveChatJoinRequestFunc            func(chatID int64, userID int64) (echotron.APIResponseBool, error)
+	DeclineChatJoinRequestFunc            func(chatID int64, userID int64) (echotron.APIResponseBool, error)
+	SetChatPhotoFunc                      func(file echotron.InputFile, chatID int64) (echotron.APIResponseBool, error)
+	DeleteChatPhotoFunc                   func(chatID int64) (echotron.APIResponseBool, error)
+	SetChatTitleFunc                      func(chatID int64, title string) (echotron.APIResponseBool, error)
+	SetChatDescriptionFunc                func(chatID int64, description string) (echotron.APIResponseBool, error)
+	PinChatMessageFunc                    func(chatID int64, messageID int, opts *echotron.PinMessageOptions) (echotron.APIResponseBool, error)
+	UnpinChatMessageFunc                  func(chatID int64, messageID int) (echotron.APIResponseBool, error)
+	UnpinAllChatMessagesFunc              func(chatID int64) (echotron.APIResponseBool, error)
+	LeaveChatFunc                         func(chatID int64) (echotron.APIResponseBool, error)
+	GetChatFunc                           func(chatID int64) (echotron.APIResponseChat, error)
+	GetChatAdministratorsFunc             func(chatID int64) (echotron.APIResponseAdministrators, error)
+	GetChatMemberCountFunc                func(chatID int64) (echotron.APIResponseInteger, error)
+	GetChatMemberFunc                     func(chatID int64, userID int64) (echotron.APIResponseChatMember, error)
+	SetChatStickerSetFunc                 func(chatID int64, stickerSetName string) (echotron.APIResponseBool, error)
+	DeleteChatStickerSetFunc              func(chatID int64) (echotron.APIResponseBool, error)
+	CreateForumTopicFunc                  func(chatID int64, name string, opts *echotron.CreateTopicOptions) (echotron.APIResponseForumTopic, error)
+	EditForumTopicFunc                    func(chatID int64, messageThreadID int64, opts *echotron.EditTopicOptions) (echotron.APIResponseBool, error)
+	CloseForumTopicFunc                   func(chatID int64, messageThreadID int64) (echotron.APIResponseBool, error)
+	ReopenForumTopicFunc                  func(chatID int64, messageThreadID int64) (echotron.APIResponseBool, error)
+	DeleteForumTopicFunc                  func(chatID int64, messageThreadID int64) (echotron.APIResponseBool, error)
+	UnpinAllForumTopicMessagesFunc        func(chatID int64, messageThreadID int64) (echotron.APIResponseBool, error)
+	EditGeneralForumTopicFunc             func(chatID int64, name string) (echotron.APIResponseBool, error)
+	CloseGeneralForumTopicFunc            func(chatID int64) (echotron.APIResponseBool, error)
+	ReopenGeneralForumTopicFunc           func(chatID int64) (echotron.APIResponseBool, error)
+	HideGeneralForumTopicFunc             func(chatID int64) (echotron.APIResponseBool, error)
+	UnhideGeneralForumTopicFunc           func(chatID int64) (echotron.APIResponseBool, error)
+	SendStickerFunc                       func(stickerID string, chatID int64, opts *echotron.StickerOptions) (echotron.APIResponseMessage, error)
+	GetStickerSetFunc                     func(name string) (echotron.APIResponseStickerSet, error)
+	GetCustomEmojiStickersFunc            func(customEmojiIDs ...string) (echotron.APIResponseStickers, error)
+	UploadStickerFileFunc                 func(userID int64, sticker echotron.InputFile, format echotron.StickerFormat) (echotron.APIResponseFile, error)
+	CreateNewStickerSetFunc               func(userID int64, name string, title string, stickers []echotron.InputSticker, format echotron.StickerFormat, opts *echotron.NewStickerSetOptions) (echotron.APIResponseBool, error)
+	AddStickerToSetFunc                   func(userID int64, name string, sticker echotron.InputSticker) (echotron.APIResponseBool, error)
+	SetStickerPositionInSetFunc           func(sticker string, position int) (echotron.APIResponseBase, error)
+	DeleteStickerFromSetFunc              func(sticker string) (echotron.APIResponseBase, error)
+	SetStickerEmojiListFunc               func(sticker string, emojis []string) (echotron.APIResponseBool, error)
+	SetStickerKeywordsFunc                func(sticker string, keywords []string) (echotron.APIResponseBool, error)
+	SetStickerMaskPositionFunc            func(sticker string, mask echotron.MaskPosition) (echotron.APIResponseBool, error)
+	SetStickerSetTitleFunc                func(name string, title string) (echotron.APIResponseBool, error)
+	SetStickerSetThumbnailFunc            func(name string, userID int64, thumbnail echotron.InputFile) (echotron.APIResponseBase, error)
+	SetCustomEmojiStickerSetThumbnailFunc func(name string, emojiID string) (echotron.APIResponseBool, error)
+	DeleteStickerSetFunc                  func(name string) (echotron.APIResponseBool, error)
+	GetForumTopicIconStickersFunc         func() (echotron.APIResponseStickers, error)
+	SendInvoiceFunc                       func(chatID int64, title string, description string, payload string, providerToken string, currency string, prices []echotron.LabeledPrice, opts *echotron.InvoiceOptions) (echotron.APIResponseMessage, error)
+	AnswerShippingQueryFunc               func(shippingQueryID string, ok bool, opts *echotron.ShippingQueryOptions) (echotron.APIResponseBase, error)
+	AnswerPreCheckoutQueryFunc            func(preCheckoutQueryID string, ok bool, opts *echotron.PreCheckoutOptions) (echotron.APIResponseBase, error)
+	CreateInvoiceLinkFunc                 func(title string, description string, payload string, providerToken string, currency string, prices []echotron.LabeledPrice, opts *echotron.CreateInvoiceLinkOptions) (echotron.APIResponseBase, error)
+	AnswerInlineQueryFunc                 func(inlineQueryID string, results []echotron.InlineQueryResult, opts *echotron.InlineQueryOptions) (echotron.APIResponseBase, error)
+	AnswerWebAppQueryFunc                 func(webAppQueryID string, result echotron.InlineQueryResult) (echotron.APIResponseSentWebAppMessage, error)
+	SendGameFunc                          func(gameShortName string, chatID int64, opts *echotron.BaseOptions) (echotron.APIResponseMessage, error)
+	SetGameScoreFunc                      func(userID int64, score int, msgID echotron.MessageIDOptions, opts *echotron.GameScoreOptions) (echotron.APIResponseMessage, error)
+	GetGameHighScoresFunc                 func(userID int64, opts echotron.MessageIDOptions) (echotron.APIResponseGameHighScore, error)
+	SetPassportDataErrorsFunc             func(userID int64, errors []echotron.PassportElementError) (echotron.APIResponseBool, error)
+	CopyMessagesFunc                      func(chatID int64, fromChatID int64, messageIDs []int, opts *echotron.CopyMessagesOptions) (echotron.APIResponseMessageIDArray, error)
+	DeleteMessagesFunc                    func(chatID int64, messageIDs []int) (echotron.APIResponseBool, error)
+	ForwardMessagesFunc                   func(chatID int64, fromChatID int64, messageIDs []int, opts *echotron.ForwardMessagesOptions) (echotron.APIResponseMessageIDArray, error)
+	GetUserChatBoostsFunc                 func(chatID int64, userID int64) (echotron.APIResponseUserChatBoosts, error)
+	SetMessageReactionFunc                func(chatID int64, messageID int, opts *echotron.SetMessageReactionOptions) (echotron.APIResponseBool, error)
+	UnpinAllGeneralForumTopicMessagesFunc func(chatID int64) (echotron.APIResponseBool, error)
+	CallFunc                              func(method string, params any, files map[string]echotron.InputFile) (echotron.APIResponseRaw, error)
+
+	calls []MockCall
+	mu    sync.Mutex
+}
+
+// GetUpdates records the call and calls GetUpdatesFunc, if set.
+func (m *Mock) GetUpdates(opts *echotron.UpdateOptions) (echotron.APIResponseUpdate, error) {
+	m.record("GetUpdates", opts)
+	if m.GetUpdatesFunc != nil {
+		return m.GetUpdatesFunc(opts)
+	}
+	var res echotron.APIResponseUpdate
+	res.Ok = true
+	return res, nil
+}
+
+// SetWebhook records the call and calls SetWebhookFunc, if set.
+func (m *Mock) SetWebhook(webhookURL string, dropPendingUpdates bool, opts *echotron.WebhookOptions) (echotron.APIResponseBase, error) {
+	m.record("SetWebhook", webhookURL, dropPendingUpdates, opts)
+	if m.SetWebhookFunc != nil {
+		return m.SetWebhookFunc(webhookURL, dropPendingUpdates, opts)
+	}
+	var res echotron.APIResponseBase
+	res.Ok = true
+	return res, nil
+}
+
+// DeleteWebhook records the call and calls DeleteWebhookFunc, if set.
+func (m *Mock) DeleteWebhook(dropPendingUpdates bool) (echotron.APIResponseBase, error) {
+	m.record("DeleteWebhook", dropPendingUpdates)
+	if m.DeleteWebhookFunc != nil {
+		return m.DeleteWebhookFunc(dropPendingUpdates)
+	}
+	var res echotron.APIResponseBase
+	res.Ok = true
+	return res, nil
+}
+
+// GetWebhookInfo records the call and calls GetWebhookInfoFunc, if set.
+func (m *Mock) GetWebhookInfo() (echotron.APIResponseWebhook, error) {
+	m.record("GetWebhookInfo")
+	if m.GetWebhookInfoFunc != nil {
+		return m.GetWebhookInfoFunc()
+	}
+	var res echotron.APIResponseWebhook
+	res.Ok = true
+	return res, nil
+}
+
+// GetMe records the call and calls GetMeFunc, if set.
+func (m *Mock) GetMe() (echotron.APIResponseUser, error) {
+	m.record("GetMe")
+	if m.GetMeFunc != nil {
+		return m.GetMeFunc()
+	}
+	var res echotron.APIResponseUser
+	res.Ok = true
+	return res, nil
+}
+
+// LogOut records the call and calls LogOutFunc, if set.
+func (m *Mock) LogOut() (echotron.APIResponseBool, error) {
+	m.record("LogOut")
+	if m.LogOutFunc != nil {
+		return m.LogOutFunc()
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// Close records the call and calls CloseFunc, if set.
+func (m *Mock) Close() (echotron.APIResponseBool, error) {
+	m.record("Close")
+	if m.CloseFunc != nil {
+		return m.CloseFunc()
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// SetMyCommands records the call and calls SetMyCommandsFunc, if set.
+func (m *Mock) SetMyCommands(opts *echotron.CommandOptions, commands ...echotron.BotCommand) (echotron.APIResponseBool, error) {
+	m.record("SetMyCommands", opts, commands)
+	if m.SetMyCommandsFunc != nil {
+		return m.SetMyCommandsFunc(opts, commands...)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// DeleteMyCommands records the call and calls DeleteMyCommandsFunc, if set.
+func (m *Mock) DeleteMyCommands(opts *echotron.CommandOptions) (echotron.APIResponseBool, error) {
+	m.record("DeleteMyCommands", opts)
+	if m.DeleteMyCommandsFunc != nil {
+		return m.DeleteMyCommandsFunc(opts)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// GetMyCommands records the call and calls GetMyCommandsFunc, if set.
+func (m *Mock) GetMyCommands(opts *echotron.CommandOptions) (echotron.APIResponseCommands, error) {
+	m.record("GetMyCommands", opts)
+	if m.GetMyCommandsFunc != nil {
+		return m.GetMyCommandsFunc(opts)
+	}
+	var res echotron.APIResponseCommands
+	res.Ok = true
+	return res, nil
+}
+
+// SetMyName records the call and calls SetMyNameFunc, if set.
+func (m *Mock) SetMyName(name string, languageCode string) (echotron.APIResponseBool, error) {
+	m.record("SetMyName", name, languageCode)
+	if m.SetMyNameFunc != nil {
+		return m.SetMyNameFunc(name, languageCode)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// GetMyName records the call and calls GetMyNameFunc, if set.
+func (m *Mock) GetMyName(languageCode string) (echotron.APIResponseBotName, error) {
+	m.record("GetMyName", languageCode)
+	if m.GetMyNameFunc != nil {
+		return m.GetMyNameFunc(languageCode)
+	}
+	var res echotron.APIResponseBotName
+	res.Ok = true
+	return res, nil
+}
+
+// SetMyDescription records the call and calls SetMyDescriptionFunc, if set.
+func (m *Mock) SetMyDescription(description string, languageCode string) (echotron.APIResponseBool, error) {
+	m.record("SetMyDescription", description, languageCode)
+	if m.SetMyDescriptionFunc != nil {
+		return m.SetMyDescriptionFunc(description, languageCode)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// GetMyDescription records the call and calls GetMyDescriptionFunc, if set.
+func (m *Mock) GetMyDescription(languageCode string) (echotron.APIResponseBotDescription, error) {
+	m.record("GetMyDescription", languageCode)
+	if m.GetMyDescriptionFunc != nil {
+		return m.GetMyDescriptionFunc(languageCode)
+	}
+	var res echotron.APIResponseBotDescription
+	res.Ok = true
+	return res, nil
+}
+
+// SetMyShortDescription records the call and calls SetMyShortDescriptionFunc, if set.
+func (m *Mock) SetMyShortDescription(shortDescription string, languageCode string) (echotron.APIResponseBool, error) {
+	m.record("SetMyShortDescription", shortDescription, languageCode)
+	if m.SetMyShortDescriptionFunc != nil {
+		return m.SetMyShortDescriptionFunc(shortDescription, languageCode)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// GetMyShortDescription records the call and calls GetMyShortDescriptionFunc, if set.
+func (m *Mock) GetMyShortDescription(languageCode string) (echotron.APIResponseBotShortDescription, error) {
+	m.record("GetMyShortDescription", languageCode)
+	if m.GetMyShortDescriptionFunc != nil {
+		return m.GetMyShortDescriptionFunc(languageCode)
+	}
+	var res echotron.APIResponseBotShortDescription
+	res.Ok = true
+	return res, nil
+}
+
+// SetMyDefaultAdministratorRights records the call and calls SetMyDefaultAdministratorRightsFunc, if set.
+func (m *Mock) SetMyDefaultAdministratorRights(opts echotron.SetMyDefaultAdministratorRightsOptions) (echotron.APIResponseBool, error) {
+	m.record("SetMyDefaultAdministratorRights", opts)
+	if m.SetMyDefaultAdministratorRightsFunc != nil {
+		return m.SetMyDefaultAdministratorRightsFunc(opts)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// GetMyDefaultAdministratorRights records the call and calls GetMyDefaultAdministratorRightsFunc, if set.
+func (m *Mock) GetMyDefaultAdministratorRights(opts echotron.GetMyDefaultAdministratorRightsOptions) (echotron.APIResponseChatAdministratorRights, error) {
+	m.record("GetMyDefaultAdministratorRights", opts)
+	if m.GetMyDefaultAdministratorRightsFunc != nil {
+		return m.GetMyDefaultAdministratorRightsFunc(opts)
+	}
+	var res echotron.APIResponseChatAdministratorRights
+	res.Ok = true
+	return res, nil
+}
+
+// SetChatMenuButton records the call and calls SetChatMenuButtonFunc, if set.
+func (m *Mock) SetChatMenuButton(opts echotron.SetChatMenuButtonOptions) (echotron.APIResponseBool, error) {
+	m.record("SetChatMenuButton", opts)
+	if m.SetChatMenuButtonFunc != nil {
+		return m.SetChatMenuButtonFunc(opts)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// GetChatMenuButton records the call and calls GetChatMenuButtonFunc, if set.
+func (m *Mock) GetChatMenuButton(opts echotron.GetChatMenuButtonOptions) (echotron.APIResponseMenuButton, error) {
+	m.record("GetChatMenuButton", opts)
+	if m.GetChatMenuButtonFunc != nil {
+		return m.GetChatMenuButtonFunc(opts)
+	}
+	var res echotron.APIResponseMenuButton
+	res.Ok = true
+	return res, nil
+}
+
+// SendMessage records the call and calls SendMessageFunc, if set.
+func (m *Mock) SendMessage(text string, chatID int64, opts *echotron.MessageOptions) (echotron.APIResponseMessage, error) {
+	m.record("SendMessage", text, chatID, opts)
+	if m.SendMessageFunc != nil {
+		return m.SendMessageFunc(text, chatID, opts)
+	}
+	var res echotron.APIResponseMessage
+	res.Ok = true
+	return res, nil
+}
+
+// SendMessageWithUserName records the call and calls SendMessageWithUserNameFunc, if set.
+func (m *Mock) SendMessageWithUserName(text string, userName string, opts *echotron.MessageOptions) (echotron.APIResponseMessage, error) {
+	m.record("SendMessageWithUserName", text, userName, opts)
+	if m.SendMessageWithUserNameFunc != nil {
+		return m.SendMessageWithUserNameFunc(text, userName, opts)
+	}
+	var res echotron.APIResponseMessage
+	res.Ok = true
+	return res, nil
+}
+
+// ForwardMessage records the call and calls ForwardMessageFunc, if set.
+func (m *Mock) ForwardMessage(chatID int64, fromChatID int64, messageID int, opts *echotron.ForwardOptions) (echotron.APIResponseMessage, error) {
+	m.record("ForwardMessage", chatID, fromChatID, messageID, opts)
+	if m.ForwardMessageFunc != nil {
+		return m.ForwardMessageFunc(chatID, fromChatID, messageID, opts)
+	}
+	var res echotron.APIResponseMessage
+	res.Ok = true
+	return res, nil
+}
+
+// CopyMessage records the call and calls CopyMessageFunc, if set.
+func (m *Mock) CopyMessage(chatID int64, fromChatID int64, messageID int, opts *echotron.CopyOptions) (echotron.APIResponseMessageID, error) {
+	m.record("CopyMessage", chatID, fromChatID, messageID, opts)
+	if m.CopyMessageFunc != nil {
+		return m.CopyMessageFunc(chatID, fromChatID, messageID, opts)
+	}
+	var res echotron.APIResponseMessageID
+	res.Ok = true
+	return res, nil
+}
+
+// SendPhoto records the call and calls SendPhotoFunc, if set.
+func (m *Mock) SendPhoto(file echotron.InputFile, chatID int64, opts *echotron.PhotoOptions) (echotron.APIResponseMessage, error) {
+	m.record("SendPhoto", file, chatID, opts)
+	if m.SendPhotoFunc != nil {
+		return m.SendPhotoFunc(file, chatID, opts)
+	}
+	var res echotron.APIResponseMessage
+	res.Ok = true
+	return res, nil
+}
+
+// SendAudio records the call and calls SendAudioFunc, if set.
+func (m *Mock) SendAudio(file echotron.InputFile, chatID int64, opts *echotron.AudioOptions) (echotron.APIResponseMessage, error) {
+	m.record("SendAudio", file, chatID, opts)
+	if m.SendAudioFunc != nil {
+		return m.SendAudioFunc(file, chatID, opts)
+	}
+	var res echotron.APIResponseMessage
+	res.Ok = true
+	return res, nil
+}
+
+// SendDocument records the call and calls SendDocumentFunc, if set.
+func (m *Mock) SendDocument(file echotron.InputFile, chatID int64, opts *echotron.DocumentOptions) (echotron.APIResponseMessage, error) {
+	m.record("SendDocument", file, chatID, opts)
+	if m.SendDocumentFunc != nil {
+		return m.SendDocumentFunc(file, chatID, opts)
+	}
+	var res echotron.APIResponseMessage
+	res.Ok = true
+	return res, nil
+}
+
+// SendVideo records the call and calls SendVideoFunc, if set.
+func (m *Mock) SendVideo(file echotron.InputFile, chatID int64, opts *echotron.VideoOptions) (echotron.APIResponseMessage, error) {
+	m.record("SendVideo", file, chatID, opts)
+	if m.SendVideoFunc != nil {
+		return m.SendVideoFunc(file, chatID, opts)
+	}
+	var res echotron.APIResponseMessage
+	res.Ok = true
+	return res, nil
+}
+
+// SendAnimation records the call and calls SendAnimationFunc, if set.
+func (m *Mock) SendAnimation(file echotron.InputFile, chatID int64, opts *echotron.AnimationOptions) (echotron.APIResponseMessage, error) {
+	m.record("SendAnimation", file, chatID, opts)
+	if m.SendAnimationFunc != nil {
+		return m.SendAnimationFunc(file, chatID, opts)
+	}
+	var res echotron.APIResponseMessage
+	res.Ok = true
+	return res, nil
+}
+
+// SendVoice records the call and calls SendVoiceFunc, if set.
+func (m *Mock) SendVoice(file echotron.InputFile, chatID int64, opts *echotron.VoiceOptions) (echotron.APIResponseMessage, error) {
+	m.record("SendVoice", file, chatID, opts)
+	if m.SendVoiceFunc != nil {
+		return m.SendVoiceFunc(file, chatID, opts)
+	}
+	var res echotron.APIResponseMessage
+	res.Ok = true
+	return res, nil
+}
+
+// SendVideoNote records the call and calls SendVideoNoteFunc, if set.
+func (m *Mock) SendVideoNote(file echotron.InputFile, chatID int64, opts *echotron.VideoNoteOptions) (echotron.APIResponseMessage, error) {
+	m.record("SendVideoNote", file, chatID, opts)
+	if m.SendVideoNoteFunc != nil {
+		return m.SendVideoNoteFunc(file, chatID, opts)
+	}
+	var res echotron.APIResponseMessage
+	res.Ok = true
+	return res, nil
+}
+
+// SendMediaGroup records the call and calls SendMediaGroupFunc, if set.
+func (m *Mock) SendMediaGroup(chatID int64, media []echotron.GroupableInputMedia, opts *echotron.MediaGroupOptions) (echotron.APIResponseMessageArray, error) {
+	m.record("SendMediaGroup", chatID, media, opts)
+	if m.SendMediaGroupFunc != nil {
+		return m.SendMediaGroupFunc(chatID, media, opts)
+	}
+	var res echotron.APIResponseMessageArray
+	res.Ok = true
+	return res, nil
+}
+
+// SendLocation records the call and calls SendLocationFunc, if set.
+func (m *Mock) SendLocation(chatID int64, latitude float64, longitude float64, opts *echotron.LocationOptions) (echotron.APIResponseMessage, error) {
+	m.record("SendLocation", chatID, latitude, longitude, opts)
+	if m.SendLocationFunc != nil {
+		return m.SendLocationFunc(chatID, latitude, longitude, opts)
+	}
+	var res echotron.APIResponseMessage
+	res.Ok = true
+	return res, nil
+}
+
+// EditMessageLiveLocation records the call and calls EditMessageLiveLocationFunc, if set.
+func (m *Mock) EditMessageLiveLocation(msg echotron.MessageIDOptions, latitude float64, longitude float64, opts *echotron.EditLocationOptions) (echotron.APIResponseMessage, error) {
+	m.record("EditMessageLiveLocation", msg, latitude, longitude, opts)
+	if m.EditMessageLiveLocationFunc != nil {
+		return m.EditMessageLiveLocationFunc(msg, latitude, longitude, opts)
+	}
+	var res echotron.APIResponseMessage
+	res.Ok = true
+	return res, nil
+}
+
+// StopMessageLiveLocation records the call and calls StopMessageLiveLocationFunc, if set.
+func (m *Mock) StopMessageLiveLocation(msg echotron.MessageIDOptions, opts *echotron.MessageReplyMarkup) (echotron.APIResponseMessage, error) {
+	m.record("StopMessageLiveLocation", msg, opts)
+	if m.StopMessageLiveLocationFunc != nil {
+		return m.StopMessageLiveLocationFunc(msg, opts)
+	}
+	var res echotron.APIResponseMessage
+	res.Ok = true
+	return res, nil
+}
+
+// SendVenue records the call and calls SendVenueFunc, if set.
+func (m *Mock) SendVenue(chatID int64, latitude float64, longitude float64, title string, address string, opts *echotron.VenueOptions) (echotron.APIResponseMessage, error) {
+	m.record("SendVenue", chatID, latitude, longitude, title, address, opts)
+	if m.SendVenueFunc != nil {
+		return m.SendVenueFunc(chatID, latitude, longitude, title, address, opts)
+	}
+	var res echotron.APIResponseMessage
+	res.Ok = true
+	return res, nil
+}
+
+// SendContact records the call and calls SendContactFunc, if set.
+func (m *Mock) SendContact(phoneNumber string, firstName string, chatID int64, opts *echotron.ContactOptions) (echotron.APIResponseMessage, error) {
+	m.record("SendContact", phoneNumber, firstName, chatID, opts)
+	if m.SendContactFunc != nil {
+		return m.SendContactFunc(phoneNumber, firstName, chatID, opts)
+	}
+	var res echotron.APIResponseMessage
+	res.Ok = true
+	return res, nil
+}
+
+// SendPoll records the call and calls SendPollFunc, if set.
+func (m *Mock) SendPoll(chatID int64, question string, options []string, opts *echotron.PollOptions) (echotron.APIResponseMessage, error) {
+	m.record("SendPoll", chatID, question, options, opts)
+	if m.SendPollFunc != nil {
+		return m.SendPollFunc(chatID, question, options, opts)
+	}
+	var res echotron.APIResponseMessage
+	res.Ok = true
+	return res, nil
+}
+
+// SendDice records the call and calls SendDiceFunc, if set.
+func (m *Mock) SendDice(chatID int64, emoji echotron.DiceEmoji, opts *echotron.BaseOptions) (echotron.APIResponseMessage, error) {
+	m.record("SendDice", chatID, emoji, opts)
+	if m.SendDiceFunc != nil {
+		return m.SendDiceFunc(chatID, emoji, opts)
+	}
+	var res echotron.APIResponseMessage
+	res.Ok = true
+	return res, nil
+}
+
+// SendChatAction records the call and calls SendChatActionFunc, if set.
+func (m *Mock) SendChatAction(action echotron.ChatAction, chatID int64, opts *echotron.ChatActionOptions) (echotron.APIResponseBool, error) {
+	m.record("SendChatAction", action, chatID, opts)
+	if m.SendChatActionFunc != nil {
+		return m.SendChatActionFunc(action, chatID, opts)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// EditMessageText records the call and calls EditMessageTextFunc, if set.
+func (m *Mock) EditMessageText(text string, msg echotron.MessageIDOptions, opts *echotron.MessageTextOptions) (echotron.APIResponseMessage, error) {
+	m.record("EditMessageText", text, msg, opts)
+	if m.EditMessageTextFunc != nil {
+		return m.EditMessageTextFunc(text, msg, opts)
+	}
+	var res echotron.APIResponseMessage
+	res.Ok = true
+	return res, nil
+}
+
+// EditMessageCaption records the call and calls EditMessageCaptionFunc, if set.
+func (m *Mock) EditMessageCaption(msg echotron.MessageIDOptions, opts *echotron.MessageCaptionOptions) (echotron.APIResponseMessage, error) {
+	m.record("EditMessageCaption", msg, opts)
+	if m.EditMessageCaptionFunc != nil {
+		return m.EditMessageCaptionFunc(msg, opts)
+	}
+	var res echotron.APIResponseMessage
+	res.Ok = true
+	return res, nil
+}
+
+// EditMessageMedia records the call and calls EditMessageMediaFunc, if set.
+func (m *Mock) EditMessageMedia(msg echotron.MessageIDOptions, media echotron.InputMedia, opts *echotron.MessageReplyMarkup) (echotron.APIResponseMessage, error) {
+	m.record("EditMessageMedia", msg, media, opts)
+	if m.EditMessageMediaFunc != nil {
+		return m.EditMessageMediaFunc(msg, media, opts)
+	}
+	var res echotron.APIResponseMessage
+	res.Ok = true
+	return res, nil
+}
+
+// EditMessageReplyMarkup records the call and calls EditMessageReplyMarkupFunc, if set.
+func (m *Mock) EditMessageReplyMarkup(msg echotron.MessageIDOptions, opts *echotron.MessageReplyMarkup) (echotron.APIResponseMessage, error) {
+	m.record("EditMessageReplyMarkup", msg, opts)
+	if m.EditMessageReplyMarkupFunc != nil {
+		return m.EditMessageReplyMarkupFunc(msg, opts)
+	}
+	var res echotron.APIResponseMessage
+	res.Ok = true
+	return res, nil
+}
+
+// StopPoll records the call and calls StopPollFunc, if set.
+func (m *Mock) StopPoll(chatID int64, messageID int, opts *echotron.MessageReplyMarkup) (echotron.APIResponsePoll, error) {
+	m.record("StopPoll", chatID, messageID, opts)
+	if m.StopPollFunc != nil {
+		return m.StopPollFunc(chatID, messageID, opts)
+	}
+	var res echotron.APIResponsePoll
+	res.Ok = true
+	return res, nil
+}
+
+// DeleteMessage records the call and calls DeleteMessageFunc, if set.
+func (m *Mock) DeleteMessage(chatID int64, messageID int) (echotron.APIResponseBase, error) {
+	m.record("DeleteMessage", chatID, messageID)
+	if m.DeleteMessageFunc != nil {
+		return m.DeleteMessageFunc(chatID, messageID)
+	}
+	var res echotron.APIResponseBase
+	res.Ok = true
+	return res, nil
+}
+
+// AnswerCallbackQuery records the call and calls AnswerCallbackQueryFunc, if set.
+func (m *Mock) AnswerCallbackQuery(callbackID string, opts *echotron.CallbackQueryOptions) (echotron.APIResponseBool, error) {
+	m.record("AnswerCallbackQuery", callbackID, opts)
+	if m.AnswerCallbackQueryFunc != nil {
+		return m.AnswerCallbackQueryFunc(callbackID, opts)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// GetFile records the call and calls GetFileFunc, if set.
+func (m *Mock) GetFile(fileID string) (echotron.APIResponseFile, error) {
+	m.record("GetFile", fileID)
+	if m.GetFileFunc != nil {
+		return m.GetFileFunc(fileID)
+	}
+	var res echotron.APIResponseFile
+	res.Ok = true
+	return res, nil
+}
+
+// DownloadFile records the call and calls DownloadFileFunc, if set.
+func (m *Mock) DownloadFile(filePath string) ([]byte, error) {
+	m.record("DownloadFile", filePath)
+	if m.DownloadFileFunc != nil {
+		return m.DownloadFileFunc(filePath)
+	}
+	return nil, nil
+}
+
+// GetUserProfilePhotos records the call and calls GetUserProfilePhotosFunc, if set.
+func (m *Mock) GetUserProfilePhotos(userID int64, opts *echotron.UserProfileOptions) (echotron.APIResponseUserProfile, error) {
+	m.record("GetUserProfilePhotos", userID, opts)
+	if m.GetUserProfilePhotosFunc != nil {
+		return m.GetUserProfilePhotosFunc(userID, opts)
+	}
+	var res echotron.APIResponseUserProfile
+	res.Ok = true
+	return res, nil
+}
+
+// BanChatMember records the call and calls BanChatMemberFunc, if set.
+func (m *Mock) BanChatMember(chatID int64, userID int64, opts *echotron.BanOptions) (echotron.APIResponseBool, error) {
+	m.record("BanChatMember", chatID, userID, opts)
+	if m.BanChatMemberFunc != nil {
+		return m.BanChatMemberFunc(chatID, userID, opts)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// UnbanChatMember records the call and calls UnbanChatMemberFunc, if set.
+func (m *Mock) UnbanChatMember(chatID int64, userID int64, opts *echotron.UnbanOptions) (echotron.APIResponseBool, error) {
+	m.record("UnbanChatMember", chatID, userID, opts)
+	if m.UnbanChatMemberFunc != nil {
+		return m.UnbanChatMemberFunc(chatID, userID, opts)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// RestrictChatMember records the call and calls RestrictChatMemberFunc, if set.
+func (m *Mock) RestrictChatMember(chatID int64, userID int64, permissions echotron.ChatPermissions, opts *echotron.RestrictOptions) (echotron.APIResponseBool, error) {
+	m.record("RestrictChatMember", chatID, userID, permissions, opts)
+	if m.RestrictChatMemberFunc != nil {
+		return m.RestrictChatMemberFunc(chatID, userID, permissions, opts)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// PromoteChatMember records the call and calls PromoteChatMemberFunc, if set.
+func (m *Mock) PromoteChatMember(chatID int64, userID int64, opts *echotron.PromoteOptions) (echotron.APIResponseBool, error) {
+	m.record("PromoteChatMember", chatID, userID, opts)
+	if m.PromoteChatMemberFunc != nil {
+		return m.PromoteChatMemberFunc(chatID, userID, opts)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// SetChatAdministratorCustomTitle records the call and calls SetChatAdministratorCustomTitleFunc, if set.
+func (m *Mock) SetChatAdministratorCustomTitle(chatID int64, userID int64, customTitle string) (echotron.APIResponseBool, error) {
+	m.record("SetChatAdministratorCustomTitle", chatID, userID, customTitle)
+	if m.SetChatAdministratorCustomTitleFunc != nil {
+		return m.SetChatAdministratorCustomTitleFunc(chatID, userID, customTitle)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// BanChatSenderChat records the call and calls BanChatSenderChatFunc, if set.
+func (m *Mock) BanChatSenderChat(chatID int64, senderChatID int64) (echotron.APIResponseBool, error) {
+	m.record("BanChatSenderChat", chatID, senderChatID)
+	if m.BanChatSenderChatFunc != nil {
+		return m.BanChatSenderChatFunc(chatID, senderChatID)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// UnbanChatSenderChat records the call and calls UnbanChatSenderChatFunc, if set.
+func (m *Mock) UnbanChatSenderChat(chatID int64, senderChatID int64) (echotron.APIResponseBool, error) {
+	m.record("UnbanChatSenderChat", chatID, senderChatID)
+	if m.UnbanChatSenderChatFunc != nil {
+		return m.UnbanChatSenderChatFunc(chatID, senderChatID)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// SetChatPermissions records the call and calls SetChatPermissionsFunc, if set.
+func (m *Mock) SetChatPermissions(chatID int64, permissions echotron.ChatPermissions, opts *echotron.ChatPermissionsOptions) (echotron.APIResponseBool, error) {
+	m.record("SetChatPermissions", chatID, permissions, opts)
+	if m.SetChatPermissionsFunc != nil {
+		return m.SetChatPermissionsFunc(chatID, permissions, opts)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// ExportChatInviteLink records the call and calls ExportChatInviteLinkFunc, if set.
+func (m *Mock) ExportChatInviteLink(chatID int64) (echotron.APIResponseString, error) {
+	m.record("ExportChatInviteLink", chatID)
+	if m.ExportChatInviteLinkFunc != nil {
+		return m.ExportChatInviteLinkFunc(chatID)
+	}
+	var res echotron.APIResponseString
+	res.Ok = true
+	return res, nil
+}
+
+// CreateChatInviteLink records the call and calls CreateChatInviteLinkFunc, if set.
+func (m *Mock) CreateChatInviteLink(chatID int64, opts *echotron.InviteLinkOptions) (echotron.APIResponseInviteLink, error) {
+	m.record("CreateChatInviteLink", chatID, opts)
+	if m.CreateChatInviteLinkFunc != nil {
+		return m.CreateChatInviteLinkFunc(chatID, opts)
+	}
+	var res echotron.APIResponseInviteLink
+	res.Ok = true
+	return res, nil
+}
+
+// EditChatInviteLink records the call and calls EditChatInviteLinkFunc, if set.
+func (m *Mock) EditChatInviteLink(chatID int64, inviteLink string, opts *echotron.InviteLinkOptions) (echotron.APIResponseInviteLink, error) {
+	m.record("EditChatInviteLink", chatID, inviteLink, opts)
+	if m.EditChatInviteLinkFunc != nil {
+		return m.EditChatInviteLinkFunc(chatID, inviteLink, opts)
+	}
+	var res echotron.APIResponseInviteLink
+	res.Ok = true
+	return res, nil
+}
+
+// RevokeChatInviteLink records the call and calls RevokeChatInviteLinkFunc, if set.
+func (m *Mock) RevokeChatInviteLink(chatID int64, inviteLink string) (echotron.APIResponseInviteLink, error) {
+	m.record("RevokeChatInviteLink", chatID, inviteLink)
+	if m.RevokeChatInviteLinkFunc != nil {
+		return m.RevokeChatInviteLinkFunc(chatID, inviteLink)
+	}
+	var res echotron.APIResponseInviteLink
+	res.Ok = true
+	return res, nil
+}
+
+// ApproveChatJoinRequest records the call and calls ApproveChatJoinRequestFunc, if set.
+func (m *Mock) ApproveChatJoinRequest(chatID int64, userID int64) (echotron.APIResponseBool, error) {
+	m.record("ApproveChatJoinRequest", chatID, userID)
+	if m.ApproveChatJoinRequestFunc != nil {
+		return m.ApproveChatJoinRequestFunc(chatID, userID)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// DeclineChatJoinRequest records the call and calls DeclineChatJoinRequestFunc, if set.
+func (m *Mock) DeclineChatJoinRequest(chatID int64, userID int64) (echotron.APIResponseBool, error) {
+	m.record("DeclineChatJoinRequest", chatID, userID)
+	if m.DeclineChatJoinRequestFunc != nil {
+		return m.DeclineChatJoinRequestFunc(chatID, userID)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// SetChatPhoto records the call and calls SetChatPhotoFunc, if set.
+func (m *Mock) SetChatPhoto(file echotron.InputFile, chatID int64) (echotron.APIResponseBool, error) {
+	m.record("SetChatPhoto", file, chatID)
+	if m.SetChatPhotoFunc != nil {
+		return m.SetChatPhotoFunc(file, chatID)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// DeleteChatPhoto records the call and calls DeleteChatPhotoFunc, if set.
+func (m *Mock) DeleteChatPhoto(chatID int64) (echotron.APIResponseBool, error) {
+	m.record("DeleteChatPhoto", chatID)
+	if m.DeleteChatPhotoFunc != nil {
+		return m.DeleteChatPhotoFunc(chatID)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// SetChatTitle records the call and calls SetChatTitleFunc, if set.
+func (m *Mock) SetChatTitle(chatID int64, title string) (echotron.APIResponseBool, error) {
+	m.record("SetChatTitle", chatID, title)
+	if m.SetChatTitleFunc != nil {
+		return m.SetChatTitleFunc(chatID, title)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// SetChatDescription records the call and calls SetChatDescriptionFunc, if set.
+func (m *Mock) SetChatDescription(chatID int64, description string) (echotron.APIResponseBool, error) {
+	m.record("SetChatDescription", chatID, description)
+	if m.SetChatDescriptionFunc != nil {
+		return m.SetChatDescriptionFunc(chatID, description)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// PinChatMessage records the call and calls PinChatMessageFunc, if set.
+func (m *Mock) PinChatMessage(chatID int64, messageID int, opts *echotron.PinMessageOptions) (echotron.APIResponseBool, error) {
+	m.record("PinChatMessage", chatID, messageID, opts)
+	if m.PinChatMessageFunc != nil {
+		return m.PinChatMessageFunc(chatID, messageID, opts)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// UnpinChatMessage records the call and calls UnpinChatMessageFunc, if set.
+func (m *Mock) UnpinChatMessage(chatID int64, messageID int) (echotron.APIResponseBool, error) {
+	m.record("UnpinChatMessage", chatID, messageID)
+	if m.UnpinChatMessageFunc != nil {
+		return m.UnpinChatMessageFunc(chatID, messageID)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// UnpinAllChatMessages records the call and calls UnpinAllChatMessagesFunc, if set.
+func (m *Mock) UnpinAllChatMessages(chatID int64) (echotron.APIResponseBool, error) {
+	m.record("UnpinAllChatMessages", chatID)
+	if m.UnpinAllChatMessagesFunc != nil {
+		return m.UnpinAllChatMessagesFunc(chatID)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// LeaveChat records the call and calls LeaveChatFunc, if set.
+func (m *Mock) LeaveChat(chatID int64) (echotron.APIResponseBool, error) {
+	m.record("LeaveChat", chatID)
+	if m.LeaveChatFunc != nil {
+		return m.LeaveChatFunc(chatID)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// GetChat records the call and calls GetChatFunc, if set.
+func (m *Mock) GetChat(chatID int64) (echotron.APIResponseChat, error) {
+	m.record("GetChat", chatID)
+	if m.GetChatFunc != nil {
+		return m.GetChatFunc(chatID)
+	}
+	var res echotron.APIResponseChat
+	res.Ok = true
+	return res, nil
+}
+
+// GetChatAdministrators records the call and calls GetChatAdministratorsFunc, if set.
+func (m *Mock) GetChatAdministrators(chatID int64) (echotron.APIResponseAdministrators, error) {
+	m.record("GetChatAdministrators", chatID)
+	if m.GetChatAdministratorsFunc != nil {
+		return m.GetChatAdministratorsFunc(chatID)
+	}
+	var res echotron.APIResponseAdministrators
+	res.Ok = true
+	return res, nil
+}
+
+// GetChatMemberCount records the call and calls GetChatMemberCountFunc, if set.
+func (m *Mock) GetChatMemberCount(chatID int64) (echotron.APIResponseInteger, error) {
+	m.record("GetChatMemberCount", chatID)
+	if m.GetChatMemberCountFunc != nil {
+		return m.GetChatMemberCountFunc(chatID)
+	}
+	var res echotron.APIResponseInteger
+	res.Ok = true
+	return res, nil
+}
+
+// GetChatMember records the call and calls GetChatMemberFunc, if set.
+func (m *Mock) GetChatMember(chatID int64, userID int64) (echotron.APIResponseChatMember, error) {
+	m.record("GetChatMember", chatID, userID)
+	if m.GetChatMemberFunc != nil {
+		return m.GetChatMemberFunc(chatID, userID)
+	}
+	var res echotron.APIResponseChatMember
+	res.Ok = true
+	return res, nil
+}
+
+// SetChatStickerSet records the call and calls SetChatStickerSetFunc, if set.
+func (m *Mock) SetChatStickerSet(chatID int64, stickerSetName string) (echotron.APIResponseBool, error) {
+	m.record("SetChatStickerSet", chatID, stickerSetName)
+	if m.SetChatStickerSetFunc != nil {
+		return m.SetChatStickerSetFunc(chatID, stickerSetName)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// DeleteChatStickerSet records the call and calls DeleteChatStickerSetFunc, if set.
+func (m *Mock) DeleteChatStickerSet(chatID int64) (echotron.APIResponseBool, error) {
+	m.record("DeleteChatStickerSet", chatID)
+	if m.DeleteChatStickerSetFunc != nil {
+		return m.DeleteChatStickerSetFunc(chatID)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// CreateForumTopic records the call and calls CreateForumTopicFunc, if set.
+func (m *Mock) CreateForumTopic(chatID int64, name string, opts *echotron.CreateTopicOptions) (echotron.APIResponseForumTopic, error) {
+	m.record("CreateForumTopic", chatID, name, opts)
+	if m.CreateForumTopicFunc != nil {
+		return m.CreateForumTopicFunc(chatID, name, opts)
+	}
+	var res echotron.APIResponseForumTopic
+	res.Ok = true
+	return res, nil
+}
+
+// EditForumTopic records the call and calls EditForumTopicFunc, if set.
+func (m *Mock) EditForumTopic(chatID int64, messageThreadID int64, opts *echotron.EditTopicOptions) (echotron.APIResponseBool, error) {
+	m.record("EditForumTopic", chatID, messageThreadID, opts)
+	if m.EditForumTopicFunc != nil {
+		return m.EditForumTopicFunc(chatID, messageThreadID, opts)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// CloseForumTopic records the call and calls CloseForumTopicFunc, if set.
+func (m *Mock) CloseForumTopic(chatID int64, messageThreadID int64) (echotron.APIResponseBool, error) {
+	m.record("CloseForumTopic", chatID, messageThreadID)
+	if m.CloseForumTopicFunc != nil {
+		return m.CloseForumTopicFunc(chatID, messageThreadID)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// ReopenForumTopic records the call and calls ReopenForumTopicFunc, if set.
+func (m *Mock) ReopenForumTopic(chatID int64, messageThreadID int64) (echotron.APIResponseBool, error) {
+	m.record("ReopenForumTopic", chatID, messageThreadID)
+	if m.ReopenForumTopicFunc != nil {
+		return m.ReopenForumTopicFunc(chatID, messageThreadID)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// DeleteForumTopic records the call and calls DeleteForumTopicFunc, if set.
+func (m *Mock) DeleteForumTopic(chatID int64, messageThreadID int64) (echotron.APIResponseBool, error) {
+	m.record("DeleteForumTopic", chatID, messageThreadID)
+	if m.DeleteForumTopicFunc != nil {
+		return m.DeleteForumTopicFunc(chatID, messageThreadID)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// UnpinAllForumTopicMessages records the call and calls UnpinAllForumTopicMessagesFunc, if set.
+func (m *Mock) UnpinAllForumTopicMessages(chatID int64, messageThreadID int64) (echotron.APIResponseBool, error) {
+	m.record("UnpinAllForumTopicMessages", chatID, messageThreadID)
+	if m.UnpinAllForumTopicMessagesFunc != nil {
+		return m.UnpinAllForumTopicMessagesFunc(chatID, messageThreadID)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// EditGeneralForumTopic records the call and calls EditGeneralForumTopicFunc, if set.
+func (m *Mock) EditGeneralForumTopic(chatID int64, name string) (echotron.APIResponseBool, error) {
+	m.record("EditGeneralForumTopic", chatID, name)
+	if m.EditGeneralForumTopicFunc != nil {
+		return m.EditGeneralForumTopicFunc(chatID, name)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// CloseGeneralForumTopic records the call and calls CloseGeneralForumTopicFunc, if set.
+func (m *Mock) CloseGeneralForumTopic(chatID int64) (echotron.APIResponseBool, error) {
+	m.record("CloseGeneralForumTopic", chatID)
+	if m.CloseGeneralForumTopicFunc != nil {
+		return m.CloseGeneralForumTopicFunc(chatID)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// ReopenGeneralForumTopic records the call and calls ReopenGeneralForumTopicFunc, if set.
+func (m *Mock) ReopenGeneralForumTopic(chatID int64) (echotron.APIResponseBool, error) {
+	m.record("ReopenGeneralForumTopic", chatID)
+	if m.ReopenGeneralForumTopicFunc != nil {
+		return m.ReopenGeneralForumTopicFunc(chatID)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// HideGeneralForumTopic records the call and calls HideGeneralForumTopicFunc, if set.
+func (m *Mock) HideGeneralForumTopic(chatID int64) (echotron.APIResponseBool, error) {
+	m.record("HideGeneralForumTopic", chatID)
+	if m.HideGeneralForumTopicFunc != nil {
+		return m.HideGeneralForumTopicFunc(chatID)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// UnhideGeneralForumTopic records the call and calls UnhideGeneralForumTopicFunc, if set.
+func (m *Mock) UnhideGeneralForumTopic(chatID int64) (echotron.APIResponseBool, error) {
+	m.record("UnhideGeneralForumTopic", chatID)
+	if m.UnhideGeneralForumTopicFunc != nil {
+		return m.UnhideGeneralForumTopicFunc(chatID)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// SendSticker records the call and calls SendStickerFunc, if set.
+func (m *Mock) SendSticker(stickerID string, chatID int64, opts *echotron.StickerOptions) (echotron.APIResponseMessage, error) {
+	m.record("SendSticker", stickerID, chatID, opts)
+	if m.SendStickerFunc != nil {
+		return m.SendStickerFunc(stickerID, chatID, opts)
+	}
+	var res echotron.APIResponseMessage
+	res.Ok = true
+	return res, nil
+}
+
+// GetStickerSet records the call and calls GetStickerSetFunc, if set.
+func (m *Mock) GetStickerSet(name string) (echotron.APIResponseStickerSet, error) {
+	m.record("GetStickerSet", name)
+	if m.GetStickerSetFunc != nil {
+		return m.GetStickerSetFunc(name)
+	}
+	var res echotron.APIResponseStickerSet
+	res.Ok = true
+	return res, nil
+}
+
+// GetCustomEmojiStickers records the call and calls GetCustomEmojiStickersFunc, if set.
+func (m *Mock) GetCustomEmojiStickers(customEmojiIDs ...string) (echotron.APIResponseStickers, error) {
+	m.record("GetCustomEmojiStickers", customEmojiIDs)
+	if m.GetCustomEmojiStickersFunc != nil {
+		return m.GetCustomEmojiStickersFunc(customEmojiIDs...)
+	}
+	var res echotron.APIResponseStickers
+	res.Ok = true
+	return res, nil
+}
+
+// UploadStickerFile records the call and calls UploadStickerFileFunc, if set.
+func (m *Mock) UploadStickerFile(userID int64, sticker echotron.InputFile, format echotron.StickerFormat) (echotron.APIResponseFile, error) {
+	m.record("UploadStickerFile", userID, sticker, format)
+	if m.UploadStickerFileFunc != nil {
+		return m.UploadStickerFileFunc(userID, sticker, format)
+	}
+	var res echotron.APIResponseFile
+	res.Ok = true
+	return res, nil
+}
+
+// CreateNewStickerSet records the call and calls CreateNewStickerSetFunc, if set.
+func (m *Mock) CreateNewStickerSet(userID int64, name string, title string, stickers []echotron.InputSticker, format echotron.StickerFormat, opts *echotron.NewStickerSetOptions) (echotron.APIResponseBool, error) {
+	m.record("CreateNewStickerSet", userID, name, title, stickers, format, opts)
+	if m.CreateNewStickerSetFunc != nil {
+		return m.CreateNewStickerSetFunc(userID, name, title, stickers, format, opts)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// AddStickerToSet records the call and calls AddStickerToSetFunc, if set.
+func (m *Mock) AddStickerToSet(userID int64, name string, sticker echotron.InputSticker) (echotron.APIResponseBool, error) {
+	m.record("AddStickerToSet", userID, name, sticker)
+	if m.AddStickerToSetFunc != nil {
+		return m.AddStickerToSetFunc(userID, name, sticker)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// SetStickerPositionInSet records the call and calls SetStickerPositionInSetFunc, if set.
+func (m *Mock) SetStickerPositionInSet(sticker string, position int) (echotron.APIResponseBase, error) {
+	m.record("SetStickerPositionInSet", sticker, position)
+	if m.SetStickerPositionInSetFunc != nil {
+		return m.SetStickerPositionInSetFunc(sticker, position)
+	}
+	var res echotron.APIResponseBase
+	res.Ok = true
+	return res, nil
+}
+
+// DeleteStickerFromSet records the call and calls DeleteStickerFromSetFunc, if set.
+func (m *Mock) DeleteStickerFromSet(sticker string) (echotron.APIResponseBase, error) {
+	m.record("DeleteStickerFromSet", sticker)
+	if m.DeleteStickerFromSetFunc != nil {
+		return m.DeleteStickerFromSetFunc(sticker)
+	}
+	var res echotron.APIResponseBase
+	res.Ok = true
+	return res, nil
+}
+
+// SetStickerEmojiList records the call and calls SetStickerEmojiListFunc, if set.
+func (m *Mock) SetStickerEmojiList(sticker string, emojis []string) (echotron.APIResponseBool, error) {
+	m.record("SetStickerEmojiList", sticker, emojis)
+	if m.SetStickerEmojiListFunc != nil {
+		return m.SetStickerEmojiListFunc(sticker, emojis)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// SetStickerKeywords records the call and calls SetStickerKeywordsFunc, if set.
+func (m *Mock) SetStickerKeywords(sticker string, keywords []string) (echotron.APIResponseBool, error) {
+	m.record("SetStickerKeywords", sticker, keywords)
+	if m.SetStickerKeywordsFunc != nil {
+		return m.SetStickerKeywordsFunc(sticker, keywords)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// SetStickerMaskPosition records the call and calls SetStickerMaskPositionFunc, if set.
+func (m *Mock) SetStickerMaskPosition(sticker string, mask echotron.MaskPosition) (echotron.APIResponseBool, error) {
+	m.record("SetStickerMaskPosition", sticker, mask)
+	if m.SetStickerMaskPositionFunc != nil {
+		return m.SetStickerMaskPositionFunc(sticker, mask)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// SetStickerSetTitle records the call and calls SetStickerSetTitleFunc, if set.
+func (m *Mock) SetStickerSetTitle(name string, title string) (echotron.APIResponseBool, error) {
+	m.record("SetStickerSetTitle", name, title)
+	if m.SetStickerSetTitleFunc != nil {
+		return m.SetStickerSetTitleFunc(name, title)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// SetStickerSetThumbnail records the call and calls SetStickerSetThumbnailFunc, if set.
+func (m *Mock) SetStickerSetThumbnail(name string, userID int64, thumbnail echotron.InputFile) (echotron.APIResponseBase, error) {
+	m.record("SetStickerSetThumbnail", name, userID, thumbnail)
+	if m.SetStickerSetThumbnailFunc != nil {
+		return m.SetStickerSetThumbnailFunc(name, userID, thumbnail)
+	}
+	var res echotron.APIResponseBase
+	res.Ok = true
+	return res, nil
+}
+
+// SetCustomEmojiStickerSetThumbnail records the call and calls SetCustomEmojiStickerSetThumbnailFunc, if set.
+func (m *Mock) SetCustomEmojiStickerSetThumbnail(name string, emojiID string) (echotron.APIResponseBool, error) {
+	m.record("SetCustomEmojiStickerSetThumbnail", name, emojiID)
+	if m.SetCustomEmojiStickerSetThumbnailFunc != nil {
+		return m.SetCustomEmojiStickerSetThumbnailFunc(name, emojiID)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// DeleteStickerSet records the call and calls DeleteStickerSetFunc, if set.
+func (m *Mock) DeleteStickerSet(name string) (echotron.APIResponseBool, error) {
+	m.record("DeleteStickerSet", name)
+	if m.DeleteStickerSetFunc != nil {
+		return m.DeleteStickerSetFunc(name)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// GetForumTopicIconStickers records the call and calls GetForumTopicIconStickersFunc, if set.
+func (m *Mock) GetForumTopicIconStickers() (echotron.APIResponseStickers, error) {
+	m.record("GetForumTopicIconStickers")
+	if m.GetForumTopicIconStickersFunc != nil {
+		return m.GetForumTopicIconStickersFunc()
+	}
+	var res echotron.APIResponseStickers
+	res.Ok = true
+	return res, nil
+}
+
+// SendInvoice records the call and calls SendInvoiceFunc, if set.
+func (m *Mock) SendInvoice(chatID int64, title string, description string, payload string, providerToken string, currency string, prices []echotron.LabeledPrice, opts *echotron.InvoiceOptions) (echotron.APIResponseMessage, error) {
+	m.record("SendInvoice", chatID, title, description, payload, providerToken, currency, prices, opts)
+	if m.SendInvoiceFunc != nil {
+		return m.SendInvoiceFunc(chatID, title, description, payload, providerToken, currency, prices, opts)
+	}
+	var res echotron.APIResponseMessage
+	res.Ok = true
+	return res, nil
+}
+
+// AnswerShippingQuery records the call and calls AnswerShippingQueryFunc, if set.
+func (m *Mock) AnswerShippingQuery(shippingQueryID string, ok bool, opts *echotron.ShippingQueryOptions) (echotron.APIResponseBase, error) {
+	m.record("AnswerShippingQuery", shippingQueryID, ok, opts)
+	if m.AnswerShippingQueryFunc != nil {
+		return m.AnswerShippingQueryFunc(shippingQueryID, ok, opts)
+	}
+	var res echotron.APIResponseBase
+	res.Ok = true
+	return res, nil
+}
+
+// AnswerPreCheckoutQuery records the call and calls AnswerPreCheckoutQueryFunc, if set.
+func (m *Mock) AnswerPreCheckoutQuery(preCheckoutQueryID string, ok bool, opts *echotron.PreCheckoutOptions) (echotron.APIResponseBase, error) {
+	m.record("AnswerPreCheckoutQuery", preCheckoutQueryID, ok, opts)
+	if m.AnswerPreCheckoutQueryFunc != nil {
+		return m.AnswerPreCheckoutQueryFunc(preCheckoutQueryID, ok, opts)
+	}
+	var res echotron.APIResponseBase
+	res.Ok = true
+	return res, nil
+}
+
+// CreateInvoiceLink records the call and calls CreateInvoiceLinkFunc, if set.
+func (m *Mock) CreateInvoiceLink(title string, description string, payload string, providerToken string, currency string, prices []echotron.LabeledPrice, opts *echotron.CreateInvoiceLinkOptions) (echotron.APIResponseBase, error) {
+	m.record("CreateInvoiceLink", title, description, payload, providerToken, currency, prices, opts)
+	if m.CreateInvoiceLinkFunc != nil {
+		return m.CreateInvoiceLinkFunc(title, description, payload, providerToken, currency, prices, opts)
+	}
+	var res echotron.APIResponseBase
+	res.Ok = true
+	return res, nil
+}
+
+// AnswerInlineQuery records the call and calls AnswerInlineQueryFunc, if set.
+func (m *Mock) AnswerInlineQuery(inlineQueryID string, results []echotron.InlineQueryResult, opts *echotron.InlineQueryOptions) (echotron.APIResponseBase, error) {
+	m.record("AnswerInlineQuery", inlineQueryID, results, opts)
+	if m.AnswerInlineQueryFunc != nil {
+		return m.AnswerInlineQueryFunc(inlineQueryID, results, opts)
+	}
+	var res echotron.APIResponseBase
+	res.Ok = true
+	return res, nil
+}
+
+// AnswerWebAppQuery records the call and calls AnswerWebAppQueryFunc, if set.
+func (m *Mock) AnswerWebAppQuery(webAppQueryID string, result echotron.InlineQueryResult) (echotron.APIResponseSentWebAppMessage, error) {
+	m.record("AnswerWebAppQuery", webAppQueryID, result)
+	if m.AnswerWebAppQueryFunc != nil {
+		return m.AnswerWebAppQueryFunc(webAppQueryID, result)
+	}
+	var res echotron.APIResponseSentWebAppMessage
+	res.Ok = true
+	return res, nil
+}
+
+// SendGame records the call and calls SendGameFunc, if set.
+func (m *Mock) SendGame(gameShortName string, chatID int64, opts *echotron.BaseOptions) (echotron.APIResponseMessage, error) {
+	m.record("SendGame", gameShortName, chatID, opts)
+	if m.SendGameFunc != nil {
+		return m.SendGameFunc(gameShortName, chatID, opts)
+	}
+	var res echotron.APIResponseMessage
+	res.Ok = true
+	return res, nil
+}
+
+// SetGameScore records the call and calls SetGameScoreFunc, if set.
+func (m *Mock) SetGameScore(userID int64, score int, msgID echotron.MessageIDOptions, opts *echotron.GameScoreOptions) (echotron.APIResponseMessage, error) {
+	m.record("SetGameScore", userID, score, msgID, opts)
+	if m.SetGameScoreFunc != nil {
+		return m.SetGameScoreFunc(userID, score, msgID, opts)
+	}
+	var res echotron.APIResponseMessage
+	res.Ok = true
+	return res, nil
+}
+
+// GetGameHighScores records the call and calls GetGameHighScoresFunc, if set.
+func (m *Mock) GetGameHighScores(userID int64, opts echotron.MessageIDOptions) (echotron.APIResponseGameHighScore, error) {
+	m.record("GetGameHighScores", userID, opts)
+	if m.GetGameHighScoresFunc != nil {
+		return m.GetGameHighScoresFunc(userID, opts)
+	}
+	var res echotron.APIResponseGameHighScore
+	res.Ok = true
+	return res, nil
+}
+
+// SetPassportDataErrors records the call and calls SetPassportDataErrorsFunc, if set.
+func (m *Mock) SetPassportDataErrors(userID int64, errors []echotron.PassportElementError) (echotron.APIResponseBool, error) {
+	m.record("SetPassportDataErrors", userID, errors)
+	if m.SetPassportDataErrorsFunc != nil {
+		return m.SetPassportDataErrorsFunc(userID, errors)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// CopyMessages records the call and calls CopyMessagesFunc, if set.
+func (m *Mock) CopyMessages(chatID int64, fromChatID int64, messageIDs []int, opts *echotron.CopyMessagesOptions) (echotron.APIResponseMessageIDArray, error) {
+	m.record("CopyMessages", chatID, fromChatID, messageIDs, opts)
+	if m.CopyMessagesFunc != nil {
+		return m.CopyMessagesFunc(chatID, fromChatID, messageIDs, opts)
+	}
+	var res echotron.APIResponseMessageIDArray
+	res.Ok = true
+	return res, nil
+}
+
+// DeleteMessages records the call and calls DeleteMessagesFunc, if set.
+func (m *Mock) DeleteMessages(chatID int64, messageIDs []int) (echotron.APIResponseBool, error) {
+	m.record("DeleteMessages", chatID, messageIDs)
+	if m.DeleteMessagesFunc != nil {
+		return m.DeleteMessagesFunc(chatID, messageIDs)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// ForwardMessages records the call and calls ForwardMessagesFunc, if set.
+func (m *Mock) ForwardMessages(chatID int64, fromChatID int64, messageIDs []int, opts *echotron.ForwardMessagesOptions) (echotron.APIResponseMessageIDArray, error) {
+	m.record("ForwardMessages", chatID, fromChatID, messageIDs, opts)
+	if m.ForwardMessagesFunc != nil {
+		return m.ForwardMessagesFunc(chatID, fromChatID, messageIDs, opts)
+	}
+	var res echotron.APIResponseMessageIDArray
+	res.Ok = true
+	return res, nil
+}
+
+// GetUserChatBoosts records the call and calls GetUserChatBoostsFunc, if set.
+func (m *Mock) GetUserChatBoosts(chatID int64, userID int64) (echotron.APIResponseUserChatBoosts, error) {
+	m.record("GetUserChatBoosts", chatID, userID)
+	if m.GetUserChatBoostsFunc != nil {
+		return m.GetUserChatBoostsFunc(chatID, userID)
+	}
+	var res echotron.APIResponseUserChatBoosts
+	res.Ok = true
+	return res, nil
+}
+
+// SetMessageReaction records the call and calls SetMessageReactionFunc, if set.
+func (m *Mock) SetMessageReaction(chatID int64, messageID int, opts *echotron.SetMessageReactionOptions) (echotron.APIResponseBool, error) {
+	m.record("SetMessageReaction", chatID, messageID, opts)
+	if m.SetMessageReactionFunc != nil {
+		return m.SetMessageReactionFunc(chatID, messageID, opts)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// UnpinAllGeneralForumTopicMessages records the call and calls UnpinAllGeneralForumTopicMessagesFunc, if set.
+func (m *Mock) UnpinAllGeneralForumTopicMessages(chatID int64) (echotron.APIResponseBool, error) {
+	m.record("UnpinAllGeneralForumTopicMessages", chatID)
+	if m.UnpinAllGeneralForumTopicMessagesFunc != nil {
+		return m.UnpinAllGeneralForumTopicMessagesFunc(chatID)
+	}
+	var res echotron.APIResponseBool
+	res.Ok = true
+	return res, nil
+}
+
+// Call records the call and calls CallFunc, if set.
+func (m *Mock) Call(method string, params any, files map[string]echotron.InputFile) (echotron.APIResponseRaw, error) {
+	m.record("Call", method, params, files)
+	if m.CallFunc != nil {
+		return m.CallFunc(method, params, files)
+	}
+	var res echotron.APIResponseRaw
+	res.Ok = true
+	return res, nil
+}
@@ -0,0 +1,43 @@
+package echotrontest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/NicoNex/echotron/v3"
+)
+
+func greet(m echotron.Messenger, chatID int64) error {
+	_, err := m.SendMessage("hello", chatID, nil)
+	return err
+}
+
+func TestMock(t *testing.T) {
+	var m Mock
+
+	if err := greet(&m, 42); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := m.CallsTo("SendMessage")
+	if len(calls) != 1 || calls[0].Args[0] != "hello" || calls[0].Args[1] != int64(42) {
+		t.Fatalf("unexpected calls %+v", calls)
+	}
+
+	m.SendMessageFunc = func(_ string, _ int64, _ *echotron.MessageOptions) (echotron.APIResponseMessage, error) {
+		return echotron.APIResponseMessage{}, errors.New("blocked")
+	}
+	if err := greet(&m, 42); err == nil || err.Error() != "blocked" {
+		t.Fatalf("expected the error of SendMessageFunc, got %v", err)
+	}
+
+	m.SetMyCommands(nil, echotron.BotCommand{Command: "start"}, echotron.BotCommand{Command: "help"})
+	if args := m.CallsTo("SetMyCommands")[0].Args; len(args[1].([]echotron.BotCommand)) != 2 {
+		t.Fatalf("unexpected arguments %+v", args)
+	}
+
+	m.Reset()
+	if len(m.Calls()) != 0 {
+		t.Fatal("calls have not been reset")
+	}
+}
@@ -0,0 +1,326 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package echotrontest
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NicoNex/echotron/v3"
+)
+
+// NewBotFn is called by the Dispatcher of a Scenario every time it receives an update with a chat ID
+// never encountered before. api is the API object the bot must use to reach the Server of the Scenario.
+type NewBotFn func(api echotron.API, chatID int64) echotron.Bot
+
+// Scenario drives a Bot implementation through a Dispatcher with the updates sent by simulated users
+// and records the calls the bot makes, in order, to an unstarted Server, without HTTP:
+//
+//	sc := echotrontest.NewScenario(t, newBot)
+//	user := sc.User(42)
+//
+//	user.Command("start")
+//	sc.ExpectKeyboard(sc.ExpectMessage("What do you want to do?"), []string{"Buy", "Sell"})
+//
+//	user.Tap("Buy")
+//	sc.ExpectEdit("Thank you for your purchase!")
+//	sc.ExpectNoMoreCalls()
+//
+// The updates are handled synchronously, so a bot waiting for the next update of its session
+// with Session.Wait blocks the Scenario.
+type Scenario struct {
+	t       testing.TB
+	srv     *Server
+	dsp     *echotron.Dispatcher
+	api     echotron.API
+	ignored map[string]bool
+	next    int
+}
+
+// NewScenario returns a new Scenario whose Dispatcher creates the bots with newBot.
+// The Server of the Scenario is closed when the test finishes.
+func NewScenario(t testing.TB, newBot NewBotFn) *Scenario {
+	srv := NewUnstartedServer()
+	t.Cleanup(srv.Close)
+
+	sc := &Scenario{
+		t:       t,
+		srv:     srv,
+		api:     srv.API(),
+		ignored: make(map[string]bool),
+	}
+
+	sc.dsp = echotron.NewDispatcher(srv.Token, func(chatID int64) echotron.Bot {
+		return newBot(sc.api, chatID)
+	})
+	sc.dsp.SetAPI(sc.api)
+	return sc
+}
+
+// Server returns the Server the bots of the Scenario make their calls to.
+func (sc *Scenario) Server() *Server {
+	return sc.srv
+}
+
+// Dispatcher returns the Dispatcher the updates are fed to, eg: to add middlewares.
+func (sc *Scenario) Dispatcher() *echotron.Dispatcher {
+	return sc.dsp
+}
+
+// API returns the API object pointed at the Server of the Scenario.
+func (sc *Scenario) API() echotron.API {
+	return sc.api
+}
+
+// Dispatch assigns the next update ID to u and passes it to the Dispatcher,
+// returning once it has been handled.
+func (sc *Scenario) Dispatch(u *echotron.Update) {
+	sc.srv.mu.Lock()
+	sc.srv.lastID++
+	u.ID = sc.srv.lastID
+	sc.srv.mu.Unlock()
+
+	sc.dsp.Dispatch(u)
+}
+
+// User returns the simulated user with the given ID, acting in its private chat with the bot.
+func (sc *Scenario) User(id int64) *User {
+	return &User{
+		sc:     sc,
+		chatID: id,
+		user:   echotron.User{ID: id, FirstName: "User", Username: "user" + itoa(id)},
+	}
+}
+
+// User is a simulated user sending updates to the bots of a Scenario.
+type User struct {
+	sc     *Scenario
+	user   echotron.User
+	chatID int64
+}
+
+// In returns a copy of the user acting in the chat with the given ID, eg: a group.
+func (u *User) In(chatID int64) *User {
+	c := *u
+	c.chatID = chatID
+	return &c
+}
+
+// Send sends a message with the given text to the bot and returns it.
+func (u *User) Send(text string) *echotron.Message {
+	u.sc.srv.mu.Lock()
+	msg := u.sc.srv.userMessage(u.chatID, u.from(), text)
+	u.sc.srv.mu.Unlock()
+
+	u.sc.Dispatch(&echotron.Update{Message: msg})
+	return msg
+}
+
+// Command sends the command with the given name and arguments to the bot and returns the message.
+func (u *User) Command(name string, args ...string) *echotron.Message {
+	text := "/" + strings.TrimPrefix(name, "/")
+	if len(args) > 0 {
+		text += " " + strings.Join(args, " ")
+	}
+	return u.Send(text)
+}
+
+// Tap presses the button of the inline keyboards sent by the bot in the chat
+// whose text or callback data is label, and returns the callback query.
+// The most recent message is searched first and the test fails if no button is found.
+func (u *User) Tap(label string) *echotron.CallbackQuery {
+	u.sc.t.Helper()
+
+	u.sc.srv.mu.Lock()
+	cq, ok := u.tap(label)
+	u.sc.srv.mu.Unlock()
+
+	if !ok {
+		u.sc.t.Fatalf("echotrontest: no button %q in chat %d", label, u.chatID)
+		return nil
+	}
+
+	u.sc.Dispatch(&echotron.Update{CallbackQuery: cq})
+	return cq
+}
+
+// tap returns a new callback query for the button with the given label, s.mu must be held.
+func (u *User) tap(label string) (*echotron.CallbackQuery, bool) {
+	msgs := u.sc.srv.messages[u.chatID]
+
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if msgs[i].ReplyMarkup == nil {
+			continue
+		}
+
+		for _, row := range msgs[i].ReplyMarkup.InlineKeyboard {
+			for _, b := range row {
+				if b.CallbackData != "" && (b.Text == label || b.CallbackData == label) {
+					cq, err := u.sc.srv.newCallback(u.chatID, msgs[i].ID, u.from(), b.CallbackData)
+					return cq, err == nil
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// InlineQuery sends an inline query with the given text to the bot and returns it.
+func (u *User) InlineQuery(query string) *echotron.InlineQuery {
+	u.sc.srv.mu.Lock()
+	iq := u.sc.srv.newInlineQuery(u.from(), query)
+	u.sc.srv.mu.Unlock()
+
+	u.sc.Dispatch(&echotron.Update{InlineQuery: iq})
+	return iq
+}
+
+// JoinRequest sends a request to join the chat with the given ID and returns it.
+func (u *User) JoinRequest(chatID int64) *echotron.ChatJoinRequest {
+	u.sc.srv.mu.Lock()
+	jr := &echotron.ChatJoinRequest{
+		From:       *u.from(),
+		Chat:       *u.sc.srv.chat(chatID),
+		Date:       int(time.Now().Unix()),
+		UserChatID: u.user.ID,
+	}
+	u.sc.srv.mu.Unlock()
+
+	u.sc.Dispatch(&echotron.Update{ChatJoinRequest: jr})
+	return jr
+}
+
+func (u *User) from() *echotron.User {
+	usr := u.user
+	return &usr
+}
+
+// Ignore makes the assertions skip the calls to the given methods, eg: "sendChatAction".
+func (sc *Scenario) Ignore(methods ...string) {
+	for _, m := range methods {
+		sc.ignored[strings.ToLower(m)] = true
+	}
+}
+
+// Calls returns all the calls made by the bots, in order.
+func (sc *Scenario) Calls() []Call {
+	return sc.srv.Calls()
+}
+
+// Expect returns the next call made by the bots, failing the test if it's not a call to the given method.
+func (sc *Scenario) Expect(method string) Call {
+	sc.t.Helper()
+
+	c, ok := sc.nextCall()
+	if !ok {
+		sc.t.Fatalf("echotrontest: expected a call to %s, got none", method)
+	}
+	if !strings.EqualFold(c.Method, method) {
+		sc.t.Fatalf("echotrontest: expected a call to %s, got %s with %v", method, c.Method, c.Params)
+	}
+	return c
+}
+
+// ExpectMessage returns the next call made by the bots, failing the test if it's not
+// a call to sendMessage with the given text.
+func (sc *Scenario) ExpectMessage(text string) Call {
+	sc.t.Helper()
+
+	c := sc.Expect("sendMessage")
+	if c.Text() != text {
+		sc.t.Fatalf("echotrontest: expected message %q, got %q", text, c.Text())
+	}
+	return c
+}
+
+// ExpectEdit returns the next call made by the bots, failing the test if it's not
+// a call to editMessageText with the given text.
+func (sc *Scenario) ExpectEdit(text string) Call {
+	sc.t.Helper()
+
+	c := sc.Expect("editMessageText")
+	if c.Text() != text {
+		sc.t.Fatalf("echotrontest: expected the message to be edited to %q, got %q", text, c.Text())
+	}
+	return c
+}
+
+// ExpectKeyboard fails the test if the labels of the buttons of the inline keyboard sent with the call
+// are not the given rows.
+func (sc *Scenario) ExpectKeyboard(c Call, rows ...[]string) {
+	sc.t.Helper()
+
+	if got := c.Keyboard(); !reflect.DeepEqual(got, rows) {
+		sc.t.Fatalf("echotrontest: expected keyboard %q, got %q", rows, got)
+	}
+}
+
+// ExpectNoMoreCalls fails the test if the bots made calls not yet checked by the assertions.
+func (sc *Scenario) ExpectNoMoreCalls() {
+	sc.t.Helper()
+
+	if c, ok := sc.nextCall(); ok {
+		sc.t.Fatalf("echotrontest: unexpected call to %s with %v", c.Method, c.Params)
+	}
+}
+
+// nextCall returns the first call not yet checked by the assertions and not ignored.
+func (sc *Scenario) nextCall() (Call, bool) {
+	calls := sc.srv.Calls()
+
+	for sc.next < len(calls) {
+		c := calls[sc.next]
+		sc.next++
+		if !sc.ignored[strings.ToLower(c.Method)] {
+			return c, true
+		}
+	}
+	return Call{}, false
+}
+
+// Text returns the text of the message sent or edited by the call, or its caption.
+func (c Call) Text() string {
+	if t := c.Params.Get("text"); t != "" {
+		return t
+	}
+	return c.Params.Get("caption")
+}
+
+// Keyboard returns the labels of the buttons of the inline keyboard sent with the call, row by row.
+func (c Call) Keyboard() [][]string {
+	var ret [][]string
+
+	if m := replyMarkup(&c); m != nil {
+		for _, row := range m.InlineKeyboard {
+			var labels []string
+			for _, b := range row {
+				labels = append(labels, b.Text)
+			}
+			ret = append(ret, labels)
+		}
+	}
+	return ret
+}
+
+func itoa(i int64) string {
+	return strconv.FormatInt(i, 10)
+}
@@ -0,0 +1,86 @@
+package echotrontest
+
+import (
+	"testing"
+
+	"github.com/NicoNex/echotron/v3"
+)
+
+type shopBot struct {
+	echotron.API
+	chatID int64
+}
+
+func newShopBot(api echotron.API, chatID int64) echotron.Bot {
+	return &shopBot{api, chatID}
+}
+
+func (b *shopBot) Update(u *echotron.Update) {
+	switch {
+	case u.Message != nil && u.Message.Text == "/start":
+		b.SendChatAction(echotron.Typing, b.chatID, nil)
+		b.SendMessage("What do you want to do?", b.chatID, &echotron.MessageOptions{
+			ReplyMarkup: echotron.InlineKeyboardMarkup{
+				InlineKeyboard: [][]echotron.InlineKeyboardButton{
+					{{Text: "Buy", CallbackData: "buy"}, {Text: "Sell", CallbackData: "sell"}},
+				},
+			},
+		})
+
+	case u.CallbackQuery != nil:
+		b.AnswerCallbackQuery(u.CallbackQuery.ID, nil)
+		b.EditMessageText(
+			"Thank you for your purchase!",
+			echotron.NewMessageID(b.chatID, u.CallbackQuery.Message.ID),
+			nil,
+		)
+
+	case u.InlineQuery != nil:
+		b.AnswerInlineQuery(u.InlineQuery.ID, nil, nil)
+
+	case u.ChatJoinRequest != nil:
+		b.ApproveChatJoinRequest(u.ChatJoinRequest.Chat.ID, u.ChatJoinRequest.From.ID)
+	}
+}
+
+func TestScenario(t *testing.T) {
+	var (
+		sc   = NewScenario(t, newShopBot)
+		user = sc.User(42)
+	)
+
+	sc.Ignore("sendChatAction")
+
+	msg := user.Command("start")
+	if len(msg.Entities) != 1 || msg.Entities[0].Length != 6 {
+		t.Fatalf("unexpected command entities %+v", msg.Entities)
+	}
+	sc.ExpectKeyboard(sc.ExpectMessage("What do you want to do?"), []string{"Buy", "Sell"})
+
+	cq := user.Tap("Buy")
+	if cq.Data != "buy" {
+		t.Fatalf("unexpected callback data %q", cq.Data)
+	}
+	sc.Expect("answerCallbackQuery")
+	sc.ExpectEdit("Thank you for your purchase!")
+	sc.ExpectNoMoreCalls()
+
+	if _, ok := sc.Server().CallbackAnswer(cq.ID); !ok {
+		t.Fatal("callback query has not been answered")
+	}
+	if msgs := sc.Server().Messages(42); msgs[len(msgs)-1].ReplyMarkup != nil {
+		t.Fatal("keyboard has not been removed by the edit")
+	}
+
+	iq := user.InlineQuery("shoes")
+	sc.Expect("answerInlineQuery")
+	if _, ok := sc.Server().InlineAnswer(iq.ID); !ok {
+		t.Fatal("inline query has not been answered")
+	}
+
+	user.JoinRequest(-100)
+	if c := sc.Expect("approveChatJoinRequest"); c.Params.Get("user_id") != "42" {
+		t.Fatalf("unexpected call %+v", c)
+	}
+	sc.ExpectNoMoreCalls()
+}
@@ -0,0 +1,720 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package echotrontest provides a fake Telegram Bot API server to test bots offline.
+//
+// The Server keeps the chats, messages, files and callback queries in memory,
+// lets the tests inject updates delivered through getUpdates or the webhook
+// and records every method call made by the bot, so that it can be inspected by the assertions:
+//
+//	srv := echotrontest.NewServer()
+//	defer srv.Close()
+//
+//	api := srv.API()
+//	srv.SendMessage(42, "/start")
+//	...
+//	calls := srv.CallsTo("sendMessage")
+package echotrontest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf16"
+
+	"github.com/NicoNex/echotron/v3"
+)
+
+// DefaultToken is the token accepted by the servers returned by NewServer.
+const DefaultToken = "123456:TEST"
+
+// DefaultUserID is the ID of the user sending the messages injected in the group chats.
+const DefaultUserID = 100
+
+// Call represents a call to a method of the Bot API received by the Server.
+type Call struct {
+	// Time is the time the call has been received at.
+	Time time.Time
+	// Params contains the parameters of the call, both from the query string and the body.
+	Params url.Values
+	// Files contains the uploaded files, keyed by the name of their parameter.
+	Files map[string][]byte
+	// Method is the name of the called method, eg: "sendMessage".
+	Method string
+
+	ctx       context.Context
+	fileNames map[string]string
+}
+
+// Context returns the context of the request, which is done when the client gives up.
+func (c *Call) Context() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
+}
+
+// Error is the error returned by a HandlerFunc to make the call fail with the given code and description.
+type Error struct {
+	Description string
+	Code        int
+	RetryAfter  int
+}
+
+// Error returns the description of the error.
+func (e *Error) Error() string {
+	return e.Description
+}
+
+// HandlerFunc is the function handling a method of the Bot API.
+// The returned result is encoded to JSON in the result field of the response.
+// If the returned error is an *Error, its code and description are sent,
+// any other error results in a 500 Internal Server Error.
+type HandlerFunc func(call *Call) (result any, err error)
+
+type file struct {
+	data []byte
+	echotron.File
+}
+
+type callback struct {
+	answer url.Values
+	query  *echotron.CallbackQuery
+}
+
+type webhook struct {
+	url            string
+	secretToken    string
+	lastError      string
+	lastErrorDate  int64
+	maxConnections int
+	certificate    bool
+}
+
+// Server is a fake Telegram Bot API server listening on a local address.
+type Server struct {
+	// URL is the base URL of the server, to be used as the BaseURL of the API options.
+	// It's empty until the server is started.
+	URL string
+	// Token is the token the bot must use, the calls made with other tokens fail with 401 Unauthorized.
+	Token string
+	// WebhookPort is the port the updates are posted to when the URL of the webhook doesn't have one,
+	// such as the "<hostname>/<path>" URLs set by Dispatcher.ListenWebhook. 80 if empty.
+	WebhookPort string
+
+	srv           *httptest.Server
+	client        *http.Client
+	handlers      map[string]HandlerFunc
+	chats         map[int64]*echotron.Chat
+	messages      map[int64][]*echotron.Message
+	lastMsgID     map[int64]int
+	files         map[string]*file
+	paths         map[string]*file
+	callbacks     map[string]*callback
+	inlineQueries map[string]url.Values
+	notify        chan struct{}
+	poll          chan struct{}
+	closed        chan struct{}
+	webhook       webhook
+	bot           echotron.User
+	updates       []*echotron.Update
+	calls         []Call
+	commands      map[string][]echotron.BotCommand
+	botInfo       map[string]map[string]string
+	menuButton    echotron.MenuButton
+	lastID        int
+	lastFile      int
+	mu            sync.Mutex
+	deliverMu     sync.Mutex
+	closeOnce     sync.Once
+}
+
+// NewServer starts and returns a new Server accepting the DefaultToken.
+// The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := NewUnstartedServer()
+	s.Start()
+	return s
+}
+
+// NewUnstartedServer returns a new Server accepting the DefaultToken without starting it.
+// An unstarted Server can be reached without HTTP with the Client it returns.
+// The caller should call Start to make it listen on a local address, and Close when finished.
+func NewUnstartedServer() *Server {
+	s := &Server{
+		Token:         DefaultToken,
+		handlers:      make(map[string]HandlerFunc),
+		chats:         make(map[int64]*echotron.Chat),
+		messages:      make(map[int64][]*echotron.Message),
+		lastMsgID:     make(map[int64]int),
+		files:         make(map[string]*file),
+		paths:         make(map[string]*file),
+		callbacks:     make(map[string]*callback),
+		inlineQueries: make(map[string]url.Values),
+		commands:      make(map[string][]echotron.BotCommand),
+		botInfo:       make(map[string]map[string]string),
+		menuButton:    echotron.MenuButton{Type: echotron.MenuButtonTypeDefault},
+		notify:        make(chan struct{}),
+		closed:        make(chan struct{}),
+		client:        http.DefaultClient,
+		bot: echotron.User{
+			ID:        1,
+			IsBot:     true,
+			FirstName: "Echotron",
+			Username:  "echotron_test_bot",
+		},
+	}
+
+	s.registerMethods()
+	return s
+}
+
+// Start starts the server on a local address.
+func (s *Server) Start() {
+	s.srv = httptest.NewServer(s)
+	s.client = s.srv.Client()
+	s.URL = s.srv.URL
+}
+
+// Close shuts down the server and blocks until all the outstanding requests have completed.
+func (s *Server) Close() {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+		if s.srv != nil {
+			s.srv.Close()
+		}
+	})
+}
+
+// Client returns an HTTP client that sends the requests directly to the server, without HTTP.
+func (s *Server) Client() *http.Client {
+	return &http.Client{Transport: transport{s}}
+}
+
+// API returns an API object pointed at the server.
+func (s *Server) API() echotron.API {
+	return echotron.NewAPIOptions(s.Token, s.APIOptions())
+}
+
+// APIOptions returns the options pointing an API object at the server.
+// If the server has not been started, the API uses the Client of the server.
+func (s *Server) APIOptions() *echotron.APIOptions {
+	if s.URL == "" {
+		return &echotron.APIOptions{BaseURL: inProcessURL, Client: s.Client()}
+	}
+	return &echotron.APIOptions{BaseURL: s.URL}
+}
+
+// inProcessURL is the base URL of the requests sent to the server by its Client.
+const inProcessURL = "http://echotrontest.invalid"
+
+// transport is the http.RoundTripper passing the requests directly to the server.
+type transport struct {
+	s *Server
+}
+
+func (t transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	var rec = httptest.NewRecorder()
+
+	if r.Body != nil {
+		defer r.Body.Close()
+	}
+	t.s.ServeHTTP(rec, r.Clone(r.Context()))
+	return rec.Result(), nil
+}
+
+// Bot returns the User the bot is seen as, the one returned by getMe.
+func (s *Server) Bot() echotron.User {
+	return s.bot
+}
+
+// Handle sets the handler of the given method, replacing the built-in one, if any.
+// It allows to support the methods not implemented by the Server or to make them fail.
+func (s *Server) Handle(method string, h HandlerFunc) {
+	s.mu.Lock()
+	s.handlers[strings.ToLower(method)] = h
+	s.mu.Unlock()
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if prefix := "/file/bot" + s.Token + "/"; strings.HasPrefix(r.URL.Path, prefix) {
+		s.serveFile(w, strings.TrimPrefix(r.URL.Path, prefix))
+		return
+	}
+
+	token, method, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/bot"), "/")
+	if !ok || !strings.HasPrefix(r.URL.Path, "/bot") {
+		writeError(w, &Error{Code: http.StatusNotFound, Description: "Not Found"})
+		return
+	}
+	if token != s.Token {
+		writeError(w, &Error{Code: http.StatusUnauthorized, Description: "Unauthorized"})
+		return
+	}
+
+	call, err := readCall(r, method)
+	if err != nil {
+		writeError(w, &Error{Code: http.StatusBadRequest, Description: "Bad Request: " + err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	s.calls = append(s.calls, *call)
+	h, ok := s.handlers[strings.ToLower(method)]
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, &Error{Code: http.StatusNotFound, Description: "Not Found"})
+		return
+	}
+
+	res, err := h(call)
+	writeResult(w, res, err)
+}
+
+func readCall(r *http.Request, method string) (*Call, error) {
+	err := r.ParseMultipartForm(32 << 20)
+	if err != nil && err != http.ErrNotMultipart {
+		return nil, err
+	}
+	if err == http.ErrNotMultipart {
+		if err := r.ParseForm(); err != nil {
+			return nil, err
+		}
+	}
+
+	call := &Call{
+		Time:   time.Now(),
+		Params: r.Form,
+		Method: method,
+		ctx:    r.Context(),
+	}
+
+	if r.MultipartForm != nil {
+		call.Files = make(map[string][]byte)
+		call.fileNames = make(map[string]string)
+		for name, hdrs := range r.MultipartForm.File {
+			f, err := hdrs[0].Open()
+			if err != nil {
+				return nil, err
+			}
+			data, err := io.ReadAll(f)
+			f.Close()
+			if err != nil {
+				return nil, err
+			}
+			call.Files[name] = data
+			call.fileNames[name] = hdrs[0].Filename
+		}
+	}
+	return call, nil
+}
+
+func writeResult(w http.ResponseWriter, res any, err error) {
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		Result any  `json:"result"`
+		Ok     bool `json:"ok"`
+	}{res, true})
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	e, ok := err.(*Error)
+	if !ok {
+		e = &Error{Code: http.StatusInternalServerError, Description: "Internal Server Error: " + err.Error()}
+	}
+
+	resp := echotron.APIResponseBase{ErrorCode: e.Code, Description: e.Description}
+	if e.RetryAfter != 0 {
+		resp.Parameters = &echotron.ResponseParameters{RetryAfter: e.RetryAfter}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.Code)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (s *Server) serveFile(w http.ResponseWriter, p string) {
+	s.mu.Lock()
+	f, ok := s.paths[p]
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, &Error{Code: http.StatusNotFound, Description: "Not Found"})
+		return
+	}
+	w.Write(f.data)
+}
+
+// SendUpdate assigns the next update ID to u and makes it available to the bot,
+// either by delivering it to the webhook, if one is set, or through getUpdates.
+// If the webhook delivery fails, the update is kept pending and the error is returned.
+func (s *Server) SendUpdate(u *echotron.Update) error {
+	s.mu.Lock()
+	s.lastID++
+	u.ID = s.lastID
+	s.updates = append(s.updates, u)
+	close(s.notify)
+	s.notify = make(chan struct{})
+	s.mu.Unlock()
+
+	return s.deliver()
+}
+
+// deliver sends the pending updates to the webhook, if any, in order.
+func (s *Server) deliver() error {
+	s.deliverMu.Lock()
+	defer s.deliverMu.Unlock()
+
+	for {
+		s.mu.Lock()
+		wh := s.webhook
+		if wh.url == "" || len(s.updates) == 0 {
+			s.mu.Unlock()
+			return nil
+		}
+		u := s.updates[0]
+		s.mu.Unlock()
+
+		err := s.post(wh, u)
+
+		s.mu.Lock()
+		if err != nil {
+			s.webhook.lastError = err.Error()
+			s.webhook.lastErrorDate = time.Now().Unix()
+			s.mu.Unlock()
+			return err
+		}
+		if len(s.updates) > 0 && s.updates[0] == u {
+			s.updates = s.updates[1:]
+		}
+		s.mu.Unlock()
+	}
+}
+
+func (s *Server) post(wh webhook, u *echotron.Update) error {
+	jsn, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", s.webhookURL(wh.url), bytes.NewReader(jsn))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if wh.secretToken != "" {
+		req.Header.Set("X-Telegram-Bot-Api-Secret-Token", wh.secretToken)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("wrong response from the webhook: %s", resp.Status)
+	}
+	return nil
+}
+
+// webhookURL returns the URL the updates are posted to for the webhook URL set by the bot.
+// Telegram only accepts HTTPS webhooks and the URLs without a scheme, but the test server posts
+// the updates over HTTP, to the WebhookPort if the URL has no port.
+func (s *Server) webhookURL(raw string) string {
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	if u.Port() == "" && s.WebhookPort != "" {
+		u.Host = net.JoinHostPort(u.Hostname(), s.WebhookPort)
+	}
+	return u.String()
+}
+
+// SendMessage injects an update with a message with the given text sent to the bot in the given chat
+// and returns the message. Positive chat IDs are private chats with the user having the same ID,
+// in the other chats the message is sent by the user with ID DefaultUserID.
+func (s *Server) SendMessage(chatID int64, text string) (*echotron.Message, error) {
+	s.mu.Lock()
+	msg := s.userMessage(chatID, s.user(chatID), text)
+	s.mu.Unlock()
+
+	return msg, s.SendUpdate(&echotron.Update{Message: msg})
+}
+
+// PressButton injects an update with a callback query with the given data, as if the user
+// pressed a button of the inline keyboard of the given message, and returns the ID of the query.
+func (s *Server) PressButton(chatID int64, messageID int, data string) (string, error) {
+	s.mu.Lock()
+	cq, err := s.newCallback(chatID, messageID, s.user(chatID), data)
+	s.mu.Unlock()
+
+	if err != nil {
+		return "", err
+	}
+	return cq.ID, s.SendUpdate(&echotron.Update{CallbackQuery: cq})
+}
+
+// userMessage stores a new message with the given text sent by the user in the chat
+// and returns a copy of it, s.mu must be held.
+func (s *Server) userMessage(chatID int64, from *echotron.User, text string) *echotron.Message {
+	msg := s.newMessage(chatID, from)
+	msg.Text = text
+
+	if ent := commandEntity(text); ent != nil {
+		msg.Entities = []*echotron.MessageEntity{ent}
+	}
+	return copyMessage(msg)
+}
+
+// newCallback stores and returns a new callback query on the message sent by the user, s.mu must be held.
+func (s *Server) newCallback(chatID int64, messageID int, from *echotron.User, data string) (*echotron.CallbackQuery, error) {
+	msg := s.message(chatID, messageID)
+	if msg == nil {
+		return nil, fmt.Errorf("echotrontest: message %d not found in chat %d", messageID, chatID)
+	}
+
+	cq := &echotron.CallbackQuery{
+		ID:           strconv.Itoa(len(s.callbacks) + 1),
+		From:         from,
+		Message:      copyMessage(msg),
+		ChatInstance: fmt.Sprint(chatID),
+		Data:         data,
+	}
+	s.callbacks[cq.ID] = &callback{query: cq}
+	return cq, nil
+}
+
+// newInlineQuery registers and returns a new inline query sent by the user, s.mu must be held.
+func (s *Server) newInlineQuery(from *echotron.User, query string) *echotron.InlineQuery {
+	iq := &echotron.InlineQuery{
+		ID:       strconv.Itoa(len(s.inlineQueries) + 1),
+		From:     from,
+		Query:    query,
+		ChatType: "sender",
+	}
+	s.inlineQueries[iq.ID] = nil
+	return iq
+}
+
+// commandEntity returns the entity of the bot command text starts with, if any.
+func commandEntity(text string) *echotron.MessageEntity {
+	if !strings.HasPrefix(text, "/") || len(text) == 1 {
+		return nil
+	}
+
+	cmd, _, _ := strings.Cut(text, " ")
+	return &echotron.MessageEntity{
+		Type:   echotron.BotCommandEntity,
+		Length: len(utf16.Encode([]rune(cmd))),
+	}
+}
+
+// AddChat adds a chat to the server or replaces the one with the same ID.
+// The chats not added are created as needed, as private chats for positive IDs and as supergroups otherwise.
+func (s *Server) AddChat(chat echotron.Chat) {
+	s.mu.Lock()
+	s.chats[chat.ID] = &chat
+	s.mu.Unlock()
+}
+
+// AddFile stores a file with the given path and content, so that it can be retrieved
+// with getFile and downloaded, and returns it.
+func (s *Server) AddFile(filePath string, data []byte) echotron.File {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addFile(filePath, data).File
+}
+
+// Calls returns all the calls received by the server, in order.
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Call(nil), s.calls...)
+}
+
+// CallsTo returns the calls to the given method received by the server, in order.
+func (s *Server) CallsTo(method string) []Call {
+	var ret []Call
+
+	for _, c := range s.Calls() {
+		if strings.EqualFold(c.Method, method) {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+// Messages returns a copy of the messages currently in the chat, sent both by the bot and by the users.
+func (s *Server) Messages(chatID int64) []echotron.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ret := make([]echotron.Message, len(s.messages[chatID]))
+	for i, m := range s.messages[chatID] {
+		ret[i] = *m
+	}
+	return ret
+}
+
+// Message returns a copy of the message with the given ID in the chat, if any.
+func (s *Server) Message(chatID int64, messageID int) (echotron.Message, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if m := s.message(chatID, messageID); m != nil {
+		return *m, true
+	}
+	return echotron.Message{}, false
+}
+
+// CallbackAnswer returns the parameters of the answerCallbackQuery call answering the
+// callback query with the given ID, and whether it has been answered.
+func (s *Server) CallbackAnswer(id string) (url.Values, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cb, ok := s.callbacks[id]; ok && cb.answer != nil {
+		return cb.answer, true
+	}
+	return nil, false
+}
+
+// InlineAnswer returns the parameters of the answerInlineQuery call answering the
+// inline query with the given ID, and whether it has been answered.
+func (s *Server) InlineAnswer(id string) (url.Values, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ans, ok := s.inlineQueries[id]
+	return ans, ok && ans != nil
+}
+
+// File returns the content of the file with the given ID, if any.
+// The files sent by URL are known to the server but have no content.
+func (s *Server) File(fileID string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if f, ok := s.files[fileID]; ok {
+		return f.data, true
+	}
+	return nil, false
+}
+
+// PendingUpdates returns the number of updates not yet received by the bot.
+func (s *Server) PendingUpdates() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.updates)
+}
+
+// user returns the user sending the messages injected in the chat, s.mu must be held.
+func (s *Server) user(chatID int64) *echotron.User {
+	id := chatID
+	if id <= 0 {
+		id = DefaultUserID
+	}
+	return &echotron.User{ID: id, FirstName: "User", Username: fmt.Sprintf("user%d", id)}
+}
+
+// chat returns the chat with the given ID, creating it if needed, s.mu must be held.
+func (s *Server) chat(chatID int64) *echotron.Chat {
+	if c, ok := s.chats[chatID]; ok {
+		return c
+	}
+
+	c := &echotron.Chat{ID: chatID, Type: "supergroup", Title: fmt.Sprintf("Group %d", chatID)}
+	if chatID > 0 {
+		c = &echotron.Chat{ID: chatID, Type: "private", FirstName: "User", Username: fmt.Sprintf("user%d", chatID)}
+	}
+	s.chats[chatID] = c
+	return c
+}
+
+// newMessage stores and returns a new message in the chat, s.mu must be held.
+func (s *Server) newMessage(chatID int64, from *echotron.User) *echotron.Message {
+	s.lastMsgID[chatID]++
+
+	msg := &echotron.Message{
+		ID:   s.lastMsgID[chatID],
+		From: from,
+		Chat: *s.chat(chatID),
+		Date: int(time.Now().Unix()),
+	}
+	s.messages[chatID] = append(s.messages[chatID], msg)
+	return msg
+}
+
+// message returns the message with the given ID in the chat, s.mu must be held.
+func (s *Server) message(chatID int64, messageID int) *echotron.Message {
+	for _, m := range s.messages[chatID] {
+		if m.ID == messageID {
+			return m
+		}
+	}
+	return nil
+}
+
+// addFile stores a new file, s.mu must be held.
+func (s *Server) addFile(filePath string, data []byte) *file {
+	s.lastFile++
+
+	f := &file{
+		data: data,
+		File: echotron.File{
+			FileID:       fmt.Sprintf("file%d", s.lastFile),
+			FileUniqueID: fmt.Sprintf("unique%d", s.lastFile),
+			FilePath:     path.Clean(filePath),
+			FileSize:     int64(len(data)),
+		},
+	}
+	s.files[f.FileID] = f
+	s.paths[f.FilePath] = f
+	return f
+}
+
+// copyMessage returns a deep enough copy of msg to be sent in an update.
+func copyMessage(msg *echotron.Message) *echotron.Message {
+	m := *msg
+	return &m
+}
@@ -0,0 +1,256 @@
+package echotrontest
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/NicoNex/echotron/v3"
+)
+
+func TestSendMessage(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	var (
+		api  = srv.API()
+		opts = &echotron.MessageOptions{
+			ReplyMarkup: echotron.InlineKeyboardMarkup{
+				InlineKeyboard: [][]echotron.InlineKeyboardButton{{{Text: "Yes", CallbackData: "yes"}}},
+			},
+		}
+	)
+
+	res, err := api.SendMessage("hello", 42, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Result.Text != "hello" || res.Result.Chat.ID != 42 || res.Result.From.ID != srv.Bot().ID {
+		t.Fatalf("unexpected message %+v", res.Result)
+	}
+
+	calls := srv.CallsTo("sendMessage")
+	if len(calls) != 1 || calls[0].Params.Get("chat_id") != "42" {
+		t.Fatalf("unexpected calls %+v", calls)
+	}
+
+	id, err := srv.PressButton(42, res.Result.ID, "yes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.AnswerCallbackQuery(id, &echotron.CallbackQueryOptions{Text: "done"}); err != nil {
+		t.Fatal(err)
+	}
+	if ans, ok := srv.CallbackAnswer(id); !ok || ans.Get("text") != "done" {
+		t.Fatalf("unexpected answer %v", ans)
+	}
+
+	if _, err := api.EditMessageText("bye", echotron.NewMessageID(42, res.Result.ID), nil); err != nil {
+		t.Fatal(err)
+	}
+	if msg, _ := srv.Message(42, res.Result.ID); msg.Text != "bye" || msg.ReplyMarkup != nil {
+		t.Fatalf("message not edited %+v", msg)
+	}
+
+	if _, err := api.DeleteMessage(42, res.Result.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.DeleteMessage(42, res.Result.ID); err == nil {
+		t.Fatal("deleting a missing message should fail")
+	}
+}
+
+func TestGetUpdates(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	api := srv.API()
+	if _, err := srv.SendMessage(42, "/start"); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := api.GetUpdates(&echotron.UpdateOptions{Timeout: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Result) != 1 || res.Result[0].Message.Text != "/start" {
+		t.Fatalf("unexpected updates %+v", res.Result)
+	}
+
+	res, err = api.GetUpdates(&echotron.UpdateOptions{Offset: res.Result[0].ID + 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Result) != 0 || srv.PendingUpdates() != 0 {
+		t.Fatal("updates have not been confirmed")
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	updates := make(chan *echotron.Update, 1)
+	dsp := echotron.NewDispatcher(srv.Token, func(_ int64) echotron.Bot {
+		return botFunc(func(u *echotron.Update) { updates <- u })
+	})
+	bot := httptest.NewServer(http.HandlerFunc(dsp.HandleWebhook))
+	defer bot.Close()
+
+	api := srv.API()
+	if _, err := api.SetWebhook(bot.URL, false, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var apiErr *echotron.APIError
+	if _, err := api.GetUpdates(nil); !errors.As(err, &apiErr) || apiErr.ErrorCode() != http.StatusConflict {
+		t.Fatalf("getUpdates should fail with a conflict while the webhook is set, got %v", err)
+	}
+
+	if _, err := srv.SendMessage(42, "hi"); err != nil {
+		t.Fatal(err)
+	}
+	if u := <-updates; u.Message.Text != "hi" {
+		t.Fatalf("unexpected update %+v", u)
+	}
+}
+
+func TestListenWebhook(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+	l.Close()
+
+	var (
+		updates = make(chan *echotron.Update, 1)
+		httpSrv = &http.Server{Addr: "127.0.0.1:" + port}
+		dsp     = echotron.NewDispatcher(srv.Token, func(_ int64) echotron.Bot {
+			return botFunc(func(u *echotron.Update) { updates <- u })
+		})
+	)
+	defer httpSrv.Close()
+
+	srv.WebhookPort = port
+	dsp.SetAPI(srv.API())
+	dsp.SetHTTPServer(httpSrv)
+	go dsp.ListenWebhook("https://127.0.0.1:" + port + "/hook")
+
+	// Wait for the webhook to be set and for its server to listen.
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(5 * time.Millisecond) {
+		info, _ := srv.API().GetWebhookInfo()
+		if conn, err := net.Dial("tcp", "127.0.0.1:"+port); err == nil && info.Result != nil && info.Result.URL != "" {
+			conn.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the webhook hasn't been set")
+		}
+	}
+
+	if info, _ := srv.API().GetWebhookInfo(); info.Result.URL != "127.0.0.1/hook" {
+		t.Fatalf("unexpected webhook URL %q", info.Result.URL)
+	}
+	if _, err := srv.SendMessage(42, "hi"); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case u := <-updates:
+		if u.Message.Text != "hi" {
+			t.Fatalf("unexpected update %+v", u)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the update hasn't been delivered to the webhook")
+	}
+}
+
+func TestFiles(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	var (
+		api     = srv.API()
+		content = []byte("content")
+	)
+
+	res, err := api.SendDocument(echotron.NewInputFileBytes("doc.txt", content), 42, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := api.GetFile(res.Result.Document.FileID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := api.DownloadFile(file.Result.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, content) {
+		t.Fatalf("unexpected content %q", data)
+	}
+}
+
+func TestHandle(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.Handle("sendMessage", func(_ *Call) (any, error) {
+		return nil, &Error{Code: http.StatusTooManyRequests, Description: "Too Many Requests: retry after 5", RetryAfter: 5}
+	})
+
+	_, err := srv.API().SendMessage("hello", 42, nil)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if _, err := srv.API().SendChatAction(echotron.Typing, 42, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := srv.API().GetStickerSet("x"); err == nil {
+		t.Fatal("unimplemented methods should fail")
+	}
+}
+
+type botFunc func(*echotron.Update)
+
+func (f botFunc) Update(u *echotron.Update) {
+	f(u)
+}
+
+func TestChatUsername(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.AddChat(echotron.Chat{ID: -1001, Type: "channel", Username: "news"})
+	chat := srv.API().Chat(echotron.NewChatUsername("news"))
+
+	res, err := chat.SendMessage("hello", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Result.Chat.ID != -1001 {
+		t.Fatalf("unexpected chat %+v", res.Result.Chat)
+	}
+
+	if _, err := chat.EditMessageText("bye", res.Result.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+	if msgs := srv.Messages(-1001); len(msgs) != 1 || msgs[0].Text != "bye" {
+		t.Fatalf("unexpected messages %+v", msgs)
+	}
+
+	if _, err := srv.API().Chat(echotron.NewChatUsername("unknown")).SendMessage("hello", nil); err == nil {
+		t.Fatal("expected an error for an unknown username")
+	}
+}
@@ -0,0 +1,197 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package echotron
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// jsonFields caches the names of the JSON fields of the struct types, see knownFields.
+var jsonFields sync.Map
+
+// knownFields returns the names of the JSON fields of the struct type t.
+func knownFields(t reflect.Type) map[string]bool {
+	if fields, ok := jsonFields.Load(t); ok {
+		return fields.(map[string]bool)
+	}
+
+	fields := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		switch name, _, _ := strings.Cut(f.Tag.Get("json"), ","); {
+		case name == "-" || !f.IsExported():
+			continue
+		case name != "":
+			fields[name] = true
+		case f.Anonymous && f.Type.Kind() == reflect.Struct:
+			for n := range knownFields(f.Type) {
+				fields[n] = true
+			}
+		default:
+			fields[f.Name] = true
+		}
+	}
+
+	jsonFields.Store(t, fields)
+	return fields
+}
+
+// UnknownFields returns the fields of the JSON object data that aren't declared by the struct
+// type of v, or nil if there are none.
+// It can be used to get the fields not supported by echotron yet of the objects nested in the
+// JSON returned by Update.Raw, eg: the ones of a message.
+func UnknownFields(data []byte, v any) (map[string]json.RawMessage, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("echotron: UnknownFields: %v is not a struct", t)
+	}
+	return unknownFields(data, t)
+}
+
+// unknownFields returns the fields of the JSON object b not declared by the struct type t,
+// or nil if there are none.
+func unknownFields(b []byte, t reflect.Type) (map[string]json.RawMessage, error) {
+	var all map[string]json.RawMessage
+
+	if err := json.Unmarshal(b, &all); err != nil {
+		return nil, err
+	}
+
+	var (
+		known   = knownFields(t)
+		unknown map[string]json.RawMessage
+	)
+	for name, v := range all {
+		if !known[name] {
+			if unknown == nil {
+				unknown = make(map[string]json.RawMessage)
+			}
+			unknown[name] = v
+		}
+	}
+	return unknown, nil
+}
+
+// rawJSON is the JSON an object has been decoded from, shared by the copies of the object.
+// The JSON of the objects nested in an Update is extracted from the one of their parent,
+// and the fields of each object are parsed only once, when first requested.
+type rawJSON struct {
+	parent  *rawJSON
+	key     string
+	typ     reflect.Type
+	data    json.RawMessage
+	fields  map[string]json.RawMessage
+	unknown map[string]json.RawMessage
+	once    sync.Once
+}
+
+// child returns the rawJSON of the object of type t found under the given key of r.
+func (r *rawJSON) child(key string, t reflect.Type) *rawJSON {
+	return &rawJSON{parent: r, key: key, typ: t}
+}
+
+func (r *rawJSON) load() {
+	r.once.Do(func() {
+		if r.parent != nil {
+			r.data = r.parent.field(r.key)
+		}
+		if r.data == nil || json.Unmarshal(r.data, &r.fields) != nil {
+			return
+		}
+
+		known := knownFields(r.typ)
+		for name, v := range r.fields {
+			if !known[name] {
+				if r.unknown == nil {
+					r.unknown = make(map[string]json.RawMessage)
+				}
+				r.unknown[name] = v
+			}
+		}
+	})
+}
+
+// raw returns the JSON of the object, or nil if r is nil.
+func (r *rawJSON) raw() json.RawMessage {
+	switch {
+	case r == nil:
+		return nil
+	case r.parent == nil:
+		return r.data
+	}
+	r.load()
+	return r.data
+}
+
+// field returns the JSON of the field of the object with the given name.
+func (r *rawJSON) field(name string) json.RawMessage {
+	r.load()
+	return r.fields[name]
+}
+
+// unknownFields returns the fields of the object not declared by its type, or nil if r is nil.
+func (r *rawJSON) unknownFields() map[string]json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	r.load()
+	return r.unknown
+}
+
+// withUnknownFields appends the unknown fields to the JSON object b, sorted by name.
+func withUnknownFields(b []byte, unknown map[string]json.RawMessage) ([]byte, error) {
+	if len(unknown) == 0 {
+		return b, nil
+	}
+
+	names := make([]string, 0, len(unknown))
+	for name := range unknown {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.Write(bytes.TrimSuffix(bytes.TrimSpace(b), []byte("}")))
+
+	for _, name := range names {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(unknown[name])
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
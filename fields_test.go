@@ -0,0 +1,154 @@
+package echotron
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestUnknownUpdate(t *testing.T) {
+	raw := `{"update_id":3,"message_reaction":{"chat":{"id":42,"type":"group"},"message_id":7,"date":1}}`
+
+	var u Update
+	if err := json.Unmarshal([]byte(raw), &u); err != nil {
+		t.Fatal(err)
+	}
+
+	if u.Type() != UnknownUpdate {
+		t.Fatalf("expected an unknown update, got %q", u.Type())
+	}
+	if _, ok := u.Unknown()["message_reaction"]; !ok || len(u.Unknown()) != 1 {
+		t.Fatalf("unexpected unknown fields %v", u.Unknown())
+	}
+	if id := u.ChatID(); id != 42 {
+		t.Fatalf("expected the chat ID 42, got %d", id)
+	}
+
+	b, err := json.Marshal(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != raw {
+		t.Fatalf("expected %s, got %s", raw, b)
+	}
+}
+
+func TestUnknownMessageFields(t *testing.T) {
+	raw := `{"update_id":1,"message":{"message_id":5,"chat":{"id":42,"type":"private","emoji_status_expiration_date":9},"date":0,"text":"hi","story":{"id":3}}}`
+
+	var u Update
+	if err := json.Unmarshal([]byte(raw), &u); err != nil {
+		t.Fatal(err)
+	}
+
+	if u.Type() != MessageUpdate || len(u.Unknown()) != 0 {
+		t.Fatalf("unexpected update type %q with unknown fields %v", u.Type(), u.Unknown())
+	}
+
+	if s := string(u.Message.Unknown()["story"]); s != `{"id":3}` || len(u.Message.Unknown()) != 1 {
+		t.Fatalf("unexpected unknown message fields %v", u.Message.Unknown())
+	}
+	if s := string(u.Message.Chat.Unknown()["emoji_status_expiration_date"]); s != "9" {
+		t.Fatalf("unexpected unknown chat fields %v", u.Message.Chat.Unknown())
+	}
+	if string(u.Message.Raw()) != `{"message_id":5,"chat":{"id":42,"type":"private","emoji_status_expiration_date":9},"date":0,"text":"hi","story":{"id":3}}` {
+		t.Fatalf("unexpected raw message %s", u.Message.Raw())
+	}
+	if reflect.ValueOf(u.Message.Unknown()).Pointer() != reflect.ValueOf(u.Message.Unknown()).Pointer() {
+		t.Fatal("the unknown fields have been extracted twice")
+	}
+
+	b, err := json.Marshal(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded struct {
+		Message map[string]any `json:"message"`
+	}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Message["story"] == nil || decoded.Message["chat"].(map[string]any)["emoji_status_expiration_date"] != float64(9) {
+		t.Fatalf("unknown fields lost in %s", b)
+	}
+
+	var nested struct {
+		Message json.RawMessage `json:"message"`
+	}
+	if err := json.Unmarshal(u.Raw(), &nested); err != nil {
+		t.Fatal(err)
+	}
+
+	unknown, err := UnknownFields(nested.Message, u.Message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(unknown["story"]); s != `{"id":3}` || len(unknown) != 1 {
+		t.Fatalf("unexpected unknown message fields %v", unknown)
+	}
+
+	if _, err := UnknownFields(nested.Message, 0); err == nil {
+		t.Fatal("expected an error for a non struct type")
+	}
+}
+
+func TestUnknownNestedFields(t *testing.T) {
+	raw := `{"update_id":1,"callback_query":{"id":"1","from":{"id":7,"is_bot":false,"first_name":"A"},"chat_instance":"x","message":{"message_id":5,"chat":{"id":42,"type":"private"},"date":0,"reply_to_message":{"message_id":4,"chat":{"id":42,"type":"private","new_chat_field":1},"date":0,"story":{}}}}}`
+
+	var u Update
+	if err := json.Unmarshal([]byte(raw), &u); err != nil {
+		t.Fatal(err)
+	}
+
+	reply := u.CallbackQuery.Message.ReplyToMessage
+	if _, ok := reply.Unknown()["story"]; !ok || len(u.CallbackQuery.Message.Unknown()) != 0 {
+		t.Fatalf("unexpected unknown fields %v and %v", reply.Unknown(), u.CallbackQuery.Message.Unknown())
+	}
+	if string(reply.Chat.Unknown()["new_chat_field"]) != "1" {
+		t.Fatalf("unexpected unknown chat fields %v", reply.Chat.Unknown())
+	}
+
+	var m Message
+	if err := json.Unmarshal(reply.Raw(), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Raw() != nil || m.Unknown() != nil || m.Chat.Raw() != nil {
+		t.Fatal("a message decoded on its own should have no raw JSON")
+	}
+}
+
+func TestChatComparable(t *testing.T) {
+	var a, b Chat
+	if err := json.Unmarshal([]byte(`{"id":42,"type":"private"}`), &a); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`{"type":"private","id":42}`), &b); err != nil {
+		t.Fatal(err)
+	}
+	if a != b {
+		t.Fatalf("expected %+v to equal %+v", a, b)
+	}
+}
+
+func TestWithUnknownFields(t *testing.T) {
+	tests := []struct {
+		in       string
+		unknown  map[string]json.RawMessage
+		expected string
+	}{
+		{`{"a":1}`, nil, `{"a":1}`},
+		{`{}`, map[string]json.RawMessage{"b": json.RawMessage(`2`)}, `{"b":2}`},
+		{`{"a":1}`, map[string]json.RawMessage{"c": json.RawMessage(`3`), "b": json.RawMessage(`"x"`)}, `{"a":1,"b":"x","c":3}`},
+	}
+
+	for _, tt := range tests {
+		b, err := withUnknownFields([]byte(tt.in), tt.unknown)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != tt.expected {
+			t.Errorf("expected %s, got %s", tt.expected, b)
+		}
+	}
+}
@@ -0,0 +1,345 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package echotron
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// secretTokenHeader is the header Telegram sends the secret token of the webhook in,
+// the Forwarder uses it too to authenticate itself to the workers.
+const secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// UpdateSink is the interface implemented by the destinations updates can be forwarded to
+// instead of being handled by the local Dispatcher, eg: worker processes.
+type UpdateSink interface {
+	// Send delivers the update, it returns nil only once the destination acknowledged it.
+	Send(ctx context.Context, update *Update) error
+}
+
+// ForwardTo returns a Middleware sending each update to sink instead of passing it to next.
+// The updates that can't be delivered are logged and dropped.
+func ForwardTo(sink UpdateSink) Middleware {
+	return func(_ HandlerFunc) HandlerFunc {
+		return func(sessionKey int64, update *Update) {
+			if err := sink.Send(update.Context(), update); err != nil {
+				getLogger().Log(LogError, "echotron.ForwardTo: cannot forward update", "update_id", update.ID, "session_key", sessionKey, "error", err)
+			}
+		}
+	}
+}
+
+// ForwarderOptions contains the optional parameters used by the NewForwarder function.
+type ForwarderOptions struct {
+	// Logger is the Logger the updates that can't be forwarded are logged to when the Forwarder
+	// is used as an http.Handler, if nil the one set with the SetLogger function is used.
+	Logger Logger
+	// Client is the http.Client used to reach the workers, if nil the one set with SetDefaultClient is used.
+	Client *http.Client
+	// SecretToken, if set, is sent to the workers which must be configured with the same one.
+	SecretToken string
+	// Replicas is the number of points each worker has on the hash ring, 100 if 0.
+	Replicas int
+	// MaxRetries is the number of times the delivery of an update is retried, 3 if 0.
+	// A negative value disables the retries.
+	MaxRetries int
+	// RetryInterval is how long to wait before the first retry, it doubles at each one. 500ms if 0.
+	RetryInterval time.Duration
+	// Timeout is how long to wait for a worker to acknowledge an update at each attempt, 10s if 0.
+	Timeout time.Duration
+}
+
+// Forwarder is the UpdateSink relaying the updates to a set of worker endpoints over HTTP.
+// Each update is sent to the worker chosen by consistent hashing on its session key, so that
+// the updates of a chat always reach the same worker, and adding or removing a worker only
+// moves a fraction of the chats.
+// A worker acknowledges an update by replying with a 2xx status code, see WorkerSource.
+type Forwarder struct {
+	logger   Logger
+	client   *http.Client
+	secret   string
+	ring     []uint32
+	nodes    map[uint32]string
+	retries  int
+	interval time.Duration
+	timeout  time.Duration
+}
+
+// NewForwarder returns a new Forwarder relaying the updates to the given worker URLs.
+func NewForwarder(workers []string, opts *ForwarderOptions) *Forwarder {
+	var o ForwarderOptions
+
+	if opts != nil {
+		o = *opts
+	}
+	if o.Replicas <= 0 {
+		o.Replicas = 100
+	}
+	if o.MaxRetries == 0 {
+		o.MaxRetries = 3
+	}
+	if o.RetryInterval <= 0 {
+		o.RetryInterval = 500 * time.Millisecond
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = 10 * time.Second
+	}
+
+	f := &Forwarder{
+		logger:   o.Logger,
+		client:   o.Client,
+		secret:   o.SecretToken,
+		nodes:    make(map[uint32]string, len(workers)*o.Replicas),
+		retries:  o.MaxRetries,
+		interval: o.RetryInterval,
+		timeout:  o.Timeout,
+	}
+
+	for _, w := range workers {
+		for i := 0; i < o.Replicas; i++ {
+			h := hashString(w + "#" + strconv.Itoa(i))
+			if _, ok := f.nodes[h]; !ok {
+				f.nodes[h] = w
+				f.ring = append(f.ring, h)
+			}
+		}
+	}
+	sort.Slice(f.ring, func(i, j int) bool { return f.ring[i] < f.ring[j] })
+	return f
+}
+
+func hashString(s string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return h.Sum32()
+}
+
+func hashKey(key int64) uint32 {
+	var b [8]byte
+
+	binary.BigEndian.PutUint64(b[:], uint64(key))
+	h := fnv.New32a()
+	h.Write(b[:])
+	return h.Sum32()
+}
+
+// Worker returns the URL of the worker the updates of the given session are sent to,
+// or an empty string if the Forwarder has no workers.
+func (f *Forwarder) Worker(sessionKey int64) string {
+	if len(f.ring) == 0 {
+		return ""
+	}
+
+	h := hashKey(sessionKey)
+	i := sort.Search(len(f.ring), func(i int) bool { return f.ring[i] >= h })
+	if i == len(f.ring) {
+		i = 0
+	}
+	return f.nodes[f.ring[i]]
+}
+
+// Send implements the UpdateSink interface.
+// The delivery is retried with an exponential backoff until the worker acknowledges it,
+// MaxRetries is reached or ctx is done.
+func (f *Forwarder) Send(ctx context.Context, update *Update) error {
+	worker := f.Worker(update.ChatID())
+	if worker == "" {
+		return fmt.Errorf("echotron.Forwarder: no workers")
+	}
+
+	body := update.Raw()
+	if len(body) == 0 {
+		var err error
+		if body, err = json.Marshal(update); err != nil {
+			return err
+		}
+	}
+
+	var (
+		err      error
+		interval = f.interval
+	)
+
+	for i := 0; ; i++ {
+		if err = f.post(ctx, worker, body); err == nil {
+			return nil
+		}
+		if i >= f.retries || ctx.Err() != nil {
+			break
+		}
+		if sleepContext(ctx, interval) != nil {
+			break
+		}
+		interval *= 2
+	}
+	return fmt.Errorf("echotron.Forwarder: update %d not acknowledged by %s: %w", update.ID, worker, err)
+}
+
+func (f *Forwarder) post(ctx context.Context, worker string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, worker, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if f.secret != "" {
+		req.Header.Set(secretTokenHeader, f.secret)
+	}
+
+	client := f.client
+	if client == nil {
+		client = getDefaultClient()
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", res.Status)
+	}
+	return nil
+}
+
+// ServeHTTP allows to use the Forwarder as the handler of the webhook URL, in place of
+// Dispatcher.HandleWebhook. Each update is acknowledged to Telegram only once a worker
+// acknowledged it, otherwise Telegram delivers it again later.
+func (f *Forwarder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	update, err := decodeUpdate(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := f.Send(r.Context(), update); err != nil {
+		f.log().Log(LogError, "echotron.Forwarder: cannot forward update", "update_id", update.ID, "error", err)
+		http.Error(w, "update not forwarded", http.StatusBadGateway)
+	}
+}
+
+func (f *Forwarder) log() Logger {
+	if f.logger != nil {
+		return f.logger
+	}
+	return getLogger()
+}
+
+// forwarded is an update received by a WorkerSource with the channel closed once it's been
+// passed to the Dispatcher.
+type forwarded struct {
+	update *Update
+	ack    chan struct{}
+}
+
+// WorkerSource is the UpdateSource receiving the updates relayed by a Forwarder.
+// It's the http.Handler of the worker endpoint: it acknowledges each update once it has
+// been passed to the Dispatcher running the source, and replies with 503 Service Unavailable
+// if that doesn't happen before the request is canceled, so that the Forwarder retries.
+type WorkerSource struct {
+	// Logger is the Logger the rejected requests are logged to,
+	// if nil the one set with the SetLogger function is used.
+	Logger Logger
+	// SecretToken, if set, must match the one of the Forwarder.
+	SecretToken string
+
+	updates chan forwarded
+	once    sync.Once
+}
+
+func (s *WorkerSource) channel() chan forwarded {
+	s.once.Do(func() {
+		s.updates = make(chan forwarded)
+	})
+	return s.updates
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (s *WorkerSource) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.SecretToken != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get(secretTokenHeader)), []byte(s.SecretToken)) != 1 {
+		s.log().Log(LogWarn, "echotron.WorkerSource: invalid secret token", "remote_addr", r.RemoteAddr)
+		http.Error(w, "invalid secret token", http.StatusUnauthorized)
+		return
+	}
+
+	update, err := decodeUpdate(r)
+	if err != nil {
+		s.log().Log(LogError, "echotron.WorkerSource: cannot decode update", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	fwd := forwarded{update: update, ack: make(chan struct{})}
+	select {
+	case s.channel() <- fwd:
+	case <-r.Context().Done():
+		http.Error(w, "worker not running", http.StatusServiceUnavailable)
+		return
+	}
+
+	select {
+	case <-fwd.ack:
+		w.WriteHeader(http.StatusNoContent)
+	case <-r.Context().Done():
+		http.Error(w, "update not handled", http.StatusServiceUnavailable)
+	}
+}
+
+// Run implements the UpdateSource interface.
+func (s *WorkerSource) Run(ctx context.Context, updates chan<- *Update) error {
+	ch := s.channel()
+
+	for {
+		select {
+		case fwd := <-ch:
+			select {
+			case updates <- fwd.update:
+				close(fwd.ack)
+			case <-ctx.Done():
+				return nil
+			}
+
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (s *WorkerSource) log() Logger {
+	if s.Logger != nil {
+		return s.Logger
+	}
+	return getLogger()
+}
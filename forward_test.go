@@ -0,0 +1,158 @@
+package echotron
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestForwarderWorker(t *testing.T) {
+	var (
+		workers = []string{"http://w1", "http://w2", "http://w3"}
+		all     = NewForwarder(workers, nil)
+		less    = NewForwarder(workers[:2], nil)
+		used    = map[string]int{}
+		moved   int
+	)
+
+	for key := int64(0); key < 3000; key++ {
+		w := all.Worker(key)
+		used[w]++
+		if w != all.Worker(key) {
+			t.Fatalf("session %d isn't consistently assigned", key)
+		}
+		if w != workers[2] && less.Worker(key) != w {
+			moved++
+		}
+	}
+
+	for _, w := range workers {
+		if used[w] < 500 {
+			t.Fatalf("unbalanced ring: %v", used)
+		}
+	}
+	if moved != 0 {
+		t.Fatalf("%d sessions moved between the remaining workers", moved)
+	}
+	if NewForwarder(nil, nil).Worker(1) != "" {
+		t.Fatal("expected no worker")
+	}
+}
+
+func TestForwarderToWorkers(t *testing.T) {
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		got         = make(chan string, 10)
+		workers     []string
+	)
+	defer cancel()
+
+	for i := 0; i < 2; i++ {
+		var (
+			name = fmt.Sprint("worker", i)
+			src  = &WorkerSource{SecretToken: "secret"}
+			d    = NewDispatcher("token", func(chatID int64) Bot {
+				return botFunc(func(u *Update) { got <- fmt.Sprint(name, ":", chatID) })
+			})
+			srv = httptest.NewServer(src)
+		)
+		defer srv.Close()
+
+		go d.Run(ctx, src)
+		workers = append(workers, srv.URL)
+	}
+
+	fwd := NewForwarder(workers, &ForwarderOptions{SecretToken: "secret"})
+	for _, chatID := range []int64{1, 2, 3} {
+		if err := fwd.Send(ctx, &Update{ID: int(chatID), Message: &Message{Chat: Chat{ID: chatID}}}); err != nil {
+			t.Fatal(err)
+		}
+
+		want := fmt.Sprint("worker", indexOf(workers, fwd.Worker(chatID)), ":", chatID)
+		select {
+		case s := <-got:
+			if s != want {
+				t.Fatalf("expected %q, got %q", want, s)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("update not dispatched")
+		}
+	}
+
+	bad := NewForwarder(workers, &ForwarderOptions{SecretToken: "wrong", MaxRetries: -1})
+	if err := bad.Send(ctx, &Update{Message: &Message{Chat: Chat{ID: 1}}}); err == nil {
+		t.Fatal("expected the worker to reject the secret token")
+	}
+}
+
+func TestForwarderRetry(t *testing.T) {
+	var (
+		attempts atomic.Int32
+		srv      = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		fwd = NewForwarder([]string{srv.URL}, &ForwarderOptions{RetryInterval: time.Millisecond})
+	)
+	defer srv.Close()
+
+	if err := fwd.Send(context.Background(), &Update{ID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if n := attempts.Load(); n != 3 {
+		t.Fatalf("expected 3 attempts, got %d", n)
+	}
+
+	fwd = NewForwarder([]string{srv.URL}, &ForwarderOptions{MaxRetries: -1})
+	attempts.Store(0)
+	if err := fwd.Send(context.Background(), &Update{ID: 1}); err == nil {
+		t.Fatal("expected an error without retries")
+	}
+}
+
+func TestWorkerSourceSecretToken(t *testing.T) {
+	var (
+		logged []string
+		src    = &WorkerSource{
+			SecretToken: "secret",
+			Logger: LoggerFunc(func(_ LogLevel, msg string, _ ...any) {
+				logged = append(logged, msg)
+			}),
+		}
+		srv = httptest.NewServer(src)
+	)
+	defer srv.Close()
+
+	for _, token := range []string{"", "secre", "secret!"} {
+		req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(`{"update_id":1}`))
+		req.Header.Set(secretTokenHeader, token)
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if res.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("token %q: expected 401, got %s", token, res.Status)
+		}
+	}
+
+	if len(logged) != 3 {
+		t.Fatalf("expected the rejected requests to be logged to the source logger, got %v", logged)
+	}
+}
+
+func indexOf(s []string, v string) int {
+	for i, e := range s {
+		if e == v {
+			return i
+		}
+	}
+	return -1
+}
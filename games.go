@@ -52,7 +52,7 @@ func (a API) SendGame(gameShortName string, chatID int64, opts *BaseOptions) (re
 
 	vals.Set("chat_id", itoa(chatID))
 	vals.Set("game_short_name", gameShortName)
-	return get[APIResponseMessage](a.base, "sendGame", addValues(vals, opts))
+	return get[APIResponseMessage](a, "sendGame", addValues(vals, opts))
 }
 
 // SetGameScore is used to set the score of the specified user in a game.
@@ -61,7 +61,7 @@ func (a API) SetGameScore(userID int64, score int, msgID MessageIDOptions, opts
 
 	vals.Set("user_id", itoa(userID))
 	vals.Set("score", itoa(int64(score)))
-	return get[APIResponseMessage](a.base, "setGameScore", addValues(addValues(vals, msgID), opts))
+	return get[APIResponseMessage](a, "setGameScore", addValues(addValues(vals, msgID), opts))
 }
 
 // GetGameHighScores is used to get data for high score tables.
@@ -69,5 +69,5 @@ func (a API) GetGameHighScores(userID int64, opts MessageIDOptions) (res APIResp
 	var vals = make(url.Values)
 
 	vals.Set("user_id", itoa(userID))
-	return get[APIResponseGameHighScore](a.base, "getGameHighScores", addValues(vals, opts))
+	return get[APIResponseGameHighScore](a, "getGameHighScores", addValues(vals, opts))
 }
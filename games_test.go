@@ -1,3 +1,5 @@
+//go:build apitest
+
 /*
  * Echotron
  * Copyright (C) 2018-2022 The Echotron Devs
@@ -40,6 +42,8 @@ func TestSendGame(t *testing.T) {
 }
 
 func TestGameHighScores(t *testing.T) {
+	requireResult(t, gameMsgTmp, "gameMsgTmp")
+
 	resp, err := api.GetGameHighScores(
 		chatID,
 		NewMessageID(chatID, gameMsgTmp.ID),
@@ -53,6 +57,8 @@ func TestGameHighScores(t *testing.T) {
 }
 
 func TestSetGameScore(t *testing.T) {
+	requireResult(t, gameMsgTmp, "gameMsgTmp")
+
 	var score int
 
 	if len(highScores) > 0 {
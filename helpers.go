@@ -19,17 +19,19 @@
 package echotron
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
+	"time"
 )
 
 func check(r APIResponse) error {
 	if b := r.Base(); !b.Ok {
-		return &APIError{code: b.ErrorCode, desc: b.Description}
+		return &APIError{code: b.ErrorCode, desc: b.Description, params: b.Parameters}
 	}
 	return nil
 }
@@ -109,7 +111,7 @@ func readFile(im InputFile) (content []byte, path string, err error) {
 	return
 }
 
-func sendFile(file, thumbnail InputFile, url, fileType string) (res []byte, err error) {
+func (a API) sendFile(file, thumbnail InputFile, url, fileType string) (res []byte, err error) {
 	var cnt []content
 
 	if file.id != "" {
@@ -129,14 +131,14 @@ func sendFile(file, thumbnail InputFile, url, fileType string) (res []byte, err
 	}
 
 	if len(cnt) > 0 {
-		res, err = sendPostRequest(url, cnt...)
+		res, err = a.sendPostRequest(url, cnt...)
 	} else {
-		res, err = sendGetRequest(url)
+		res, err = a.sendGetRequest(url)
 	}
 	return
 }
 
-func sendMediaFiles(url string, editSingle bool, files ...InputMedia) (res []byte, err error) {
+func (a API) sendMediaFiles(url string, editSingle bool, files ...InputMedia) (res []byte, err error) {
 	var (
 		med []mediaEnvelope
 		cnt []content
@@ -174,13 +176,13 @@ func sendMediaFiles(url string, editSingle bool, files ...InputMedia) (res []byt
 	url = fmt.Sprintf("%s&media=%s", url, jsn)
 
 	if len(cnt) > 0 {
-		return sendPostRequest(url, cnt...)
+		return a.sendPostRequest(url, cnt...)
 	}
 
-	return sendGetRequest(url)
+	return a.sendGetRequest(url)
 }
 
-func sendStickers(url string, stickers ...InputSticker) (res []byte, err error) {
+func (a API) sendStickers(url string, stickers ...InputSticker) (res []byte, err error) {
 	var (
 		sti []stickerEnvelope
 		cnt []content
@@ -211,10 +213,10 @@ func sendStickers(url string, stickers ...InputSticker) (res []byte, err error)
 	}
 
 	if len(cnt) > 0 {
-		return sendPostRequest(url, cnt...)
+		return a.sendPostRequest(url, cnt...)
 	}
 
-	return sendGetRequest(url)
+	return a.sendGetRequest(url)
 }
 
 func serializePerms(permissions ChatPermissions) (string, error) {
@@ -247,85 +249,128 @@ func toInputMedia(media []GroupableInputMedia) (ret []InputMedia) {
 	return ret
 }
 
-func get[T APIResponse](base, endpoint string, vals url.Values) (res T, err error) {
-	url, err := url.JoinPath(base, endpoint)
-	if err != nil {
-		return res, err
+// request performs a call to the given method of the Telegram Bot API through send,
+// running the interceptors of the API around it.
+// params and files are the parameters and files of the call as seen by the interceptors.
+// send is passed a copy of a bound to the context of the span of the call, if any.
+func request[T APIResponse](a API, method string, params url.Values, files map[string]InputFile, send func(a API) ([]byte, error)) (res T, err error) {
+	var span Span
+
+	if a.tracer != nil {
+		var ctx context.Context
+		ctx, span = a.tracer.Start(a.context(), "echotron.api."+method)
+		span.SetAttribute("method", method)
+		if meta, ok := UpdateMetaFromContext(ctx); ok {
+			span.SetAttribute("update_id", meta.UpdateID)
+			span.SetAttribute("session_key", meta.SessionKey)
+		}
+		a.ctx = ctx
 	}
 
-	if vals != nil {
-		if queries := vals.Encode(); queries != "" {
-			url = fmt.Sprintf("%s?%s", url, queries)
+	var call = &APICall{
+		Context: a.context(),
+		Method:  method,
+		Params:  redact(params),
+		Files:   files,
+		Start:   time.Now(),
+	}
+
+	defer func() {
+		call.Duration = time.Since(call.Start)
+		call.Err = err
+		a.after(call)
+		a.logError(method, err)
+
+		if span != nil {
+			if call.Response.ErrorCode != 0 {
+				span.SetAttribute("error_code", call.Response.ErrorCode)
+			}
+			span.End(err)
 		}
+	}()
+
+	if err = a.before(call); err != nil {
+		return
 	}
 
-	cnt, err := sendGetRequest(url)
+	cnt, err := send(a)
 	if err != nil {
-		return res, err
+		return
 	}
 
 	if err = json.Unmarshal(cnt, &res); err != nil {
 		return
 	}
+
+	call.Response = res.Base()
 	err = check(res)
 	return
 }
 
-func postFile[T APIResponse](base, endpoint, fileType string, file, thumbnail InputFile, vals url.Values) (res T, err error) {
-	url, err := joinURL(base, endpoint, vals)
+func get[T APIResponse](a API, endpoint string, vals url.Values) (res T, err error) {
+	url, err := joinURL(a.base, endpoint, vals)
 	if err != nil {
 		return res, err
 	}
 
-	cnt, err := sendFile(file, thumbnail, url, fileType)
+	return request[T](a, endpoint, vals, nil, func(a API) ([]byte, error) {
+		return a.sendGetRequest(url)
+	})
+}
+
+func postFile[T APIResponse](a API, endpoint, fileType string, file, thumbnail InputFile, vals url.Values) (res T, err error) {
+	url, err := joinURL(a.base, endpoint, vals)
 	if err != nil {
 		return res, err
 	}
 
-	if err = json.Unmarshal(cnt, &res); err != nil {
-		return
-	}
+	files := make(map[string]InputFile)
+	addFile(files, fileType, file)
+	addFile(files, "thumbnail", thumbnail)
 
-	err = check(res)
-	return
+	return request[T](a, endpoint, vals, files, func(a API) ([]byte, error) {
+		return a.sendFile(file, thumbnail, url, fileType)
+	})
 }
 
-func postMedia[T APIResponse](base, endpoint string, editSingle bool, vals url.Values, files ...InputMedia) (res T, err error) {
-	url, err := joinURL(base, endpoint, vals)
+func postMedia[T APIResponse](a API, endpoint string, editSingle bool, vals url.Values, files ...InputMedia) (res T, err error) {
+	url, err := joinURL(a.base, endpoint, vals)
 	if err != nil {
 		return res, err
 	}
 
-	cnt, err := sendMediaFiles(url, editSingle, files...)
-	if err != nil {
-		return res, err
-	}
-
-	if err = json.Unmarshal(cnt, &res); err != nil {
-		return
+	callFiles := make(map[string]InputFile)
+	for i, f := range files {
+		addFile(callFiles, fmt.Sprintf("media[%d]", i), f.media())
+		addFile(callFiles, fmt.Sprintf("thumbnail[%d]", i), f.thumbnail())
 	}
 
-	err = check(res)
-	return
+	return request[T](a, endpoint, vals, callFiles, func(a API) ([]byte, error) {
+		return a.sendMediaFiles(url, editSingle, files...)
+	})
 }
 
-func postStickers[T APIResponse](base, endpoint string, vals url.Values, stickers ...InputSticker) (res T, err error) {
-	url, err := joinURL(base, endpoint, vals)
+func postStickers[T APIResponse](a API, endpoint string, vals url.Values, stickers ...InputSticker) (res T, err error) {
+	url, err := joinURL(a.base, endpoint, vals)
 	if err != nil {
 		return res, err
 	}
 
-	cnt, err := sendStickers(url, stickers...)
-	if err != nil {
-		return res, err
+	files := make(map[string]InputFile)
+	for i, s := range stickers {
+		addFile(files, fmt.Sprintf("sticker[%d]", i), s.Sticker)
 	}
 
-	if err = json.Unmarshal(cnt, &res); err != nil {
-		return
-	}
+	return request[T](a, endpoint, vals, files, func(a API) ([]byte, error) {
+		return a.sendStickers(url, stickers...)
+	})
+}
 
-	err = check(res)
-	return
+// addFile adds the file to the map if it's not empty.
+func addFile(files map[string]InputFile, name string, file InputFile) {
+	if file.id != "" || file.url != "" || file.path != "" || len(file.content) > 0 {
+		files[name] = file
+	}
 }
 
 func joinURL(base, endpoint string, vals url.Values) (addr string, err error) {
@@ -354,3 +399,8 @@ func ftoa(f float64) string {
 func btoa(b bool) string {
 	return strconv.FormatBool(b)
 }
+
+func jsonString(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
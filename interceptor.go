@@ -0,0 +1,94 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package echotron
+
+import (
+	"context"
+	"net/url"
+	"time"
+)
+
+// APICall describes a call to a method of the Telegram Bot API, as seen by the interceptors.
+type APICall struct {
+	// Context is the context the call is bound to, see API.WithContext.
+	// The UpdateMeta of the update that caused the call can be retrieved from it with UpdateMetaFromContext.
+	Context context.Context
+	// Start is the time the call started at.
+	Start time.Time
+	// Err is the error returned by the call, set before the After hooks are called.
+	Err error
+	// Params contains the parameters of the call, with the secret ones redacted.
+	Params url.Values
+	// Files contains the files uploaded or referenced by the call, keyed by the name of their parameter.
+	Files map[string]InputFile
+	// Method is the name of the method of the Telegram Bot API, eg: "sendMessage".
+	Method string
+	// Response is the decoded response, set before the After hooks are called.
+	Response APIResponseBase
+	// Duration is the duration of the call, set before the After hooks are called.
+	Duration time.Duration
+}
+
+// Interceptor contains the hooks run before and after each call made by an API object.
+// Either of them can be nil.
+type Interceptor struct {
+	// Before is called before the request is sent.
+	// If it returns an error, the request is not sent and the call fails with that error.
+	Before func(call *APICall) error
+	// After is called once the call is completed, even if it failed.
+	After func(call *APICall)
+}
+
+// secretParams contains the parameters whose values are hidden from the interceptors.
+var secretParams = []string{"secret_token", "provider_token"}
+
+// redact returns a copy of vals with the values of the secret parameters replaced.
+func redact(vals url.Values) url.Values {
+	var ret = make(url.Values, len(vals))
+
+	for k, v := range vals {
+		ret[k] = append([]string(nil), v...)
+	}
+
+	for _, k := range secretParams {
+		if _, ok := ret[k]; ok {
+			ret.Set(k, "REDACTED")
+		}
+	}
+	return ret
+}
+
+func (a API) before(call *APICall) error {
+	for _, i := range a.interceptors {
+		if i.Before != nil {
+			if err := i.Before(call); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (a API) after(call *APICall) {
+	for _, i := range a.interceptors {
+		if i.After != nil {
+			i.After(call)
+		}
+	}
+}
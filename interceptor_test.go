@@ -0,0 +1,66 @@
+package echotron
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestServerAPI(t *testing.T, opts *APIOptions, h http.HandlerFunc) API {
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	a := NewAPIOptions("token", opts)
+	a.base = srv.URL + "/bottoken/"
+	return a
+}
+
+func TestInterceptors(t *testing.T) {
+	var (
+		calls []*APICall
+		a     = newTestServerAPI(t, &APIOptions{
+			Interceptors: []Interceptor{{
+				After: func(c *APICall) { calls = append(calls, c) },
+			}},
+		}, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request"}`))
+		})
+	)
+
+	a.SendPhoto(NewInputFileID("photo_id"), 42, &PhotoOptions{Caption: "test"})
+	a.SendInvoice(42, "title", "desc", "payload", "provider_secret", "EUR", nil, nil)
+
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 calls, got %d", len(calls))
+	}
+
+	c := calls[0]
+	if c.Method != "sendPhoto" || c.Params.Get("caption") != "test" || c.Files["photo"].String() != "photo_id" {
+		t.Fatalf("unexpected call %+v", c)
+	}
+	if c.Response.ErrorCode != 400 || c.Err == nil {
+		t.Fatalf("unexpected response %+v, error %v", c.Response, c.Err)
+	}
+	if p := calls[1].Params.Get("provider_token"); p != "REDACTED" {
+		t.Fatalf("provider token has not been redacted: %q", p)
+	}
+}
+
+func TestInterceptorBefore(t *testing.T) {
+	var (
+		sent   bool
+		errFoo = errors.New("foo")
+		a      = newTestServerAPI(t, &APIOptions{
+			Interceptors: []Interceptor{{
+				Before: func(_ *APICall) error { return errFoo },
+			}},
+		}, func(w http.ResponseWriter, r *http.Request) {
+			sent = true
+		})
+	)
+
+	if _, err := a.GetMe(); !errors.Is(err, errFoo) || sent {
+		t.Fatalf("call should have been aborted, got %v", err)
+	}
+}
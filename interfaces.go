@@ -0,0 +1,201 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package echotron
+
+// BotAPI is the interface implemented by the API object, made of smaller interfaces
+// grouped by domain, so that the code calling the Telegram Bot API can depend on
+// the subset of methods it needs and be tested with a mock implementation,
+// such as the one provided by the echotrontest package.
+// The methods are documented on the API object.
+type BotAPI interface {
+	Updater
+	BotManager
+	Messenger
+	FileManager
+	ChatAdmin
+	StickerManager
+	PaymentProvider
+	InlineResponder
+	GameManager
+	PassportManager
+	SpecAPI
+	Caller
+}
+
+var _ BotAPI = API{}
+
+// Updater contains the methods used to receive the updates, with long polling or webhooks.
+type Updater interface {
+	GetUpdates(opts *UpdateOptions) (APIResponseUpdate, error)
+	SetWebhook(webhookURL string, dropPendingUpdates bool, opts *WebhookOptions) (APIResponseBase, error)
+	DeleteWebhook(dropPendingUpdates bool) (APIResponseBase, error)
+	GetWebhookInfo() (APIResponseWebhook, error)
+}
+
+// BotManager contains the methods used to get and change the settings of the bot itself.
+type BotManager interface {
+	GetMe() (APIResponseUser, error)
+	LogOut() (APIResponseBool, error)
+	Close() (APIResponseBool, error)
+	SetMyCommands(opts *CommandOptions, commands ...BotCommand) (APIResponseBool, error)
+	DeleteMyCommands(opts *CommandOptions) (APIResponseBool, error)
+	GetMyCommands(opts *CommandOptions) (APIResponseCommands, error)
+	SetMyName(name, languageCode string) (APIResponseBool, error)
+	GetMyName(languageCode string) (APIResponseBotName, error)
+	SetMyDescription(description, languageCode string) (APIResponseBool, error)
+	GetMyDescription(languageCode string) (APIResponseBotDescription, error)
+	SetMyShortDescription(shortDescription, languageCode string) (APIResponseBool, error)
+	GetMyShortDescription(languageCode string) (APIResponseBotShortDescription, error)
+	SetMyDefaultAdministratorRights(opts SetMyDefaultAdministratorRightsOptions) (APIResponseBool, error)
+	GetMyDefaultAdministratorRights(opts GetMyDefaultAdministratorRightsOptions) (APIResponseChatAdministratorRights, error)
+	SetChatMenuButton(opts SetChatMenuButtonOptions) (APIResponseBool, error)
+	GetChatMenuButton(opts GetChatMenuButtonOptions) (APIResponseMenuButton, error)
+}
+
+// Messenger contains the methods used to send, edit and delete messages and to answer callback queries.
+type Messenger interface {
+	SendMessage(text string, chatID int64, opts *MessageOptions) (APIResponseMessage, error)
+	SendMessageWithUserName(text string, userName string, opts *MessageOptions) (APIResponseMessage, error)
+	ForwardMessage(chatID, fromChatID int64, messageID int, opts *ForwardOptions) (APIResponseMessage, error)
+	CopyMessage(chatID, fromChatID int64, messageID int, opts *CopyOptions) (APIResponseMessageID, error)
+	SendPhoto(file InputFile, chatID int64, opts *PhotoOptions) (APIResponseMessage, error)
+	SendAudio(file InputFile, chatID int64, opts *AudioOptions) (APIResponseMessage, error)
+	SendDocument(file InputFile, chatID int64, opts *DocumentOptions) (APIResponseMessage, error)
+	SendVideo(file InputFile, chatID int64, opts *VideoOptions) (APIResponseMessage, error)
+	SendAnimation(file InputFile, chatID int64, opts *AnimationOptions) (APIResponseMessage, error)
+	SendVoice(file InputFile, chatID int64, opts *VoiceOptions) (APIResponseMessage, error)
+	SendVideoNote(file InputFile, chatID int64, opts *VideoNoteOptions) (APIResponseMessage, error)
+	SendMediaGroup(chatID int64, media []GroupableInputMedia, opts *MediaGroupOptions) (APIResponseMessageArray, error)
+	SendLocation(chatID int64, latitude, longitude float64, opts *LocationOptions) (APIResponseMessage, error)
+	EditMessageLiveLocation(msg MessageIDOptions, latitude, longitude float64, opts *EditLocationOptions) (APIResponseMessage, error)
+	StopMessageLiveLocation(msg MessageIDOptions, opts *MessageReplyMarkup) (APIResponseMessage, error)
+	SendVenue(chatID int64, latitude, longitude float64, title, address string, opts *VenueOptions) (APIResponseMessage, error)
+	SendContact(phoneNumber, firstName string, chatID int64, opts *ContactOptions) (APIResponseMessage, error)
+	SendPoll(chatID int64, question string, options []string, opts *PollOptions) (APIResponseMessage, error)
+	SendDice(chatID int64, emoji DiceEmoji, opts *BaseOptions) (APIResponseMessage, error)
+	SendChatAction(action ChatAction, chatID int64, opts *ChatActionOptions) (APIResponseBool, error)
+	EditMessageText(text string, msg MessageIDOptions, opts *MessageTextOptions) (APIResponseMessage, error)
+	EditMessageCaption(msg MessageIDOptions, opts *MessageCaptionOptions) (APIResponseMessage, error)
+	EditMessageMedia(msg MessageIDOptions, media InputMedia, opts *MessageReplyMarkup) (APIResponseMessage, error)
+	EditMessageReplyMarkup(msg MessageIDOptions, opts *MessageReplyMarkup) (APIResponseMessage, error)
+	StopPoll(chatID int64, messageID int, opts *MessageReplyMarkup) (APIResponsePoll, error)
+	DeleteMessage(chatID int64, messageID int) (APIResponseBase, error)
+	AnswerCallbackQuery(callbackID string, opts *CallbackQueryOptions) (APIResponseBool, error)
+}
+
+// FileManager contains the methods used to download files.
+type FileManager interface {
+	GetFile(fileID string) (APIResponseFile, error)
+	DownloadFile(filePath string) ([]byte, error)
+}
+
+// ChatAdmin contains the methods used to get information about chats and users and to administer chats and their members.
+type ChatAdmin interface {
+	GetUserProfilePhotos(userID int64, opts *UserProfileOptions) (APIResponseUserProfile, error)
+	BanChatMember(chatID, userID int64, opts *BanOptions) (APIResponseBool, error)
+	UnbanChatMember(chatID, userID int64, opts *UnbanOptions) (APIResponseBool, error)
+	RestrictChatMember(chatID, userID int64, permissions ChatPermissions, opts *RestrictOptions) (APIResponseBool, error)
+	PromoteChatMember(chatID, userID int64, opts *PromoteOptions) (APIResponseBool, error)
+	SetChatAdministratorCustomTitle(chatID, userID int64, customTitle string) (APIResponseBool, error)
+	BanChatSenderChat(chatID, senderChatID int64) (APIResponseBool, error)
+	UnbanChatSenderChat(chatID, senderChatID int64) (APIResponseBool, error)
+	SetChatPermissions(chatID int64, permissions ChatPermissions, opts *ChatPermissionsOptions) (APIResponseBool, error)
+	ExportChatInviteLink(chatID int64) (APIResponseString, error)
+	CreateChatInviteLink(chatID int64, opts *InviteLinkOptions) (APIResponseInviteLink, error)
+	EditChatInviteLink(chatID int64, inviteLink string, opts *InviteLinkOptions) (APIResponseInviteLink, error)
+	RevokeChatInviteLink(chatID int64, inviteLink string) (APIResponseInviteLink, error)
+	ApproveChatJoinRequest(chatID, userID int64) (APIResponseBool, error)
+	DeclineChatJoinRequest(chatID, userID int64) (APIResponseBool, error)
+	SetChatPhoto(file InputFile, chatID int64) (APIResponseBool, error)
+	DeleteChatPhoto(chatID int64) (APIResponseBool, error)
+	SetChatTitle(chatID int64, title string) (APIResponseBool, error)
+	SetChatDescription(chatID int64, description string) (APIResponseBool, error)
+	PinChatMessage(chatID int64, messageID int, opts *PinMessageOptions) (APIResponseBool, error)
+	UnpinChatMessage(chatID int64, messageID int) (APIResponseBool, error)
+	UnpinAllChatMessages(chatID int64) (APIResponseBool, error)
+	LeaveChat(chatID int64) (APIResponseBool, error)
+	GetChat(chatID int64) (APIResponseChat, error)
+	GetChatAdministrators(chatID int64) (APIResponseAdministrators, error)
+	GetChatMemberCount(chatID int64) (APIResponseInteger, error)
+	GetChatMember(chatID, userID int64) (APIResponseChatMember, error)
+	SetChatStickerSet(chatID int64, stickerSetName string) (APIResponseBool, error)
+	DeleteChatStickerSet(chatID int64) (APIResponseBool, error)
+	CreateForumTopic(chatID int64, name string, opts *CreateTopicOptions) (APIResponseForumTopic, error)
+	EditForumTopic(chatID, messageThreadID int64, opts *EditTopicOptions) (APIResponseBool, error)
+	CloseForumTopic(chatID, messageThreadID int64) (APIResponseBool, error)
+	ReopenForumTopic(chatID, messageThreadID int64) (APIResponseBool, error)
+	DeleteForumTopic(chatID, messageThreadID int64) (APIResponseBool, error)
+	UnpinAllForumTopicMessages(chatID, messageThreadID int64) (APIResponseBool, error)
+	EditGeneralForumTopic(chatID int64, name string) (APIResponseBool, error)
+	CloseGeneralForumTopic(chatID int64) (APIResponseBool, error)
+	ReopenGeneralForumTopic(chatID int64) (APIResponseBool, error)
+	HideGeneralForumTopic(chatID int64) (APIResponseBool, error)
+	UnhideGeneralForumTopic(chatID int64) (APIResponseBool, error)
+}
+
+// StickerManager contains the methods used to send stickers and to manage sticker sets.
+type StickerManager interface {
+	SendSticker(stickerID string, chatID int64, opts *StickerOptions) (APIResponseMessage, error)
+	GetStickerSet(name string) (APIResponseStickerSet, error)
+	GetCustomEmojiStickers(customEmojiIDs ...string) (APIResponseStickers, error)
+	UploadStickerFile(userID int64, sticker InputFile, format StickerFormat) (APIResponseFile, error)
+	CreateNewStickerSet(userID int64, name, title string, stickers []InputSticker, format StickerFormat, opts *NewStickerSetOptions) (APIResponseBool, error)
+	AddStickerToSet(userID int64, name string, sticker InputSticker) (APIResponseBool, error)
+	SetStickerPositionInSet(sticker string, position int) (APIResponseBase, error)
+	DeleteStickerFromSet(sticker string) (APIResponseBase, error)
+	SetStickerEmojiList(sticker string, emojis []string) (APIResponseBool, error)
+	SetStickerKeywords(sticker string, keywords []string) (APIResponseBool, error)
+	SetStickerMaskPosition(sticker string, mask MaskPosition) (APIResponseBool, error)
+	SetStickerSetTitle(name, title string) (APIResponseBool, error)
+	SetStickerSetThumbnail(name string, userID int64, thumbnail InputFile) (APIResponseBase, error)
+	SetCustomEmojiStickerSetThumbnail(name, emojiID string) (APIResponseBool, error)
+	DeleteStickerSet(name string) (APIResponseBool, error)
+	GetForumTopicIconStickers() (APIResponseStickers, error)
+}
+
+// PaymentProvider contains the methods used to send invoices and to answer shipping and pre-checkout queries.
+type PaymentProvider interface {
+	SendInvoice(chatID int64, title, description, payload, providerToken, currency string, prices []LabeledPrice, opts *InvoiceOptions) (APIResponseMessage, error)
+	AnswerShippingQuery(shippingQueryID string, ok bool, opts *ShippingQueryOptions) (APIResponseBase, error)
+	AnswerPreCheckoutQuery(preCheckoutQueryID string, ok bool, opts *PreCheckoutOptions) (APIResponseBase, error)
+	CreateInvoiceLink(title, description, payload, providerToken, currency string, prices []LabeledPrice, opts *CreateInvoiceLinkOptions) (APIResponseBase, error)
+}
+
+// InlineResponder contains the methods used to answer inline queries and Web App queries.
+type InlineResponder interface {
+	AnswerInlineQuery(inlineQueryID string, results []InlineQueryResult, opts *InlineQueryOptions) (APIResponseBase, error)
+	AnswerWebAppQuery(webAppQueryID string, result InlineQueryResult) (APIResponseSentWebAppMessage, error)
+}
+
+// GameManager contains the methods used to send games and to manage their high scores.
+type GameManager interface {
+	SendGame(gameShortName string, chatID int64, opts *BaseOptions) (APIResponseMessage, error)
+	SetGameScore(userID int64, score int, msgID MessageIDOptions, opts *GameScoreOptions) (APIResponseMessage, error)
+	GetGameHighScores(userID int64, opts MessageIDOptions) (APIResponseGameHighScore, error)
+}
+
+// PassportManager contains the methods used to handle Telegram Passport data.
+type PassportManager interface {
+	SetPassportDataErrors(userID int64, errors []PassportElementError) (APIResponseBool, error)
+}
+
+// Caller contains the method used to call the methods of the Telegram Bot API not supported by echotron yet.
+type Caller interface {
+	Call(method string, params any, files map[string]InputFile) (APIResponseRaw, error)
+}
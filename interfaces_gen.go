@@ -0,0 +1,14 @@
+// Code generated by apigen; DO NOT EDIT.
+
+package echotron
+
+// SpecAPI contains the methods of the API object generated from the Bot API 7.0 specification in spec/botapi.json
+// which have no hand-written counterpart yet.
+type SpecAPI interface {
+	CopyMessages(chatID, fromChatID int64, messageIDs []int, opts *CopyMessagesOptions) (APIResponseMessageIDArray, error)
+	DeleteMessages(chatID int64, messageIDs []int) (APIResponseBool, error)
+	ForwardMessages(chatID, fromChatID int64, messageIDs []int, opts *ForwardMessagesOptions) (APIResponseMessageIDArray, error)
+	GetUserChatBoosts(chatID, userID int64) (APIResponseUserChatBoosts, error)
+	SetMessageReaction(chatID int64, messageID int, opts *SetMessageReactionOptions) (APIResponseBool, error)
+	UnpinAllGeneralForumTopicMessages(chatID int64) (APIResponseBool, error)
+}
@@ -0,0 +1,24 @@
+package echotron
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBotAPICoversAPI(t *testing.T) {
+	var (
+		api   = reflect.TypeOf(API{})
+		iface = reflect.TypeOf((*BotAPI)(nil)).Elem()
+	)
+
+	for i := 0; i < api.NumMethod(); i++ {
+		name := api.Method(i).Name
+		// Chat is left out of BotAPI since it returns a ChatAPI bound to the API itself.
+		if name == "WithContext" || name == "Chat" {
+			continue
+		}
+		if _, ok := iface.MethodByName(name); !ok {
+			t.Errorf("API.%s is missing from the BotAPI interface", name)
+		}
+	}
+}
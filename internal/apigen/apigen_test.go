@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNames(t *testing.T) {
+	for name, want := range map[string][2]string{
+		"chat_id":            {"ChatID", "chatID"},
+		"message_ids":        {"MessageIDs", "messageIDs"},
+		"id":                 {"ID", "id"},
+		"ip_address":         {"IPAddress", "ipAddress"},
+		"MessageId":          {"MessageID", "messageID"},
+		"setMessageReaction": {"SetMessageReaction", "setMessageReaction"},
+		"type":               {"Type", "typeValue"},
+	} {
+		if got := exported(name); got != want[0] {
+			t.Errorf("exported(%q) = %q, want %q", name, got, want[0])
+		}
+		if got := unexported(name); got != want[1] {
+			t.Errorf("unexported(%q) = %q, want %q", name, got, want[1])
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	var (
+		spec = &Spec{
+			Version: "Bot API 0.0",
+			Types: map[string]SpecType{
+				"Shape": {Description: []string{"This object describes a shape. It can be one of"}, Subtypes: []string{"Circle", "Square"}},
+				"Circle": {SubtypeOf: []string{"Shape"}, Fields: []SpecField{
+					{Name: "type", Types: []string{"String"}, Required: true},
+					{Name: "radius", Types: []string{"Float"}, Required: true},
+				}},
+				"Square": {SubtypeOf: []string{"Shape"}, Fields: []SpecField{
+					{Name: "type", Types: []string{"String"}, Required: true},
+					{Name: "side", Types: []string{"Float"}, Required: true},
+				}},
+				"User": {Fields: []SpecField{
+					{Name: "id", Types: []string{"Integer"}, Required: true},
+					{Name: "has_main_web_app", Types: []string{"Boolean"}},
+				}},
+			},
+			Methods: map[string]SpecMethod{
+				"getMe": {Returns: []string{"User"}},
+				"sendShape": {
+					Description: []string{"Use this method to send a shape. On success, the sent Message is returned."},
+					Returns:     []string{"Message"},
+					Fields: []SpecField{
+						{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
+						{Name: "shape", Types: []string{"Shape"}, Required: true},
+						{Name: "disable_notification", Types: []string{"Boolean"}},
+					},
+				},
+				"sendPicture": {
+					Description: []string{"Use this method to send a picture."},
+					Returns:     []string{"Array of Picture"},
+					Fields: []SpecField{
+						{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
+						{Name: "picture", Types: []string{"InputFile", "String"}, Required: true},
+						{Name: "thumbnail", Types: []string{"InputFile", "String"}},
+						{Name: "caption", Types: []string{"String"}},
+					},
+				},
+				"sendAlbum": {
+					Returns: []string{"Array of Message"},
+					Fields: []SpecField{
+						{Name: "media", Types: []string{"Array of InputMediaPhoto"}, Required: true},
+					},
+				},
+			},
+		}
+		p = &pkg{
+			types:     map[string]map[string]bool{"User": {"id": true}, "Message": nil},
+			methods:   map[string]bool{"GetMe": true},
+			responses: map[string]string{"*Message": "APIResponseMessage"},
+			files:     map[string]string{"User": "types.go"},
+		}
+		g = newGenerator(spec, p)
+	)
+
+	g.build()
+	files, err := g.render()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var code = make(map[string]string)
+	for _, f := range files {
+		code[f.name] = string(f.code)
+	}
+
+	for name, want := range map[string][]string{
+		"api_gen.go": {
+			"// SendShape is used to send a shape.\n",
+			"func (a API) SendShape(chatID int64, shape Shape, opts *SendShapeOptions) (res APIResponseMessage, err error) {",
+			`vals.Set("shape", jsonString(shape))`,
+			"func (a API) SendPicture(chatID int64, picture InputFile, opts *SendPictureOptions) (res APIResponsePictureArray, err error) {",
+			"thumbnail = opts.Thumbnail",
+			`return postFile[APIResponsePictureArray](a, "sendPicture", "picture", picture, thumbnail, addValues(vals, opts))`,
+		},
+		"types_gen.go": {
+			"// Shape describes a shape. It's a unique type for Circle and Square.\n",
+			"Radius float64 `json:\"radius,omitempty\"`",
+			"type APIResponsePictureArray struct {",
+			"Result []*Picture `json:\"result,omitempty\"`",
+		},
+		"options_gen.go": {
+			"DisableNotification bool `query:\"disable_notification\"`",
+			"\tThumbnail InputFile\n",
+		},
+		"interfaces_gen.go": {
+			"SendShape(chatID int64, shape Shape, opts *SendShapeOptions) (APIResponseMessage, error)",
+		},
+	} {
+		for _, w := range want {
+			if !strings.Contains(code[name], w) {
+				t.Errorf("%s doesn't contain %q:\n%s", name, w, code[name])
+			}
+		}
+	}
+
+	for _, unwanted := range []string{"GetMe", "SendAlbum", "type User", "type Circle"} {
+		for name, c := range code {
+			if strings.Contains(c, unwanted) {
+				t.Errorf("%s contains %q", name, unwanted)
+			}
+		}
+	}
+
+	if len(g.missing) != 1 || g.missing[0] != "types.go: User is missing the field has_main_web_app" {
+		t.Errorf("unexpected missing fields %v", g.missing)
+	}
+
+	warnings := strings.Join(g.warnings, "\n")
+	for _, w := range []string{"sendAlbum: the method must be implemented by hand"} {
+		if !strings.Contains(warnings, w) {
+			t.Errorf("missing warning %q in:\n%s", w, warnings)
+		}
+	}
+}
+
+// TestUpToDate checks that the generated files of the echotron package match the specification.
+func TestUpToDate(t *testing.T) {
+	dir := filepath.Join("..", "..")
+
+	spec, err := loadSpec(filepath.Join(dir, "spec", "botapi.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := parsePackage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := newGenerator(spec, p)
+	g.build()
+	files, err := g.render()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, m := range g.missing {
+		t.Error(m)
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(filepath.Join(dir, f.name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, f.code) {
+			t.Errorf("%s is out of date, run go generate", f.name)
+		}
+	}
+}
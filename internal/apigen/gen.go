@@ -0,0 +1,468 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+)
+
+// header is the first line of the generated files.
+const header = "// Code generated by apigen; DO NOT EDIT.\n"
+
+// field is a field of a type or a parameter of a method.
+type field struct {
+	// name is the name in the Bot API, eg: "chat_id".
+	name string
+	// goName is the name of the struct field, eg: "ChatID".
+	goName string
+	// param is the name of the method parameter, eg: "chatID".
+	param    string
+	typ      string
+	required bool
+}
+
+type method struct {
+	name     string
+	goName   string
+	doc      string
+	response string
+	params   []field
+	options  []field
+}
+
+// fields returns the parameters and the options of the method.
+func (m *method) fields() []field {
+	return append(append([]field{}, m.params...), m.options...)
+}
+
+// optionsType returns the name of the struct containing the optional parameters of the method.
+func (m *method) optionsType() string {
+	return m.goName + "Options"
+}
+
+type typ struct {
+	name   string
+	goName string
+	doc    string
+	fields []field
+}
+
+type response struct {
+	name   string
+	result string
+	doc    string
+}
+
+type generator struct {
+	spec      *Spec
+	pkg       *pkg
+	methods   []*method
+	types     []*typ
+	responses []*response
+	warnings  []string
+	// missing contains the fields of the hand-written types missing from the specification,
+	// which make apigen fail since they can't be generated.
+	missing []string
+	// parents maps the subtypes to the type they're merged in.
+	parents map[string]string
+}
+
+func newGenerator(spec *Spec, p *pkg) *generator {
+	g := &generator{
+		spec:    spec,
+		pkg:     p,
+		parents: make(map[string]string),
+	}
+
+	for name, t := range spec.Types {
+		if len(t.SubtypeOf) > 0 {
+			g.parents[name] = t.SubtypeOf[0]
+		}
+	}
+	return g
+}
+
+func (g *generator) warn(format string, a ...any) {
+	g.warnings = append(g.warnings, fmt.Sprintf(format, a...))
+}
+
+// typeName returns the Go name of a Bot API type, the subtypes are represented by the type they're part of.
+func (g *generator) typeName(t string) string {
+	for g.parents[t] != "" {
+		t = g.parents[t]
+	}
+	return exported(t)
+}
+
+func (g *generator) newField(f SpecField, ctx context) field {
+	return field{
+		name:     f.Name,
+		goName:   exported(f.Name),
+		param:    unexported(f.Name),
+		typ:      g.goType(f.Name, f.Types, f.Required, ctx),
+		required: f.Required,
+	}
+}
+
+// sortFields orders the fields roughly by size, like in the rest of the package.
+func sortFields(fields []field) {
+	sort.SliceStable(fields, func(i, j int) bool {
+		return rank(fields[i].typ) < rank(fields[j].typ)
+	})
+}
+
+// build computes the declarations to generate, leaving out the ones already declared by hand.
+func (g *generator) build() {
+	for _, name := range g.spec.typeNames() {
+		g.buildType(name, g.spec.Types[name])
+	}
+
+	for _, name := range g.spec.methodNames() {
+		g.buildMethod(name, g.spec.Methods[name])
+	}
+}
+
+func (g *generator) buildType(name string, st SpecType) {
+	if len(st.SubtypeOf) > 0 {
+		return
+	}
+
+	var (
+		goName = g.typeName(name)
+		fields = g.spec.fields(st)
+	)
+
+	if g.pkg.hasType(goName) {
+		// The fields can't be added to a hand-written type, so the missing ones are reported as errors.
+		if known := g.pkg.types[goName]; known != nil {
+			for _, f := range fields {
+				if !known[f.Name] {
+					g.missing = append(g.missing, fmt.Sprintf("%s: %s is missing the field %s", g.pkg.files[goName], goName, f.Name))
+				}
+			}
+		}
+		return
+	}
+
+	t := &typ{name: name, goName: goName, doc: typeDoc(goName, st.Description)}
+	if len(st.Subtypes) > 0 {
+		t.doc = unionDoc(goName, st)
+	}
+	for _, f := range fields {
+		t.fields = append(t.fields, g.newField(f, inType))
+	}
+	sortFields(t.fields)
+	g.types = append(g.types, t)
+}
+
+func (g *generator) buildMethod(name string, sm SpecMethod) {
+	m := &method{
+		name:   name,
+		goName: exported(name),
+		doc:    methodDoc(exported(name), sm.Description),
+	}
+
+	if g.pkg.methods[m.goName] {
+		return
+	}
+	if len(sm.Fields) > 0 && g.pkg.hasType(m.optionsType()) {
+		g.warn("%s: %s is declared by hand, the method is left to a hand-written implementation", name, m.optionsType())
+		return
+	}
+
+	for _, f := range sm.Fields {
+		if f.Required {
+			m.params = append(m.params, g.newField(f, inParams))
+		} else {
+			m.options = append(m.options, g.newField(f, inParams))
+		}
+	}
+	sortFields(m.options)
+
+	m.response = g.response(sm.Returns)
+	g.methods = append(g.methods, m)
+}
+
+// response returns the name of the APIResponse type wrapping the given result,
+// which is generated if it doesn't exist.
+func (g *generator) response(returns []string) string {
+	if len(returns) == 0 {
+		return "APIResponseBase"
+	}
+
+	result := g.goType("result", returns[:1], false, inType)
+	if name, ok := g.pkg.responses[result]; ok {
+		return name
+	}
+	for _, r := range g.responses {
+		if r.result == result {
+			return r.name
+		}
+	}
+
+	var (
+		elem = strings.TrimLeft(result, "[]*")
+		name = "APIResponse" + exported(elem)
+		doc  = fmt.Sprintf("Used by all methods that return a %s object on success.", elem)
+	)
+
+	if strings.HasPrefix(result, "[]") {
+		name += "Array"
+		doc = fmt.Sprintf("Used by all methods that return an array of %s objects on success.", elem)
+	}
+
+	g.responses = append(g.responses, &response{name: name, result: result, doc: doc})
+	return name
+}
+
+// wrap formats the text as a comment, wrapping it at about 110 columns.
+func wrap(text string) string {
+	var (
+		b    strings.Builder
+		line string
+	)
+
+	for _, w := range strings.Fields(text) {
+		if line != "" && len(line)+len(w) > 110 {
+			b.WriteString("// " + line + "\n")
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += w
+	}
+	if line != "" {
+		b.WriteString("// " + line + "\n")
+	}
+	return b.String()
+}
+
+// methodDoc returns the doc comment of a method, in the style of the hand-written ones,
+// eg: "SetMyName is used to change the bot's name.".
+func methodDoc(goName string, desc []string) string {
+	var ss []string
+
+	for _, s := range sentences(desc) {
+		if strings.HasPrefix(s, "Returns ") || strings.HasPrefix(s, "On success") {
+			continue
+		}
+		ss = append(ss, s)
+	}
+
+	if len(ss) == 0 {
+		return wrap(fmt.Sprintf("%s calls the %s method of the Bot API.", goName, goName))
+	}
+	if rest := strings.TrimPrefix(ss[0], "Use this method to "); rest != ss[0] {
+		ss[0] = goName + " is used to " + rest
+	} else {
+		ss[0] = goName + ": " + ss[0]
+	}
+	return wrap(strings.Join(ss, " "))
+}
+
+// typeDoc returns the doc comment of a type, in the style of the hand-written ones,
+// eg: "User represents a Telegram user or bot.".
+func typeDoc(goName string, desc []string) string {
+	ss := sentences(desc)
+	if len(ss) == 0 {
+		return wrap(goName + " is a type of the Bot API.")
+	}
+
+	s := ss[0]
+	for _, p := range [][2]string{
+		{"This object represents ", "represents "},
+		{"This object describes ", "describes "},
+		{"This object contains ", "contains "},
+		{"Represents ", "represents "},
+		{"Describes ", "describes "},
+		{"Contains ", "contains "},
+	} {
+		if rest := strings.TrimPrefix(s, p[0]); rest != s {
+			ss[0] = goName + " " + p[1] + rest
+			return wrap(strings.Join(ss, " "))
+		}
+	}
+
+	ss[0] = goName + ": " + s
+	return wrap(strings.Join(ss, " "))
+}
+
+// unionDoc returns the doc comment of a type with subtypes, whose fields are merged in a single struct.
+func unionDoc(goName string, st SpecType) string {
+	var desc string
+
+	if len(st.Description) > 0 {
+		for _, s := range sentences(st.Description[:1]) {
+			if !strings.Contains(s, "can be one of") {
+				desc += s + " "
+			}
+		}
+	}
+
+	subtypes := strings.Join(st.Subtypes[:len(st.Subtypes)-1], ", ") + " and " + st.Subtypes[len(st.Subtypes)-1]
+	if len(st.Subtypes) == 1 {
+		subtypes = st.Subtypes[0]
+	}
+	return typeDoc(goName, []string{desc + "It's a unique type for " + subtypes + "."})
+}
+
+// signature returns the parameters and the results of the Go method.
+func (m *method) signature(named bool) string {
+	var params []string
+
+	// The consecutive parameters of the same type share it, eg: "chatID, fromChatID int64".
+	for i, p := range m.params {
+		if i+1 < len(m.params) && m.params[i+1].typ == p.typ {
+			params = append(params, p.param)
+		} else {
+			params = append(params, p.param+" "+p.typ)
+		}
+	}
+	if len(m.options) > 0 {
+		params = append(params, "opts *"+m.optionsType())
+	}
+
+	if named {
+		return fmt.Sprintf("(%s) (res %s, err error)", strings.Join(params, ", "), m.response)
+	}
+	return fmt.Sprintf("(%s) (%s, error)", strings.Join(params, ", "), m.response)
+}
+
+// file is a generated file.
+type file struct {
+	name string
+	code []byte
+}
+
+func source(imports []string, body string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteString(header + "\npackage echotron\n\n")
+	if len(imports) > 0 {
+		buf.WriteString("import (\n")
+		for _, i := range imports {
+			fmt.Fprintf(&buf, "\t%q\n", i)
+		}
+		buf.WriteString(")\n\n")
+	}
+	buf.WriteString(body)
+
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("%w\n%s", err, buf.Bytes())
+	}
+	return code, nil
+}
+
+// render returns the generated files.
+func (g *generator) render() ([]file, error) {
+	var (
+		api, types, options, ifaces strings.Builder
+		generated                   []*method
+	)
+
+	for _, m := range g.methods {
+		var body strings.Builder
+
+		if !hookFor(m)(&body, m) {
+			g.warn("%s: the method must be implemented by hand", m.name)
+			continue
+		}
+		generated = append(generated, m)
+
+		fmt.Fprintf(&api, "\n%sfunc (a API) %s%s {\n%s}\n", m.doc, m.goName, m.signature(true), body.String())
+		fmt.Fprintf(&ifaces, "\t%s%s\n", m.goName, m.signature(false))
+
+		if len(m.options) > 0 {
+			fmt.Fprintf(&options, "\n// %s contains the optional parameters used by the %s method.\n", m.optionsType(), m.goName)
+			fmt.Fprintf(&options, "type %s struct {\n", m.optionsType())
+			for _, f := range m.options {
+				if f.typ == "InputFile" {
+					fmt.Fprintf(&options, "\t%s %s\n", f.goName, f.typ)
+				} else {
+					fmt.Fprintf(&options, "\t%s %s `query:%q`\n", f.goName, f.typ, f.name)
+				}
+			}
+			options.WriteString("}\n")
+		}
+	}
+
+	for _, t := range g.types {
+		fmt.Fprintf(&types, "\n%stype %s struct {\n", t.doc, t.goName)
+		for _, f := range t.fields {
+			tag := f.name
+			if !f.required {
+				tag += ",omitempty"
+			}
+			fmt.Fprintf(&types, "\t%s %s `json:%q`\n", f.goName, f.typ, tag)
+		}
+		types.WriteString("}\n")
+	}
+
+	for _, r := range g.responses {
+		if !g.used(r, generated) {
+			continue
+		}
+		fmt.Fprintf(&types, "\n// %s represents the incoming response from Telegram servers.\n// %s\n", r.name, r.doc)
+		fmt.Fprintf(&types, "type %s struct {\n\tResult %s `json:\"result,omitempty\"`\n\tAPIResponseBase\n}\n", r.name, r.result)
+		fmt.Fprintf(&types, "\n// Base returns the contained object of type APIResponseBase.\nfunc (a %s) Base() APIResponseBase {\n\treturn a.APIResponseBase\n}\n", r.name)
+	}
+
+	var apiImports []string
+	if strings.Contains(api.String(), "url.Values") {
+		apiImports = append(apiImports, "net/url")
+	}
+
+	ifaceDoc := wrap(fmt.Sprintf("SpecAPI contains the methods of the API object generated from the %s specification "+
+		"in spec/botapi.json which have no hand-written counterpart yet.", g.spec.Version))
+
+	var files []file
+	for _, f := range []struct {
+		name    string
+		imports []string
+		body    string
+	}{
+		{"api_gen.go", apiImports, api.String()},
+		{"types_gen.go", nil, types.String()},
+		{"options_gen.go", nil, options.String()},
+		{"interfaces_gen.go", nil, fmt.Sprintf("%stype SpecAPI interface {\n%s}\n", ifaceDoc, ifaces.String())},
+	} {
+		code, err := source(f.imports, f.body)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.name, err)
+		}
+		files = append(files, file{name: f.name, code: code})
+	}
+	return files, nil
+}
+
+// used reports whether the response is returned by one of the generated methods.
+func (g *generator) used(r *response, methods []*method) bool {
+	for _, m := range methods {
+		if m.response == r.name {
+			return true
+		}
+	}
+	return false
+}
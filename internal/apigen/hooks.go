@@ -0,0 +1,176 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A hook writes the body of the Go method calling a Bot API method, it returns false if
+// the method can't be generated and must be implemented by hand.
+type hook func(w *strings.Builder, m *method) bool
+
+// hooks contains the hooks of the methods that need special handling, by Bot API name.
+// The methods not listed here use plainHook, or uploadHook if they have a parameter
+// of type InputFile.
+var hooks = map[string]hook{
+	// The media are uploaded along with their thumbnails by postMedia.
+	"sendMediaGroup":   manualHook,
+	"editMessageMedia": manualHook,
+	// The stickers are uploaded by postStickers.
+	"createNewStickerSet": manualHook,
+	"addStickerToSet":     manualHook,
+	"replaceStickerInSet": manualHook,
+	"sendPaidMedia":       manualHook,
+}
+
+// hookFor returns the hook of the method.
+func hookFor(m *method) hook {
+	if h, ok := hooks[m.name]; ok {
+		return h
+	}
+
+	for _, f := range m.fields() {
+		if strings.HasPrefix(f.typ, "[]Input") || strings.HasPrefix(f.typ, "Input") && f.typ != "InputFile" {
+			return manualHook
+		}
+		if f.typ == "InputFile" {
+			return uploadHook
+		}
+	}
+	return plainHook
+}
+
+// manualHook leaves the method to a hand-written implementation.
+func manualHook(_ *strings.Builder, _ *method) bool {
+	return false
+}
+
+// encode returns the expression encoding the parameter as a string.
+func encode(f field) string {
+	switch f.typ {
+	case "string":
+		return f.param
+	case "int":
+		return fmt.Sprintf("itoa(int64(%s))", f.param)
+	case "int64":
+		return fmt.Sprintf("itoa(%s)", f.param)
+	case "float64":
+		return fmt.Sprintf("ftoa(%s)", f.param)
+	case "bool":
+		return fmt.Sprintf("btoa(%s)", f.param)
+	default:
+		return fmt.Sprintf("jsonString(%s)", f.param)
+	}
+}
+
+// values returns the expression of the url.Values passed to the request, given the name
+// of the variable containing the required parameters, if any.
+func values(m *method, vals string) string {
+	switch {
+	case vals != "" && len(m.options) > 0:
+		return fmt.Sprintf("addValues(%s, opts)", vals)
+	case vals != "":
+		return vals
+	case len(m.options) > 0:
+		return "urlValues(opts)"
+	default:
+		return "nil"
+	}
+}
+
+// plainHook sends the parameters in the query string of the request.
+func plainHook(w *strings.Builder, m *method) bool {
+	if len(m.params) == 0 {
+		fmt.Fprintf(w, "\treturn get[%s](a, %q, %s)\n", m.response, m.name, values(m, ""))
+		return true
+	}
+
+	w.WriteString("\tvar vals = make(url.Values)\n\n")
+	for _, p := range m.params {
+		fmt.Fprintf(w, "\tvals.Set(%q, %s)\n", p.name, encode(p))
+	}
+	fmt.Fprintf(w, "\treturn get[%s](a, %q, %s)\n", m.response, m.name, values(m, "vals"))
+	return true
+}
+
+// uploadHook uploads the parameter of type InputFile, along with its thumbnail if any,
+// and sends the other parameters in the query string of the request.
+func uploadHook(w *strings.Builder, m *method) bool {
+	var file, thumbnail *field
+
+	for _, f := range m.fields() {
+		if f.typ != "InputFile" {
+			continue
+		}
+		f := f
+		switch {
+		case f.name == "thumbnail" && !f.required:
+			thumbnail = &f
+		case file == nil:
+			file = &f
+		default:
+			// Only one file can be uploaded by postFile.
+			return false
+		}
+	}
+	if file == nil {
+		file, thumbnail = thumbnail, nil
+	}
+
+	var vars []string
+	if !file.required {
+		vars = append(vars, file.param+" InputFile")
+	}
+	if thumbnail != nil {
+		vars = append(vars, "thumbnail InputFile")
+	}
+	vars = append(vars, "vals = make(url.Values)")
+
+	if len(vars) == 1 {
+		fmt.Fprintf(w, "\tvar %s\n\n", vars[0])
+	} else {
+		fmt.Fprintf(w, "\tvar (\n\t\t%s\n\t)\n\n", strings.Join(vars, "\n\t\t"))
+	}
+
+	if !file.required || thumbnail != nil {
+		w.WriteString("\tif opts != nil {\n")
+		if !file.required {
+			fmt.Fprintf(w, "\t\t%s = opts.%s\n", file.param, file.goName)
+		}
+		if thumbnail != nil {
+			fmt.Fprintf(w, "\t\tthumbnail = opts.%s\n", thumbnail.goName)
+		}
+		w.WriteString("\t}\n\n")
+	}
+
+	for _, p := range m.params {
+		if p.typ != "InputFile" {
+			fmt.Fprintf(w, "\tvals.Set(%q, %s)\n", p.name, encode(p))
+		}
+	}
+
+	thumb := "InputFile{}"
+	if thumbnail != nil {
+		thumb = "thumbnail"
+	}
+	fmt.Fprintf(w, "\treturn postFile[%s](a, %q, %q, %s, %s, %s)\n", m.response, m.name, file.name, file.param, thumb, values(m, "vals"))
+	return true
+}
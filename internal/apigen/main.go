@@ -0,0 +1,89 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Apigen generates the methods of the API object, the types, the option structs and the
+// APIResponse types added by a Bot API release from a machine-readable description of its
+// changelog, for everything that isn't already declared in the hand-written files.
+// The hand-written declarations always take precedence and are only checked against the
+// entries of the changelog: the fields missing from them can't be generated, so they're
+// reported and make apigen exit with an error, as well as the TestUpToDate test, until
+// they're added by hand.
+//
+// The methods are generated by hooks, see hooks.go, the ones that need special handling,
+// such as uploading media, are left to a hand-written implementation and reported.
+//
+// Usage:
+//
+//	go run ./internal/apigen -spec spec/botapi.json -dir .
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	var (
+		specPath = flag.String("spec", "spec/botapi.json", "the Bot API specification")
+		dir      = flag.String("dir", ".", "the directory of the echotron package")
+		quiet    = flag.Bool("q", false, "don't report the declarations that must be written by hand")
+	)
+	flag.Parse()
+	log.SetFlags(0)
+	log.SetPrefix("apigen: ")
+
+	spec, err := loadSpec(*specPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	p, err := parsePackage(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	g := newGenerator(spec, p)
+	g.build()
+
+	files, err := g.render()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(*dir, f.name), f.code, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if !*quiet {
+		for _, w := range g.warnings {
+			fmt.Fprintln(os.Stderr, "apigen:", w)
+		}
+	}
+
+	for _, m := range g.missing {
+		fmt.Fprintln(os.Stderr, "apigen:", m)
+	}
+	if len(g.missing) > 0 {
+		log.Fatalf("%d fields of the specification are missing from the hand-written types", len(g.missing))
+	}
+}
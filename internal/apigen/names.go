@@ -0,0 +1,229 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"go/token"
+	"strings"
+	"unicode"
+)
+
+// initialisms contains the words written in upper case in Go identifiers.
+var initialisms = map[string]string{
+	"Id":   "ID",
+	"Ids":  "IDs",
+	"Url":  "URL",
+	"Ip":   "IP",
+	"Http": "HTTP",
+	"Json": "JSON",
+	"Html": "HTML",
+	"Api":  "API",
+	"Uri":  "URI",
+}
+
+// words splits a snake_case or CamelCase name in words.
+func words(name string) []string {
+	var (
+		res []string
+		cur []rune
+	)
+
+	for _, r := range name {
+		switch {
+		case r == '_':
+			if len(cur) > 0 {
+				res = append(res, string(cur))
+			}
+			cur = nil
+		case unicode.IsUpper(r) && len(cur) > 0 && !unicode.IsUpper(cur[len(cur)-1]):
+			res = append(res, string(cur))
+			cur = []rune{r}
+		default:
+			cur = append(cur, r)
+		}
+	}
+	if len(cur) > 0 {
+		res = append(res, string(cur))
+	}
+	return res
+}
+
+func title(w string) string {
+	if w == "" {
+		return w
+	}
+	w = strings.ToUpper(w[:1]) + w[1:]
+	if i, ok := initialisms[w]; ok {
+		return i
+	}
+	return w
+}
+
+// exported returns the exported Go name of a Bot API name, eg: "chat_id" becomes "ChatID".
+func exported(name string) string {
+	var b strings.Builder
+
+	for _, w := range words(name) {
+		b.WriteString(title(w))
+	}
+	return b.String()
+}
+
+// unexported returns the unexported Go name of a Bot API name, eg: "chat_id" becomes "chatID".
+func unexported(name string) string {
+	var (
+		b  strings.Builder
+		ws = words(name)
+	)
+
+	for i, w := range ws {
+		if i == 0 {
+			if t := title(w); t == strings.ToUpper(t) {
+				w = strings.ToLower(t)
+			} else {
+				w = strings.ToLower(w[:1]) + w[1:]
+			}
+			b.WriteString(w)
+			continue
+		}
+		b.WriteString(title(w))
+	}
+
+	res := b.String()
+	if token.IsKeyword(res) {
+		res += "Value"
+	}
+	return res
+}
+
+// isInt64 reports whether the Integer field with the given name may not fit in 32 bits,
+// eg: the IDs of the chats and the users and the dates.
+func isInt64(name string) bool {
+	switch {
+	case name == "chat_id", name == "user_id", name == "date", name == "file_size":
+		return true
+	case strings.HasSuffix(name, "_chat_id"), strings.HasSuffix(name, "_user_id"), strings.HasSuffix(name, "_date"):
+		return true
+	default:
+		return false
+	}
+}
+
+// replyMarkups are the types of the reply_markup parameters, which are grouped
+// by the ReplyMarkup interface.
+var replyMarkups = map[string]bool{
+	"InlineKeyboardMarkup": true,
+	"ReplyKeyboardMarkup":  true,
+	"ReplyKeyboardRemove":  true,
+	"ForceReply":           true,
+}
+
+// context is where a Go type is used, which affects how objects are referenced.
+type context int
+
+const (
+	// inType is a field of a type, where the optional objects and the elements of the arrays are pointers.
+	inType context = iota
+	// inParams is a parameter of a method or a field of an option struct, where the objects are values.
+	inParams
+)
+
+// goType returns the Go type of a field with the given Bot API types.
+func (g *generator) goType(name string, types []string, required bool, ctx context) string {
+	if len(types) == 0 {
+		return "any"
+	}
+
+	if len(types) > 1 {
+		all := true
+		for _, t := range types {
+			switch {
+			case t == "InputFile":
+				return "InputFile"
+			case t == "Integer":
+				// Integer or String, used for the IDs of the chats.
+				return "int64"
+			case !replyMarkups[t]:
+				all = false
+			}
+		}
+		if all {
+			return "ReplyMarkup"
+		}
+		return g.goType(name, types[:1], required, ctx)
+	}
+
+	t := types[0]
+	if elem := strings.TrimPrefix(t, "Array of "); elem != t {
+		et := g.goType(name, []string{elem}, true, ctx)
+		if ctx == inType && isObject(elem) {
+			et = "*" + et
+		}
+		return "[]" + et
+	}
+
+	switch t {
+	case "Integer", "Int":
+		if isInt64(name) {
+			return "int64"
+		}
+		return "int"
+	case "Float", "Float number":
+		return "float64"
+	case "Boolean", "True":
+		return "bool"
+	case "String":
+		return "string"
+	case "InputFile":
+		return "InputFile"
+	}
+
+	if ctx == inType && !required {
+		return "*" + g.typeName(t)
+	}
+	return g.typeName(t)
+}
+
+// isObject reports whether the Bot API type is an object, rather than a scalar or an array.
+func isObject(t string) bool {
+	switch t {
+	case "Integer", "Int", "Float", "Float number", "Boolean", "True", "String", "InputFile":
+		return false
+	}
+	return !strings.HasPrefix(t, "Array of ")
+}
+
+// rank is the position of a field of the given Go type in a struct, so that
+// the fields are roughly ordered by size like in the rest of the package.
+func rank(typ string) int {
+	switch {
+	case strings.HasPrefix(typ, "*"), typ == "ReplyMarkup", typ == "any":
+		return 0
+	case typ == "string", typ == "InputFile":
+		return 1
+	case strings.HasPrefix(typ, "[]"):
+		return 2
+	case typ == "int", typ == "int64", typ == "float64":
+		return 4
+	case typ == "bool":
+		return 5
+	default:
+		return 3
+	}
+}
@@ -0,0 +1,134 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// pkg contains the declarations of the hand-written files of the echotron package,
+// which take precedence over the generated ones.
+type pkg struct {
+	// types maps the names of the types to the JSON names of their fields, if they're structs.
+	types map[string]map[string]bool
+	// methods contains the names of the methods of the API object.
+	methods map[string]bool
+	// responses maps the type of the Result field of the APIResponse* types to their name.
+	responses map[string]string
+	// files maps the names of the types to the file they're declared in.
+	files map[string]string
+}
+
+// isGenerated reports whether the file has been generated by apigen.
+func isGenerated(name string) bool {
+	return strings.HasSuffix(name, "_gen.go")
+}
+
+func parsePackage(dir string) (*pkg, error) {
+	var (
+		fset = token.NewFileSet()
+		p    = &pkg{
+			types:     make(map[string]map[string]bool),
+			methods:   make(map[string]bool),
+			responses: make(map[string]string),
+			files:     make(map[string]string),
+		}
+	)
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range paths {
+		name := filepath.Base(path)
+		if strings.HasSuffix(name, "_test.go") || isGenerated(name) {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if d.Recv != nil && len(d.Recv.List) == 1 && exprString(fset, d.Recv.List[0].Type) == "API" {
+					p.methods[d.Name.Name] = true
+				}
+
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					if ts, ok := spec.(*ast.TypeSpec); ok {
+						p.addType(fset, name, ts)
+					}
+				}
+			}
+		}
+	}
+	return p, nil
+}
+
+func (p *pkg) addType(fset *token.FileSet, file string, ts *ast.TypeSpec) {
+	name := ts.Name.Name
+	p.files[name] = file
+
+	st, ok := ts.Type.(*ast.StructType)
+	if !ok {
+		p.types[name] = nil
+		return
+	}
+
+	fields := make(map[string]bool)
+	for _, f := range st.Fields.List {
+		if f.Tag != nil {
+			tag, _ := strconv.Unquote(f.Tag.Value)
+			if jsn := strings.Split(reflect.StructTag(tag).Get("json"), ",")[0]; jsn != "" && jsn != "-" {
+				fields[jsn] = true
+			}
+		}
+		for _, n := range f.Names {
+			if n.Name == "Result" && strings.HasPrefix(name, "APIResponse") {
+				p.responses[exprString(fset, f.Type)] = name
+			}
+		}
+	}
+	p.types[name] = fields
+}
+
+func exprString(fset *token.FileSet, e ast.Expr) string {
+	var buf bytes.Buffer
+	format.Node(&buf, fset, e)
+	return buf.String()
+}
+
+// hasType reports whether the type is declared in the package.
+func (p *pkg) hasType(name string) bool {
+	_, ok := p.types[name]
+	return ok
+}
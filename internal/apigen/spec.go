@@ -0,0 +1,145 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Spec is the machine-readable description of the Telegram Bot API,
+// in the format of github.com/PaulSonOfLars/telegram-bot-api-spec.
+// It may describe only the methods and types added or changed by a release.
+type Spec struct {
+	Version     string                `json:"version"`
+	ReleaseDate string                `json:"release_date"`
+	Changelog   string                `json:"changelog"`
+	Methods     map[string]SpecMethod `json:"methods"`
+	Types       map[string]SpecType   `json:"types"`
+}
+
+// SpecMethod is a method of the Bot API.
+type SpecMethod struct {
+	Name        string      `json:"name"`
+	Href        string      `json:"href"`
+	Description []string    `json:"description"`
+	Returns     []string    `json:"returns"`
+	Fields      []SpecField `json:"fields"`
+}
+
+// SpecType is a type of the Bot API. A type with subtypes is one of them,
+// eg: ReactionType is either a ReactionTypeEmoji or a ReactionTypeCustomEmoji.
+type SpecType struct {
+	Name        string      `json:"name"`
+	Href        string      `json:"href"`
+	Description []string    `json:"description"`
+	Fields      []SpecField `json:"fields"`
+	Subtypes    []string    `json:"subtypes"`
+	SubtypeOf   []string    `json:"subtype_of"`
+}
+
+// SpecField is a field of a type or a parameter of a method.
+type SpecField struct {
+	Name        string   `json:"name"`
+	Types       []string `json:"types"`
+	Description string   `json:"description"`
+	Required    bool     `json:"required"`
+}
+
+func loadSpec(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var spec Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// methodNames returns the names of the methods of the spec in alphabetical order.
+func (s *Spec) methodNames() []string {
+	names := make([]string, 0, len(s.Methods))
+	for n := range s.Methods {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// typeNames returns the names of the types of the spec in alphabetical order.
+func (s *Spec) typeNames() []string {
+	names := make([]string, 0, len(s.Types))
+	for n := range s.Types {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fields returns the fields of the type, which for a type with subtypes are the ones
+// of all of them, required only if required in each of them.
+func (s *Spec) fields(t SpecType) []SpecField {
+	if len(t.Subtypes) == 0 {
+		return t.Fields
+	}
+
+	var (
+		fields []SpecField
+		index  = make(map[string]int)
+		count  = make(map[string]int)
+	)
+
+	for _, name := range t.Subtypes {
+		for _, f := range s.fields(s.Types[name]) {
+			count[f.Name]++
+			if i, ok := index[f.Name]; ok {
+				fields[i].Required = fields[i].Required && f.Required
+				continue
+			}
+			index[f.Name] = len(fields)
+			fields = append(fields, f)
+		}
+	}
+
+	for i, f := range fields {
+		if count[f.Name] < len(t.Subtypes) {
+			fields[i].Required = false
+		}
+	}
+	return fields
+}
+
+// sentences splits the description in sentences.
+func sentences(desc []string) []string {
+	var res []string
+
+	for _, d := range desc {
+		for _, s := range strings.SplitAfter(d, ". ") {
+			if s = strings.TrimSpace(s); s != "" {
+				res = append(res, s)
+			}
+		}
+	}
+	return res
+}
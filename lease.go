@@ -0,0 +1,245 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package echotron
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// Lease is the interface implemented by the leader election mechanisms used by PollingSource
+// to make sure that only one replica of the bot polls Telegram at a time.
+type Lease interface {
+	// TryAcquire acquires the lease, or renews it if already held, and reports whether
+	// the caller holds it. A lease that isn't renewed in time expires.
+	TryAcquire(ctx context.Context) (bool, error)
+	// Release releases the lease if held, so that another replica can take over without waiting for it to expire.
+	Release(ctx context.Context) error
+}
+
+var leaseHolders atomic.Int64
+
+// FileLease is a Lease stored in a file, which must be on a filesystem shared by the replicas.
+// The file contains the holder of the lease and when it expires, and is updated while holding
+// a lock file next to it, which is taken over if its holder doesn't remove it within the TTL.
+type FileLease struct {
+	path   string
+	holder string
+	ttl    time.Duration
+}
+
+type fileLeaseState struct {
+	Expires time.Time `json:"expires"`
+	Holder  string    `json:"holder"`
+}
+
+// NewFileLease returns a new FileLease stored in the file at the given path,
+// the lease expires if not renewed within ttl, 30s if 0.
+func NewFileLease(path string, ttl time.Duration) *FileLease {
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+
+	host, _ := os.Hostname()
+	return &FileLease{
+		path:   path,
+		holder: fmt.Sprintf("%s:%d:%d", host, os.Getpid(), leaseHolders.Add(1)),
+		ttl:    ttl,
+	}
+}
+
+// Holder returns the identifier of the FileLease, which is written in the file while it holds the lease.
+func (l *FileLease) Holder() string {
+	return l.holder
+}
+
+// TryAcquire implements the Lease interface.
+func (l *FileLease) TryAcquire(ctx context.Context) (bool, error) {
+	token, err := l.lock(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer l.removeLock(token)
+
+	state, err := l.read()
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	if state.Holder != l.holder && now.Before(state.Expires) {
+		return false, nil
+	}
+
+	// The lock might have been taken over by another replica if it took longer than the TTL to get here.
+	if !l.locked(token) {
+		return false, nil
+	}
+	if err := l.write(fileLeaseState{Holder: l.holder, Expires: now.Add(l.ttl)}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Release implements the Lease interface.
+func (l *FileLease) Release(ctx context.Context) error {
+	token, err := l.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer l.removeLock(token)
+
+	state, err := l.read()
+	if err != nil || state.Holder != l.holder || !l.locked(token) {
+		return err
+	}
+	return os.Remove(l.path)
+}
+
+func (l *FileLease) lockPath() string {
+	return l.path + ".lock"
+}
+
+// lock creates the lock file containing a new unique token, which is returned, waiting for the
+// other holders of the lock to remove it.
+// A lock file older than the TTL is considered stale and taken over.
+func (l *FileLease) lock(ctx context.Context) (token string, err error) {
+	token = fmt.Sprintf("%s:%d", l.holder, time.Now().UnixNano())
+
+	for {
+		file, err := os.OpenFile(l.lockPath(), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			_, err = file.WriteString(token)
+			if cerr := file.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				os.Remove(l.lockPath())
+				return "", err
+			}
+			return token, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return "", err
+		}
+
+		if stale, ok := l.staleLock(); ok {
+			l.removeLock(stale)
+			continue
+		}
+
+		if err := sleepContext(ctx, 10*time.Millisecond); err != nil {
+			return "", err
+		}
+	}
+}
+
+// locked reports whether the lock file contains the given token.
+func (l *FileLease) locked(token string) bool {
+	data, err := os.ReadFile(l.lockPath())
+	return err == nil && string(data) == token
+}
+
+// staleLock returns the token of the lock file if it's older than the TTL.
+// The age and the token are read from the same open file, so that they belong to the same lock
+// even if it's replaced in the meantime.
+func (l *FileLease) staleLock() (token string, ok bool) {
+	file, err := os.Open(l.lockPath())
+	if err != nil {
+		return "", false
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || time.Since(info.ModTime()) <= l.ttl {
+		return "", false
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// removeLock removes the lock file if it contains the given token.
+// Checking the token and then removing the file would let another replica create a new lock file in
+// between, which would be removed instead, so the lock file is first moved atomically to a unique path
+// and checked there: if it turns out to be another lock it's put back, unless a new one has been
+// created in the meantime, in which case its holder finds out with locked before changing the lease.
+func (l *FileLease) removeLock(token string) {
+	tmp, err := os.CreateTemp(filepath.Dir(l.path), filepath.Base(l.lockPath())+".*")
+	if err != nil {
+		return
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := os.Rename(l.lockPath(), tmp.Name()); err != nil {
+		return
+	}
+	if data, err := os.ReadFile(tmp.Name()); err == nil && string(data) != token {
+		// os.Link doesn't replace the lock file if another one has been created in the meantime.
+		os.Link(tmp.Name(), l.lockPath())
+	}
+}
+
+func (l *FileLease) read() (state fileLeaseState, err error) {
+	data, err := os.ReadFile(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+
+	if len(data) > 0 {
+		err = json.Unmarshal(data, &state)
+	}
+	return state, err
+}
+
+func (l *FileLease) write(state fileLeaseState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(l.path), filepath.Base(l.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), l.path)
+}
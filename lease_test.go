@@ -0,0 +1,146 @@
+package echotron
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFileLease(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		path = filepath.Join(t.TempDir(), "poller.lease")
+		a    = NewFileLease(path, 100*time.Millisecond)
+		b    = NewFileLease(path, 100*time.Millisecond)
+	)
+
+	if ok, err := a.TryAcquire(ctx); !ok || err != nil {
+		t.Fatalf("a didn't acquire the lease: %v", err)
+	}
+	if ok, _ := b.TryAcquire(ctx); ok {
+		t.Fatal("b acquired a lease held by a")
+	}
+	if ok, _ := a.TryAcquire(ctx); !ok {
+		t.Fatal("a couldn't renew the lease")
+	}
+
+	if err := a.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := b.TryAcquire(ctx); !ok {
+		t.Fatal("b didn't acquire the released lease")
+	}
+	if err := a.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := a.TryAcquire(ctx); ok {
+		t.Fatal("a released the lease held by b")
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if ok, _ := a.TryAcquire(ctx); !ok {
+		t.Fatal("a didn't take over the expired lease")
+	}
+}
+
+func TestFileLeaseStaleLock(t *testing.T) {
+	for round := 0; round < 20; round++ {
+		var (
+			ctx      = context.Background()
+			path     = filepath.Join(t.TempDir(), "poller.lease")
+			acquired atomic.Int32
+			wg       sync.WaitGroup
+		)
+
+		if err := os.WriteFile(path+".lock", []byte("crashed"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		old := time.Now().Add(-time.Minute)
+		if err := os.Chtimes(path+".lock", old, old); err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ok, err := NewFileLease(path, time.Second).TryAcquire(ctx)
+				if err != nil {
+					t.Error(err)
+				}
+				if ok {
+					acquired.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if n := acquired.Load(); n != 1 {
+			t.Fatalf("round %d: the lease has been acquired %d times", round, n)
+		}
+		if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+			t.Fatalf("round %d: the lock file has been left behind: %v", round, err)
+		}
+	}
+}
+
+func TestPollingSourceLease(t *testing.T) {
+	var (
+		path    = filepath.Join(t.TempDir(), "poller.lease")
+		polls   [2]atomic.Int32
+		cancels [2]context.CancelFunc
+		errs    = make(chan error, 2)
+	)
+
+	for i := range polls {
+		var (
+			n   = &polls[i]
+			api = newTestServerAPI(t, nil, func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasSuffix(r.URL.Path, "/getUpdates") {
+					n.Add(1)
+					time.Sleep(5 * time.Millisecond)
+				}
+				w.Write([]byte(`{"ok":true,"result":[]}`))
+			})
+			src = &PollingSource{
+				API:           api,
+				Lease:         NewFileLease(path, 200*time.Millisecond),
+				LeaseInterval: 20 * time.Millisecond,
+			}
+			ctx context.Context
+		)
+
+		ctx, cancels[i] = context.WithCancel(context.Background())
+		go func() { errs <- src.Run(ctx, make(chan *Update)) }()
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	leader := 0
+	if polls[1].Load() > 0 {
+		leader = 1
+	}
+	if standby := polls[1-leader].Load(); standby != 0 || polls[leader].Load() == 0 {
+		t.Fatalf("expected only one source polling, got %d and %d", polls[0].Load(), polls[1].Load())
+	}
+
+	cancels[leader]()
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if polls[1-leader].Load() == 0 {
+		t.Fatal("the standby source didn't take over")
+	}
+
+	cancels[1-leader]()
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,119 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package echotron
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+)
+
+// LogLevel is a custom type for the severity of the messages logged by echotron.
+type LogLevel int
+
+// These are all the possible log levels.
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogWarn
+	LogError
+)
+
+// String returns the name of the log level.
+func (l LogLevel) String() string {
+	switch l {
+	case LogDebug:
+		return "DEBUG"
+	case LogInfo:
+		return "INFO"
+	case LogWarn:
+		return "WARN"
+	case LogError:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("LEVEL(%d)", int(l))
+	}
+}
+
+// Logger is the interface used by echotron for all of its logging.
+// keyvals contains alternated keys and values adding context to the message,
+// eg: "method", "sendMessage", "error", err.
+type Logger interface {
+	Log(level LogLevel, msg string, keyvals ...any)
+}
+
+// LoggerFunc is an adapter to use ordinary functions as a Logger.
+type LoggerFunc func(level LogLevel, msg string, keyvals ...any)
+
+// Log calls f(level, msg, keyvals...).
+func (f LoggerFunc) Log(level LogLevel, msg string, keyvals ...any) {
+	f(level, msg, keyvals...)
+}
+
+// NopLogger is a Logger that discards all the messages.
+var NopLogger Logger = LoggerFunc(func(_ LogLevel, _ string, _ ...any) {})
+
+type stdLogger LogLevel
+
+// NewStdLogger returns a Logger that writes to the standard log package the messages
+// with a level equal or greater than min, in the form "echotron LEVEL msg key=value".
+func NewStdLogger(min LogLevel) Logger {
+	return stdLogger(min)
+}
+
+func (s stdLogger) Log(level LogLevel, msg string, keyvals ...any) {
+	if level < LogLevel(s) {
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "echotron %s %s", level, msg)
+	for i := 0; i < len(keyvals); i += 2 {
+		if i+1 < len(keyvals) {
+			fmt.Fprintf(&b, " %v=%v", keyvals[i], keyvals[i+1])
+		} else {
+			fmt.Fprintf(&b, " %v", keyvals[i])
+		}
+	}
+	log.Println(b.String())
+}
+
+var (
+	defaultLogger   = NewStdLogger(LogInfo)
+	defaultLoggerMu sync.RWMutex
+)
+
+// SetLogger sets the Logger used by PollingUpdates, WebhookUpdates and by the Dispatcher and API
+// objects without a Logger of their own. It defaults to NewStdLogger(LogInfo).
+func SetLogger(l Logger) {
+	if l == nil {
+		l = NopLogger
+	}
+
+	defaultLoggerMu.Lock()
+	defaultLogger = l
+	defaultLoggerMu.Unlock()
+}
+
+func getLogger() Logger {
+	defaultLoggerMu.RLock()
+	defer defaultLoggerMu.RUnlock()
+	return defaultLogger
+}
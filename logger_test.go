@@ -0,0 +1,81 @@
+package echotron
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestStdLogger(t *testing.T) {
+	var buf bytes.Buffer
+
+	log.SetOutput(&buf)
+	log.SetFlags(0)
+	defer log.SetOutput(os.Stderr)
+	defer log.SetFlags(log.LstdFlags)
+
+	l := NewStdLogger(LogInfo)
+	l.Log(LogDebug, "hidden")
+	l.Log(LogError, "failed", "method", "getMe", "error", "boom")
+
+	if expected := "echotron ERROR failed method=getMe error=boom\n"; buf.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestDispatcherLogger(t *testing.T) {
+	var (
+		levels []LogLevel
+		d      = NewDispatcher("token", func(_ int64) Bot { return test{} })
+	)
+
+	d.SetLogger(LoggerFunc(func(level LogLevel, msg string, _ ...any) {
+		if !strings.HasPrefix(msg, "echotron.Dispatcher HandleWebhook") {
+			t.Errorf("unexpected message %q", msg)
+		}
+		levels = append(levels, level)
+	}))
+
+	d.HandleWebhook(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader("{")))
+
+	if len(levels) != 1 || levels[0] != LogError {
+		t.Fatalf("unexpected log levels %v", levels)
+	}
+}
+
+func TestAPILogRedactsToken(t *testing.T) {
+	const token = "123456:secret-token"
+
+	var (
+		logged []string
+		srv    = httptest.NewServer(nil)
+	)
+	srv.Close()
+
+	api := NewAPIOptions(token, &APIOptions{
+		BaseURL: srv.URL,
+		Logger: LoggerFunc(func(_ LogLevel, msg string, keyvals ...any) {
+			logged = append(logged, fmt.Sprint(append([]any{msg}, keyvals...)...))
+		}),
+	})
+
+	_, err := api.GetMe()
+	if err == nil {
+		t.Fatal("expected a network error")
+	}
+	if strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("the token has not been redacted from the error: %v", err)
+	}
+	if len(logged) == 0 {
+		t.Fatal("the error has not been logged")
+	}
+	for _, l := range logged {
+		if strings.Contains(l, "secret-token") {
+			t.Errorf("the token has not been redacted from the log: %s", l)
+		}
+	}
+}
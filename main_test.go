@@ -0,0 +1,59 @@
+//go:build apitest
+
+package echotron
+
+import (
+	"log"
+	"os"
+	"testing"
+
+	"github.com/NicoNex/echotron/v3/echotrontest/cassette"
+)
+
+// apiCassette contains the exchanges with the Telegram Bot API made by the tests in api_test.go.
+const apiCassette = "testdata/api_cassette.json"
+
+// apiIgnoredParams contains the parameters the tests set from the current time,
+// which can't match the recorded ones.
+var apiIgnoredParams = []string{
+	"expire_date",
+	"setMyName.name",
+	"setChatDescription.description",
+	"editGeneralForumTopic.name",
+	"createNewStickerSet.name",
+	"addStickerToSet.name",
+	"getStickerSet.name",
+	"setStickerSetTitle.name",
+	"setStickerSetTitle.title",
+	"setStickerSetThumbnail.name",
+	"deleteStickerSet.name",
+}
+
+// replaying reports whether the tests are replaying apiCassette rather than calling the live API.
+var replaying bool
+
+// TestMain runs the tests built with the apitest tag against the exchanges recorded in apiCassette,
+// so that they don't need network access. If ECHOTRON_RECORD is set, the tests call the live API
+// instead and the cassette is rewritten if they all pass.
+func TestMain(m *testing.M) {
+	var mode = cassette.ModeReplay
+
+	if os.Getenv("ECHOTRON_RECORD") != "" {
+		mode = cassette.ModeRecord
+	}
+
+	rec, err := cassette.New(apiCassette, &cassette.Options{Mode: mode, IgnoreParams: apiIgnoredParams})
+	if err != nil {
+		log.Fatal(err)
+	}
+	replaying = !rec.Recording()
+	api = NewAPIOptions(api.token, &APIOptions{Client: rec.Client()})
+
+	code := m.Run()
+	if code == 0 && rec.Recording() {
+		if err := rec.Save(); err != nil {
+			log.Fatal(err)
+		}
+	}
+	os.Exit(code)
+}
@@ -0,0 +1,302 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package echotron
+
+import (
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the upper bounds in seconds of the buckets of the latency histograms.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics collects counters and latency histograms about the updates handled by the Dispatchers
+// and the calls made by the API objects it's attached to.
+// Updates are measured by the Middleware, API calls by the Interceptor and the sessions
+// and the updates being handled by the Dispatchers passed to Watch.
+// The metrics can be published with expvar or served in the Prometheus text format.
+type Metrics struct {
+	updates         map[UpdateType]int64
+	apiCalls        map[apiCallKey]int64
+	apiDuration     map[string]*histogram
+	handlerDuration *histogram
+	dispatchers     []*Dispatcher
+	buckets         []float64
+	rateLimited     int64
+	retryAfter      int64
+	mu              sync.Mutex
+}
+
+type apiCallKey struct {
+	method string
+	status string
+}
+
+type histogram struct {
+	counts []int64
+	sum    float64
+	count  int64
+}
+
+// NewMetrics returns a new Metrics object using the DefaultBuckets for its histograms.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		updates:         make(map[UpdateType]int64),
+		apiCalls:        make(map[apiCallKey]int64),
+		apiDuration:     make(map[string]*histogram),
+		handlerDuration: newHistogram(DefaultBuckets),
+		buckets:         DefaultBuckets,
+	}
+}
+
+func newHistogram(buckets []float64) *histogram {
+	return &histogram{counts: make([]int64, len(buckets))}
+}
+
+func (h *histogram) observe(buckets []float64, d time.Duration) {
+	s := d.Seconds()
+	for i, b := range buckets {
+		if s <= b {
+			h.counts[i]++
+		}
+	}
+	h.sum += s
+	h.count++
+}
+
+// Middleware returns a Middleware that counts the updates by type and measures how long it takes to handle them.
+func (m *Metrics) Middleware() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(sessionKey int64, update *Update) {
+			start := time.Now()
+			next(sessionKey, update)
+
+			m.mu.Lock()
+			defer m.mu.Unlock()
+
+			m.updates[update.Type()]++
+			m.handlerDuration.observe(m.buckets, time.Since(start))
+		}
+	}
+}
+
+// Interceptor returns an Interceptor that counts the API calls by method and status
+// and measures their duration, along with the rate limited calls and their retry_after.
+func (m *Metrics) Interceptor() Interceptor {
+	return Interceptor{
+		After: func(call *APICall) {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+
+			status := "ok"
+			switch {
+			case call.Response.ErrorCode != 0:
+				status = strconv.Itoa(call.Response.ErrorCode)
+			case call.Err != nil:
+				status = "error"
+			}
+			m.apiCalls[apiCallKey{call.Method, status}]++
+
+			h, ok := m.apiDuration[call.Method]
+			if !ok {
+				h = newHistogram(m.buckets)
+				m.apiDuration[call.Method] = h
+			}
+			h.observe(m.buckets, call.Duration)
+
+			if call.Response.ErrorCode == http.StatusTooManyRequests {
+				m.rateLimited++
+				if p := call.Response.Parameters; p != nil {
+					m.retryAfter += int64(p.RetryAfter)
+				}
+			}
+		},
+	}
+}
+
+// Watch adds the Dispatcher to the ones whose sessions and updates being handled are reported.
+func (m *Metrics) Watch(d *Dispatcher) {
+	m.mu.Lock()
+	m.dispatchers = append(m.dispatchers, d)
+	m.mu.Unlock()
+}
+
+func (m *Metrics) gauges() (sessions, inFlight int64) {
+	m.mu.Lock()
+	dsps := m.dispatchers
+	m.mu.Unlock()
+
+	for _, d := range dsps {
+		sessions += int64(d.sessionCount())
+		inFlight += d.inFlight.Load()
+	}
+	return
+}
+
+// Publish publishes the metrics with expvar under the given name, so that they're served
+// as JSON by the expvar handler at /debug/vars.
+func (m *Metrics) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return m.Snapshot()
+	}))
+}
+
+// Snapshot returns a copy of the current value of the metrics, suitable for encoding to JSON.
+func (m *Metrics) Snapshot() map[string]any {
+	sessions, inFlight := m.gauges()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	updates := make(map[string]int64, len(m.updates))
+	for t, n := range m.updates {
+		updates[string(t)] = n
+	}
+
+	calls := make(map[string]map[string]int64)
+	for k, n := range m.apiCalls {
+		if calls[k.method] == nil {
+			calls[k.method] = make(map[string]int64)
+		}
+		calls[k.method][k.status] = n
+	}
+
+	durations := make(map[string]map[string]any, len(m.apiDuration))
+	for method, h := range m.apiDuration {
+		durations[method] = h.snapshot(m.buckets)
+	}
+
+	return map[string]any{
+		"updates":               updates,
+		"handler_duration":      m.handlerDuration.snapshot(m.buckets),
+		"sessions":              sessions,
+		"updates_in_flight":     inFlight,
+		"api_calls":             calls,
+		"api_call_duration":     durations,
+		"api_rate_limited":      m.rateLimited,
+		"api_retry_after_total": m.retryAfter,
+	}
+}
+
+func (h *histogram) snapshot(buckets []float64) map[string]any {
+	b := make(map[string]int64, len(buckets))
+	for i, le := range buckets {
+		b[ftoa(le)] = h.counts[i]
+	}
+	return map[string]any{"buckets": b, "sum": h.sum, "count": h.count}
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format,
+// so that it can be mounted next to the webhook handler.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	m.WriteText(w)
+}
+
+// WriteText writes the metrics to w in the Prometheus text exposition format.
+func (m *Metrics) WriteText(w io.Writer) error {
+	sessions, inFlight := m.gauges()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var ew = &errWriter{w: w}
+
+	ew.printf("# TYPE echotron_updates_total counter\n")
+	types := make([]string, 0, len(m.updates))
+	for t := range m.updates {
+		types = append(types, string(t))
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		ew.printf("echotron_updates_total{type=%q} %d\n", t, m.updates[UpdateType(t)])
+	}
+
+	ew.printf("# TYPE echotron_handler_duration_seconds histogram\n")
+	m.handlerDuration.write(ew, "echotron_handler_duration_seconds", "", m.buckets)
+
+	ew.printf("# TYPE echotron_sessions gauge\nechotron_sessions %d\n", sessions)
+	ew.printf("# TYPE echotron_updates_in_flight gauge\nechotron_updates_in_flight %d\n", inFlight)
+
+	ew.printf("# TYPE echotron_api_calls_total counter\n")
+	keys := make([]apiCallKey, 0, len(m.apiCalls))
+	for k := range m.apiCalls {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].method != keys[j].method {
+			return keys[i].method < keys[j].method
+		}
+		return keys[i].status < keys[j].status
+	})
+	for _, k := range keys {
+		ew.printf("echotron_api_calls_total{method=%q,status=%q} %d\n", k.method, k.status, m.apiCalls[k])
+	}
+
+	ew.printf("# TYPE echotron_api_call_duration_seconds histogram\n")
+	methods := make([]string, 0, len(m.apiDuration))
+	for method := range m.apiDuration {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	for _, method := range methods {
+		labels := fmt.Sprintf("method=%q", method)
+		m.apiDuration[method].write(ew, "echotron_api_call_duration_seconds", labels, m.buckets)
+	}
+
+	ew.printf("# TYPE echotron_api_rate_limited_total counter\nechotron_api_rate_limited_total %d\n", m.rateLimited)
+	ew.printf("# TYPE echotron_api_retry_after_seconds_total counter\nechotron_api_retry_after_seconds_total %d\n", m.retryAfter)
+	return ew.err
+}
+
+func (h *histogram) write(ew *errWriter, name, labels string, buckets []float64) {
+	var sep string
+	if labels != "" {
+		sep = ","
+	}
+
+	for i, le := range buckets {
+		ew.printf("%s_bucket{%s%sle=%q} %d\n", name, labels, sep, ftoa(le), h.counts[i])
+	}
+	ew.printf("%s_bucket{%s%sle=\"+Inf\"} %d\n", name, labels, sep, h.count)
+
+	if labels != "" {
+		labels = "{" + labels + "}"
+	}
+	ew.printf("%s_sum%s %s\n", name, labels, ftoa(h.sum))
+	ew.printf("%s_count%s %d\n", name, labels, h.count)
+}
+
+// errWriter is an io.Writer wrapper that keeps the first error encountered.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (e *errWriter) printf(format string, a ...any) {
+	if e.err == nil {
+		_, e.err = fmt.Fprintf(e.w, format, a...)
+	}
+}
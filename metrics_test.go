@@ -0,0 +1,59 @@
+package echotron
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	var (
+		buf   bytes.Buffer
+		m     = NewMetrics()
+		d, ch = newRecorderDispatcher()
+		after = m.Interceptor().After
+	)
+
+	m.Watch(d)
+	d.Use(m.Middleware())
+	d.handle(&Update{Message: &Message{Chat: Chat{ID: 1}}})
+	d.handle(&Update{CallbackQuery: &CallbackQuery{Message: &Message{Chat: Chat{ID: 2}}}})
+	<-ch
+	<-ch
+
+	after(&APICall{Method: "sendMessage", Duration: 20 * time.Millisecond, Response: APIResponseBase{Ok: true}})
+	after(&APICall{
+		Method: "sendMessage",
+		Response: APIResponseBase{
+			ErrorCode:  429,
+			Parameters: &ResponseParameters{RetryAfter: 30},
+		},
+	})
+
+	if err := m.WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{
+		`echotron_updates_total{type="callback_query"} 1`,
+		`echotron_updates_total{type="message"} 1`,
+		`echotron_handler_duration_seconds_count 2`,
+		`echotron_sessions 2`,
+		`echotron_api_calls_total{method="sendMessage",status="ok"} 1`,
+		`echotron_api_calls_total{method="sendMessage",status="429"} 1`,
+		`echotron_api_call_duration_seconds_bucket{method="sendMessage",le="0.025"} 2`,
+		`echotron_api_call_duration_seconds_bucket{method="sendMessage",le="0.01"} 1`,
+		`echotron_api_rate_limited_total 1`,
+		`echotron_api_retry_after_seconds_total 30`,
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Fatalf("missing line %q in:\n%s", line, buf.String())
+		}
+	}
+
+	if _, err := json.Marshal(m.Snapshot()); err != nil {
+		t.Fatal(err)
+	}
+}
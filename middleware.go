@@ -0,0 +1,103 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package echotron
+
+import (
+	"sync"
+	"time"
+)
+
+// HandlerFunc is the function called by the Dispatcher for each incoming update,
+// sessionKey is the key of the session the update belongs to.
+type HandlerFunc func(sessionKey int64, update *Update)
+
+// Middleware wraps a HandlerFunc with another one, in order to run code before and after the update is handled.
+// A Middleware can short-circuit an update by not calling next, in that case the update never reaches the Bot.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// chain wraps h with the given middlewares so that the first one is the outermost.
+func chain(h HandlerFunc, mw ...Middleware) HandlerFunc {
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+	return h
+}
+
+// AllowSessions returns a Middleware that drops all the updates that don't belong to one of the given sessions.
+func AllowSessions(sessionKeys ...int64) Middleware {
+	var allowed = make(map[int64]bool, len(sessionKeys))
+
+	for _, k := range sessionKeys {
+		allowed[k] = true
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(sessionKey int64, update *Update) {
+			if allowed[sessionKey] {
+				next(sessionKey, update)
+			}
+		}
+	}
+}
+
+// RateLimit returns a Middleware that lets through at most n updates per session in the given interval,
+// the updates exceeding the limit are dropped.
+func RateLimit(n int, interval time.Duration) Middleware {
+	type window struct {
+		start time.Time
+		count int
+	}
+
+	var (
+		mu        sync.Mutex
+		windows   = make(map[int64]*window)
+		lastPrune = time.Now()
+	)
+
+	allow := func(sessionKey int64) bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		now := time.Now()
+		if now.Sub(lastPrune) >= interval {
+			for k, w := range windows {
+				if now.Sub(w.start) >= interval {
+					delete(windows, k)
+				}
+			}
+			lastPrune = now
+		}
+
+		w, ok := windows[sessionKey]
+		if !ok || now.Sub(w.start) >= interval {
+			w = &window{start: now}
+			windows[sessionKey] = w
+		}
+		w.count++
+		return w.count <= n
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(sessionKey int64, update *Update) {
+			if allow(sessionKey) {
+				next(sessionKey, update)
+			}
+		}
+	}
+}
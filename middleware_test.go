@@ -0,0 +1,80 @@
+package echotron
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type recorderBot struct {
+	updates chan *Update
+}
+
+func (r recorderBot) Update(u *Update) {
+	r.updates <- u
+}
+
+func newRecorderDispatcher() (*Dispatcher, chan *Update) {
+	updates := make(chan *Update, 16)
+	d := NewDispatcher("token", func(_ int64) Bot { return recorderBot{updates} })
+	return d, updates
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	var (
+		calls  []string
+		d, ch  = newRecorderDispatcher()
+		record = func(name string) Middleware {
+			return func(next HandlerFunc) HandlerFunc {
+				return func(key int64, u *Update) {
+					calls = append(calls, name)
+					next(key, u)
+				}
+			}
+		}
+	)
+
+	d.Use(record("first"), record("second"))
+	d.Use(record("third"))
+	d.handle(&Update{Message: &Message{Chat: Chat{ID: 42}}})
+
+	if u := <-ch; u.ChatID() != 42 {
+		t.Fatalf("unexpected chat ID %d", u.ChatID())
+	}
+
+	if expected := []string{"first", "second", "third"}; !reflect.DeepEqual(calls, expected) {
+		t.Fatalf("expected %v, got %v", expected, calls)
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	d, ch := newRecorderDispatcher()
+	d.Use(AllowSessions(1))
+
+	d.handle(&Update{Message: &Message{Chat: Chat{ID: 2}}})
+	d.handle(&Update{Message: &Message{Chat: Chat{ID: 1}}})
+
+	if u := <-ch; u.ChatID() != 1 {
+		t.Fatalf("update from chat %d should have been dropped", u.ChatID())
+	}
+
+	if _, ok := d.sessionMap[2]; ok {
+		t.Fatal("a session has been created for a dropped update")
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	var (
+		count int
+		h     = RateLimit(2, time.Hour)(func(_ int64, _ *Update) { count++ })
+	)
+
+	for i := 0; i < 5; i++ {
+		h(1, &Update{})
+	}
+	h(2, &Update{})
+
+	if count != 3 {
+		t.Fatalf("expected 3 updates to pass, got %d", count)
+	}
+}
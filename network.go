@@ -20,12 +20,15 @@ package echotron
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"net/url"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"sync"
 )
 
 // content is a struct which contains a file's name, its type and its data.
@@ -35,11 +38,75 @@ type content struct {
 	fdata []byte
 }
 
+var (
+	defaultClient   = http.DefaultClient
+	defaultClientMu sync.RWMutex
+)
+
+// SetDefaultClient sets the HTTP client used by the API objects without a Client of their own.
+// It defaults to http.DefaultClient.
+func SetDefaultClient(c *http.Client) {
+	if c == nil {
+		c = http.DefaultClient
+	}
+
+	defaultClientMu.Lock()
+	defaultClient = c
+	defaultClientMu.Unlock()
+}
+
+func getDefaultClient() *http.Client {
+	defaultClientMu.RLock()
+	defer defaultClientMu.RUnlock()
+	return defaultClient
+}
+
+// tokenRegexp matches the token of the bot in the URLs of the Bot API.
+var tokenRegexp = regexp.MustCompile(`/bot[0-9]+:[A-Za-z0-9_-]+`)
+
+// redactToken removes the token of the bot from the URL of the errors returned by the
+// HTTP client, which are logged and returned to the caller.
+func (a API) redactToken(err error) error {
+	var uerr *url.Error
+	if errors.As(err, &uerr) {
+		uerr.URL = tokenRegexp.ReplaceAllString(uerr.URL, "/bot<token>")
+		if a.token != "" {
+			uerr.URL = strings.ReplaceAll(uerr.URL, a.token, "<token>")
+		}
+	}
+	return err
+}
+
+// httpClient returns the HTTP client used to send the requests of the API.
+// During a dry-run replay the requests never reach Telegram, see ReplayOptions.DryRun.
+func (a API) httpClient() *http.Client {
+	if c := dryRunClient(a.ctx); c != nil {
+		return c
+	}
+	if a.client != nil {
+		if _, ok := a.client.Transport.(*DryRunTransport); ok {
+			return a.client
+		}
+	}
+	if dryRuns.Load() > 0 {
+		return dryRunRejectClient
+	}
+	if a.client != nil {
+		return a.client
+	}
+	return getDefaultClient()
+}
+
 // sendGetRequest is used to send an HTTP GET request.
-func sendGetRequest(url string) ([]byte, error) {
-	resp, err := http.Get(url)
+func (a API) sendGetRequest(url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(a.context(), "GET", url, nil)
 	if err != nil {
-		return []byte{}, err
+		return []byte{}, a.redactToken(err)
+	}
+
+	resp, err := a.httpClient().Do(req)
+	if err != nil {
+		return []byte{}, a.redactToken(err)
 	}
 	defer resp.Body.Close()
 
@@ -52,7 +119,7 @@ func sendGetRequest(url string) ([]byte, error) {
 }
 
 // sendPostRequest is used to send an HTTP POST request.
-func sendPostRequest(url string, files ...content) ([]byte, error) {
+func (a API) sendPostRequest(url string, files ...content) ([]byte, error) {
 	var buf = new(bytes.Buffer)
 	var w = multipart.NewWriter(buf)
 
@@ -66,16 +133,15 @@ func sendPostRequest(url string, files ...content) ([]byte, error) {
 
 	w.Close()
 
-	req, err := http.NewRequest("POST", url, buf)
+	req, err := http.NewRequestWithContext(a.context(), "POST", url, buf)
 	if err != nil {
-		return []byte{}, err
+		return []byte{}, a.redactToken(err)
 	}
 	req.Header.Add("Content-Type", w.FormDataContentType())
 
-	var client = new(http.Client)
-	res, err := client.Do(req)
+	res, err := a.httpClient().Do(req)
 	if err != nil {
-		return []byte{}, err
+		return []byte{}, a.redactToken(err)
 	}
 	defer res.Body.Close()
 
@@ -87,25 +153,23 @@ func sendPostRequest(url string, files ...content) ([]byte, error) {
 }
 
 // sendPostForm is used to send an "application/x-www-form-urlencoded" through an HTTP POST request.
-func sendPostForm(reqURL string, keyVals map[string]string) ([]byte, error) {
+func (a API) sendPostForm(reqURL string, keyVals map[string]string) ([]byte, error) {
 	var form = make(url.Values)
 
 	for k, v := range keyVals {
 		form.Add(k, v)
 	}
 
-	request, err := http.NewRequest("POST", reqURL, strings.NewReader(form.Encode()))
+	request, err := http.NewRequestWithContext(a.context(), "POST", reqURL, strings.NewReader(form.Encode()))
 	if err != nil {
-		return []byte{}, err
+		return []byte{}, a.redactToken(err)
 	}
 	request.PostForm = form
 	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 
-	var client http.Client
-
-	response, err := client.Do(request)
+	response, err := a.httpClient().Do(request)
 	if err != nil {
-		return []byte{}, err
+		return []byte{}, a.redactToken(err)
 	}
 	defer response.Body.Close()
 
@@ -18,6 +18,8 @@
 
 package echotron
 
+import "path/filepath"
+
 // ParseMode is a custom type for the various frequent options used by some methods of the API.
 type ParseMode string
 
@@ -111,8 +113,12 @@ const (
 	PollAnswerUpdate                    = "poll_answer"
 	MyChatMemberUpdate                  = "my_chat_member"
 	ChatMemberUpdate                    = "chat_member"
+	ChatJoinRequestUpdate               = "chat_join_request"
 )
 
+// UnknownUpdate is the type returned by the Type method of the updates not supported by echotron yet.
+const UnknownUpdate UpdateType = "unknown"
+
 // ReplyMarkup is an interface for the various keyboard types.
 type ReplyMarkup interface {
 	ImplementsReplyMarkup()
@@ -318,6 +324,18 @@ func NewInputFileBytes(fileName string, content []byte) InputFile {
 	return InputFile{path: fileName, content: content}
 }
 
+// String returns the ID or the URL of the file, or the name of the file to upload.
+func (i InputFile) String() string {
+	switch {
+	case i.id != "":
+		return i.id
+	case i.url != "":
+		return i.url
+	default:
+		return filepath.Base(i.path)
+	}
+}
+
 // PhotoOptions contains the optional parameters used by the SendPhoto method.
 type PhotoOptions struct {
 	ReplyMarkup              ReplyMarkup     `query:"reply_markup"`
@@ -0,0 +1,24 @@
+// Code generated by apigen; DO NOT EDIT.
+
+package echotron
+
+// CopyMessagesOptions contains the optional parameters used by the CopyMessages method.
+type CopyMessagesOptions struct {
+	MessageThreadID     int  `query:"message_thread_id"`
+	DisableNotification bool `query:"disable_notification"`
+	ProtectContent      bool `query:"protect_content"`
+	RemoveCaption       bool `query:"remove_caption"`
+}
+
+// ForwardMessagesOptions contains the optional parameters used by the ForwardMessages method.
+type ForwardMessagesOptions struct {
+	MessageThreadID     int  `query:"message_thread_id"`
+	DisableNotification bool `query:"disable_notification"`
+	ProtectContent      bool `query:"protect_content"`
+}
+
+// SetMessageReactionOptions contains the optional parameters used by the SetMessageReaction method.
+type SetMessageReactionOptions struct {
+	Reaction []ReactionType `query:"reaction"`
+	IsBig    bool           `query:"is_big"`
+}
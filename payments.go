@@ -112,7 +112,7 @@ func (a API) SendInvoice(chatID int64, title, description, payload, providerToke
 	vals.Set("provider_token", providerToken)
 	vals.Set("currency", currency)
 	vals.Set("prices", string(p))
-	return get[APIResponseMessage](a.base, "sendInvoice", addValues(vals, opts))
+	return get[APIResponseMessage](a, "sendInvoice", addValues(vals, opts))
 }
 
 // AnswerShippingQuery is used to reply to shipping queries.
@@ -123,7 +123,7 @@ func (a API) AnswerShippingQuery(shippingQueryID string, ok bool, opts *Shipping
 
 	vals.Set("shipping_query_id", shippingQueryID)
 	vals.Set("ok", btoa(ok))
-	return get[APIResponseBase](a.base, "answerShippingQuery", addValues(vals, opts))
+	return get[APIResponseBase](a, "answerShippingQuery", addValues(vals, opts))
 }
 
 // AnswerPreCheckoutQuery is used to respond to such pre-checkout queries.
@@ -135,7 +135,7 @@ func (a API) AnswerPreCheckoutQuery(preCheckoutQueryID string, ok bool, opts *Pr
 
 	vals.Set("pre_checkout_query_id", preCheckoutQueryID)
 	vals.Set("ok", btoa(ok))
-	return get[APIResponseBase](a.base, "answerPreCheckoutQuery", addValues(vals, opts))
+	return get[APIResponseBase](a, "answerPreCheckoutQuery", addValues(vals, opts))
 }
 
 // CreateInvoiceLink creates a link for an invoice.
@@ -153,5 +153,5 @@ func (a API) CreateInvoiceLink(title, description, payload, providerToken, curre
 	vals.Set("provider_token", providerToken)
 	vals.Set("currency", currency)
 	vals.Set("prices", string(p))
-	return get[APIResponseBase](a.base, "createInvoiceLink", addValues(vals, opts))
+	return get[APIResponseBase](a, "createInvoiceLink", addValues(vals, opts))
 }
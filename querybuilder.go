@@ -19,33 +19,111 @@
 package echotron
 
 import (
-	"encoding/json"
 	"net/url"
 	"reflect"
 	"strconv"
+	"sync"
 )
 
-func toString(v reflect.Value) string {
-	switch v.Kind() {
+// encoder returns the string representation of a value sent as a query parameter.
+type encoder func(v reflect.Value) string
+
+// queryField is a field of a struct sent as a query parameter.
+type queryField struct {
+	enc   encoder
+	name  string
+	index []int
+	// ptr reports whether the field is a pointer, whose pointed value is sent even if it's
+	// the zero value of its type, eg: to explicitly send false.
+	ptr bool
+}
+
+// queryPlans caches the fields of the struct types passed to scan, see queryPlan.
+var queryPlans sync.Map
+
+// queryPlan returns the fields with the query tag of the struct type t, including the ones
+// of the structs embedded in it without the query tag, and caches them.
+func queryPlan(t reflect.Type) []queryField {
+	if plan, ok := queryPlans.Load(t); ok {
+		return plan.([]queryField)
+	}
+
+	plan := appendFields(nil, t, nil)
+	queryPlans.Store(t, plan)
+	return plan
+}
+
+func appendFields(plan []queryField, t reflect.Type, index []int) []queryField {
+	for i := 0; i < t.NumField(); i++ {
+		var (
+			f   = t.Field(i)
+			idx = append(append([]int(nil), index...), i)
+			typ = f.Type
+		)
+
+		name := f.Tag.Get("query")
+		if name == "" {
+			if f.Anonymous && (typ.Kind() == reflect.Struct || typ.Kind() == reflect.Pointer && typ.Elem().Kind() == reflect.Struct) {
+				if typ.Kind() == reflect.Pointer {
+					typ = typ.Elem()
+				}
+				plan = appendFields(plan, typ, idx)
+			}
+			continue
+		}
+
+		field := queryField{name: name, index: idx}
+		if typ.Kind() == reflect.Pointer {
+			field.ptr = true
+			typ = typ.Elem()
+		}
+		field.enc = newEncoder(typ)
+		plan = append(plan, field)
+	}
+	return plan
+}
+
+func newEncoder(t reflect.Type) encoder {
+	switch t.Kind() {
 	case reflect.String:
-		return v.String()
+		return func(v reflect.Value) string { return v.String() }
+
+	case reflect.Float32:
+		return func(v reflect.Value) string { return strconv.FormatFloat(v.Float(), 'f', -1, 32) }
 
 	case reflect.Float64:
-		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+		return func(v reflect.Value) string { return strconv.FormatFloat(v.Float(), 'f', -1, 64) }
 
-	case reflect.Int, reflect.Int64:
-		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(v reflect.Value) string { return strconv.FormatInt(v.Int(), 10) }
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return func(v reflect.Value) string { return strconv.FormatUint(v.Uint(), 10) }
 
 	case reflect.Bool:
-		return strconv.FormatBool(v.Bool())
+		return func(v reflect.Value) string { return strconv.FormatBool(v.Bool()) }
 
-	case reflect.Struct, reflect.Interface, reflect.Slice, reflect.Array:
-		b, _ := json.Marshal(v.Interface())
-		return string(b)
+	case reflect.Struct, reflect.Interface, reflect.Slice, reflect.Array, reflect.Map:
+		return encodeJSON
 
 	default:
-		return ""
+		return func(reflect.Value) string { return "" }
+	}
+}
+
+// field returns the field of the struct e at the given index, or false if it can't be reached
+// because one of the embedded structs it belongs to is a nil pointer.
+func field(e reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && e.Kind() == reflect.Pointer {
+			if e.IsNil() {
+				return reflect.Value{}, false
+			}
+			e = e.Elem()
+		}
+		e = e.Field(x)
 	}
+	return e, true
 }
 
 func scan(i any, v url.Values) url.Values {
@@ -55,16 +133,19 @@ func scan(i any, v url.Values) url.Values {
 		e = e.Elem()
 	}
 
-	if e.Kind() == reflect.Invalid {
+	if e.Kind() != reflect.Struct {
 		return v
 	}
 
-	for i := 0; i < e.NumField(); i++ {
-		fTag := e.Type().Field(i).Tag
-
-		if name := fTag.Get("query"); name != "" && !e.Field(i).IsZero() {
-			v.Set(name, toString(e.Field(i)))
+	for _, f := range queryPlan(e.Type()) {
+		fv, ok := field(e, f.index)
+		if !ok || fv.IsZero() {
+			continue
+		}
+		if f.ptr {
+			fv = fv.Elem()
 		}
+		v.Set(f.name, f.enc(fv))
 	}
 
 	return v
@@ -1,8 +1,11 @@
 package echotron
 
 import (
+	"encoding/json"
+	"math"
 	"net/url"
 	"reflect"
+	"strconv"
 	"testing"
 )
 
@@ -35,3 +38,221 @@ func TestScan(t *testing.T) {
 		}
 	}
 }
+
+type baseQuery struct {
+	ChatID int64 `query:"chat_id"`
+}
+
+type nestedQuery struct {
+	Limit uint8 `query:"limit"`
+}
+
+type embeddingQuery struct {
+	baseQuery
+	*nestedQuery
+	Silent  *bool    `query:"silent"`
+	Offset  *int     `query:"offset"`
+	Ratio   float32  `query:"ratio"`
+	Tags    []string `query:"tags"`
+	Ignored string
+}
+
+func TestScanFields(t *testing.T) {
+	var (
+		silent = false
+		tests  = []scanTest{
+			{
+				i:        embeddingQuery{baseQuery: baseQuery{ChatID: 42}, Silent: &silent, Ratio: 0.5, Tags: []string{"a"}, Ignored: "x"},
+				expected: url.Values{"chat_id": {"42"}, "silent": {"false"}, "ratio": {"0.5"}, "tags": {`["a"]`}},
+			},
+			{
+				i:        &embeddingQuery{nestedQuery: &nestedQuery{Limit: 10}},
+				expected: url.Values{"limit": {"10"}},
+			},
+			{
+				i:        NewMessageID(42, 7),
+				expected: url.Values{"chat_id": {"42"}, "message_id": {"7"}},
+			},
+			{
+				i:        (*MessageOptions)(nil),
+				expected: url.Values{},
+			},
+		}
+	)
+
+	for i, tt := range tests {
+		if result := scan(tt.i, url.Values{}); !reflect.DeepEqual(tt.expected, result) {
+			t.Errorf("test #%d: expected %v, got %v", i, tt.expected, result)
+		}
+	}
+}
+
+// baselineScan and baselineToString are the implementation of scan before the cached plans,
+// kept as they were as the reference of the tests and the benchmarks.
+func baselineToString(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Int, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Struct, reflect.Interface, reflect.Slice, reflect.Array:
+		b, _ := json.Marshal(v.Interface())
+		return string(b)
+	default:
+		return ""
+	}
+}
+
+func baselineScan(i any, v url.Values) url.Values {
+	e := reflect.ValueOf(i)
+
+	if e.Kind() == reflect.Pointer {
+		e = e.Elem()
+	}
+
+	if e.Kind() == reflect.Invalid {
+		return v
+	}
+
+	for i := 0; i < e.NumField(); i++ {
+		fTag := e.Type().Field(i).Tag
+
+		if name := fTag.Get("query"); name != "" && !e.Field(i).IsZero() {
+			v.Set(name, baselineToString(e.Field(i)))
+		}
+	}
+
+	return v
+}
+
+var benchOptions = []any{
+	&MessageOptions{ParseMode: MarkdownV2, MessageThreadID: 3, ReplyToMessageID: 10, DisableNotification: true},
+	&PhotoOptions{Caption: "caption", ParseMode: HTML, HasSpoiler: true},
+	&MessageOptions{
+		ParseMode: MarkdownV2,
+		ReplyMarkup: InlineKeyboardMarkup{
+			InlineKeyboard: [][]InlineKeyboardButton{{{Text: "Yes", CallbackData: "yes"}, {Text: "No", CallbackData: "no"}}},
+		},
+	},
+}
+
+func TestScanMatchesBaseline(t *testing.T) {
+	for i, opts := range benchOptions {
+		if result, baseline := scan(opts, url.Values{}), baselineScan(opts, url.Values{}); !reflect.DeepEqual(result, baseline) {
+			t.Errorf("test #%d: expected %v, got %v", i, baseline, result)
+		}
+	}
+}
+
+func BenchmarkScan(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		scan(benchOptions[0], url.Values{})
+	}
+}
+
+func BenchmarkScanBaseline(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		baselineScan(benchOptions[0], url.Values{})
+	}
+}
+
+func BenchmarkScanJSON(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		scan(benchOptions[2], url.Values{})
+	}
+}
+
+func BenchmarkScanJSONBaseline(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		baselineScan(benchOptions[2], url.Values{})
+	}
+}
+
+type jsonNode struct {
+	Name     string      `json:"name"`
+	Next     *jsonNode   `json:"next,omitempty"`
+	Children []jsonNode  `json:"children,omitempty"`
+	Value    any         `json:"value,omitempty"`
+	Score    float64     `json:"score"`
+	Small    float32     `json:"small,omitempty"`
+	Counts   [2]uint8    `json:"counts"`
+	Data     []byte      `json:"data,omitempty"`
+	Meta     map[int]any `json:"meta,omitempty"`
+	Skipped  string      `json:"-"`
+	Untagged int
+	hidden   int
+}
+
+type jsonTextID int
+
+func (id *jsonTextID) MarshalText() ([]byte, error) {
+	return []byte("id-" + strconv.Itoa(int(*id))), nil
+}
+
+type jsonWithText struct {
+	ID  jsonTextID  `json:"id"`
+	Str int         `json:"str,string"`
+	Opt *jsonTextID `json:"opt,omitempty"`
+}
+
+type jsonEmbedding struct {
+	jsonNode
+	Extra bool `json:"extra"`
+}
+
+func TestEncodeJSONMatchesMarshal(t *testing.T) {
+	id := jsonTextID(7)
+	values := []any{
+		jsonNode{},
+		jsonNode{
+			Name:     "<b>\"quoted\"</b> & \\ \n\t \u2028 \u2029 \x01 è 😀 \xff",
+			Next:     &jsonNode{Name: "next", Value: NewChatID(-100)},
+			Children: []jsonNode{{Name: "child", Value: []any{1, "two", nil, 3.5}}},
+			Value:    map[string]int{"b": 2, "a": 1},
+			Score:    1e21,
+			Small:    1e-7,
+			Counts:   [2]uint8{1, 2},
+			Data:     []byte("data"),
+			Meta:     map[int]any{2: "two", 1: true},
+			Skipped:  "skipped",
+			Untagged: -1,
+			hidden:   1,
+		},
+		jsonWithText{ID: 1, Str: 2, Opt: &id},
+		jsonEmbedding{jsonNode: jsonNode{Name: "embedded"}, Extra: true},
+		[]float64{0, -0.5, 123456789, 1e-7, 1.5e300},
+		InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{{{Text: "text", CallbackData: "data"}}}},
+		ReplyKeyboardMarkup{Keyboard: [][]KeyboardButton{{{Text: "text", RequestContact: true}}}, ResizeKeyboard: true},
+		BotCommandScope{Type: BCSTChat, ChatID: 123},
+		[]MessageEntity{{Type: BoldEntity, Offset: 1, Length: 2}},
+	}
+
+	for i, value := range values {
+		expected, _ := json.Marshal(value)
+		if result := encodeJSON(reflect.ValueOf(value)); result != string(expected) {
+			t.Errorf("test #%d: expected %s, got %s", i, expected, result)
+		}
+
+		// The encoding mustn't change when the value is reached from a pointer, as it is
+		// in the options passed to scan.
+		ptr := reflect.New(reflect.TypeOf(value))
+		ptr.Elem().Set(reflect.ValueOf(value))
+		if result := encodeJSON(ptr.Elem()); result != string(expected) {
+			t.Errorf("test #%d: expected %s, got %s from an addressable value", i, expected, result)
+		}
+	}
+}
+
+func TestEncodeJSONUnsupported(t *testing.T) {
+	if result := encodeJSON(reflect.ValueOf(jsonNode{Score: math.NaN()})); result != "" {
+		t.Errorf("expected an empty string, got %s", result)
+	}
+}
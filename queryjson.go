@@ -0,0 +1,371 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package echotron
+
+import (
+	"encoding"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// appendJSON appends the JSON encoding of v to b, the same as json.Marshal would produce.
+type appendJSON func(b []byte, v reflect.Value) ([]byte, error)
+
+// jsonPlan is the cached JSON encoder of a type nested in the options sent as query parameters.
+type jsonPlan struct {
+	enc appendJSON
+	// addr reports whether the encoding depends on the value being addressable, because
+	// the type or one of its fields has a MarshalJSON or MarshalText method on the pointer.
+	addr bool
+}
+
+// jsonField is a field of a struct encoded to JSON.
+type jsonField struct {
+	plan      *jsonPlan
+	key       []byte
+	index     int
+	omitEmpty bool
+}
+
+var (
+	// jsonPlans caches the encoders of the types passed to encodeJSON, see jsonPlanOf.
+	jsonPlans sync.Map
+
+	marshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// encodeJSON encodes the nested structs, slices and maps with the cached plans of their types,
+// which fall back to encoding/json for the custom Marshalers and the types without a plan.
+func encodeJSON(v reflect.Value) string {
+	plan := jsonPlanOf(v.Type())
+	// json.Marshal receives a copy of the value, so nothing in it is addressable.
+	if plan.addr && v.CanAddr() {
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		v = c
+	}
+
+	b, err := plan.enc(make([]byte, 0, 64), v)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// jsonPlanOf returns the JSON encoder of the type t and caches it.
+func jsonPlanOf(t reflect.Type) *jsonPlan {
+	if plan, ok := jsonPlans.Load(t); ok {
+		return plan.(*jsonPlan)
+	}
+
+	plan, _ := jsonPlans.LoadOrStore(t, newJSONPlan(t))
+	return plan.(*jsonPlan)
+}
+
+// lazyJSON returns an encoder that looks up the plan of t when it's first used,
+// so that the recursive types don't recurse while building their plans.
+func lazyJSON(t reflect.Type) appendJSON {
+	var (
+		once sync.Once
+		plan *jsonPlan
+	)
+
+	return func(b []byte, v reflect.Value) ([]byte, error) {
+		once.Do(func() { plan = jsonPlanOf(t) })
+		return plan.enc(b, v)
+	}
+}
+
+func newJSONPlan(t reflect.Type) *jsonPlan {
+	if t.Implements(marshalerType) || t.Implements(textMarshalerType) {
+		return &jsonPlan{enc: marshalJSON}
+	}
+	if t.Kind() != reflect.Pointer && (reflect.PointerTo(t).Implements(marshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)) {
+		return &jsonPlan{enc: marshalAddrJSON, addr: true}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &jsonPlan{enc: appendJSONString}
+
+	case reflect.Bool:
+		return &jsonPlan{enc: func(b []byte, v reflect.Value) ([]byte, error) {
+			return strconv.AppendBool(b, v.Bool()), nil
+		}}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &jsonPlan{enc: func(b []byte, v reflect.Value) ([]byte, error) {
+			return strconv.AppendInt(b, v.Int(), 10), nil
+		}}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &jsonPlan{enc: func(b []byte, v reflect.Value) ([]byte, error) {
+			return strconv.AppendUint(b, v.Uint(), 10), nil
+		}}
+
+	case reflect.Float32:
+		return &jsonPlan{enc: func(b []byte, v reflect.Value) ([]byte, error) {
+			return appendJSONFloat(b, v, 32)
+		}}
+
+	case reflect.Float64:
+		return &jsonPlan{enc: func(b []byte, v reflect.Value) ([]byte, error) {
+			return appendJSONFloat(b, v, 64)
+		}}
+
+	case reflect.Interface:
+		return &jsonPlan{enc: appendJSONInterface}
+
+	case reflect.Pointer:
+		elem := lazyJSON(t.Elem())
+		return &jsonPlan{enc: func(b []byte, v reflect.Value) ([]byte, error) {
+			if v.IsNil() {
+				return append(b, "null"...), nil
+			}
+			return elem(b, v.Elem())
+		}}
+
+	case reflect.Slice:
+		// Byte slices are encoded as base64 strings.
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &jsonPlan{enc: marshalJSON}
+		}
+		elem := lazyJSON(t.Elem())
+		return &jsonPlan{enc: func(b []byte, v reflect.Value) ([]byte, error) {
+			if v.IsNil() {
+				return append(b, "null"...), nil
+			}
+			return appendJSONList(b, v, elem)
+		}}
+
+	case reflect.Array:
+		elem := jsonPlanOf(t.Elem())
+		return &jsonPlan{
+			enc: func(b []byte, v reflect.Value) ([]byte, error) {
+				return appendJSONList(b, v, elem.enc)
+			},
+			addr: elem.addr,
+		}
+
+	case reflect.Struct:
+		return newJSONStructPlan(t)
+
+	default:
+		// Maps need their keys sorted and the other kinds are rejected by encoding/json,
+		// which handles both.
+		return &jsonPlan{enc: marshalJSON}
+	}
+}
+
+// newJSONStructPlan returns the plan of the struct type t, or falls back to encoding/json
+// if t embeds other types or uses tag options other than omitempty.
+func newJSONStructPlan(t reflect.Type) *jsonPlan {
+	var (
+		fields []jsonField
+		addr   bool
+	)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			return &jsonPlan{enc: marshalJSON}
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if opts != "" && opts != "omitempty" || !validJSONKey(name) {
+			return &jsonPlan{enc: marshalJSON}
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		key, _ := json.Marshal(name)
+		plan := jsonPlanOf(f.Type)
+		addr = addr || plan.addr
+		fields = append(fields, jsonField{
+			plan:      plan,
+			key:       append(key, ':'),
+			index:     i,
+			omitEmpty: opts == "omitempty",
+		})
+	}
+
+	return &jsonPlan{
+		enc: func(b []byte, v reflect.Value) ([]byte, error) {
+			var err error
+
+			b = append(b, '{')
+			first := true
+			for _, f := range fields {
+				fv := v.Field(f.index)
+				if f.omitEmpty && isEmptyJSON(fv) {
+					continue
+				}
+				if !first {
+					b = append(b, ',')
+				}
+				first = false
+				b = append(b, f.key...)
+				if b, err = f.plan.enc(b, fv); err != nil {
+					return b, err
+				}
+			}
+			return append(b, '}'), nil
+		},
+		addr: addr,
+	}
+}
+
+// validJSONKey reports whether the key is made only of the characters that encoding/json
+// accepts in every position, so that the tag is used the same way.
+func validJSONKey(key string) bool {
+	for _, c := range key {
+		if c != '_' && c != '-' && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func appendJSONList(b []byte, v reflect.Value, elem appendJSON) ([]byte, error) {
+	var err error
+
+	b = append(b, '[')
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		if b, err = elem(b, v.Index(i)); err != nil {
+			return b, err
+		}
+	}
+	return append(b, ']'), nil
+}
+
+func appendJSONInterface(b []byte, v reflect.Value) ([]byte, error) {
+	if v.IsNil() {
+		return append(b, "null"...), nil
+	}
+	e := v.Elem()
+	return jsonPlanOf(e.Type()).enc(b, e)
+}
+
+// appendJSONString appends the string quoted as encoding/json does, which escapes the HTML
+// characters too, and falls back to it for the strings that need escaping.
+func appendJSONString(b []byte, v reflect.Value) ([]byte, error) {
+	s := v.String()
+
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c < 0x20 || c == '"' || c == '\\' || c == '<' || c == '>' || c == '&' {
+				return marshalJSONString(b, s)
+			}
+			i++
+			continue
+		}
+
+		r, n := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && n == 1 || r == '\u2028' || r == '\u2029' {
+			return marshalJSONString(b, s)
+		}
+		i += n
+	}
+
+	b = append(b, '"')
+	b = append(b, s...)
+	return append(b, '"'), nil
+}
+
+func marshalJSONString(b []byte, s string) ([]byte, error) {
+	j, err := json.Marshal(s)
+	return append(b, j...), err
+}
+
+// appendJSONFloat appends the float in the same format as encoding/json.
+func appendJSONFloat(b []byte, v reflect.Value, bits int) ([]byte, error) {
+	f := v.Float()
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return b, &json.UnsupportedValueError{Value: v, Str: strconv.FormatFloat(f, 'g', -1, bits)}
+	}
+
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+
+	b = strconv.AppendFloat(b, f, format, -1, bits)
+	if format == 'e' {
+		// Clean up e-09 to e-9.
+		if n := len(b); n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+	return b, nil
+}
+
+// isEmptyJSON reports whether the value is omitted by the omitempty option.
+func isEmptyJSON(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
+
+// marshalJSON encodes the value with encoding/json.
+func marshalJSON(b []byte, v reflect.Value) ([]byte, error) {
+	j, err := json.Marshal(v.Interface())
+	return append(b, j...), err
+}
+
+// marshalAddrJSON encodes the value with encoding/json through a pointer when it's addressable,
+// so that the methods on the pointer are used as encoding/json would.
+func marshalAddrJSON(b []byte, v reflect.Value) ([]byte, error) {
+	if v.CanAddr() {
+		v = v.Addr()
+	}
+	return marshalJSON(b, v)
+}
@@ -0,0 +1,467 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package echotron
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RecordedUpdate is a line of the JSONL files written by the UpdateRecorder.
+type RecordedUpdate struct {
+	// Time is the time the update has been received at.
+	Time time.Time `json:"time"`
+	// Update is the raw update, as received from Telegram.
+	Update json.RawMessage `json:"update"`
+}
+
+// UpdateRecorderOptions contains the optional parameters used by the NewUpdateRecorder function.
+type UpdateRecorderOptions struct {
+	// MaxSize is the size in bytes after which the file is rotated, 0 means no rotation.
+	MaxSize int64
+	// MaxBackups is the number of rotated files to keep, 0 means all of them.
+	// The rotated files are named after the file with a numeric suffix, the most recent being ".1".
+	MaxBackups int
+}
+
+// UpdateRecorder appends the updates to a JSONL file, one RecordedUpdate per line,
+// so that they can be inspected or replayed into a Dispatcher with Replay.
+// Set it with Dispatcher.SetRecorder to record the updates received by the Dispatcher.
+type UpdateRecorder struct {
+	file       *os.File
+	path       string
+	size       int64
+	maxSize    int64
+	maxBackups int
+	mu         sync.Mutex
+}
+
+// NewUpdateRecorder returns a new UpdateRecorder appending the updates to the file at the given path.
+func NewUpdateRecorder(path string, opts *UpdateRecorderOptions) (*UpdateRecorder, error) {
+	r := &UpdateRecorder{path: path}
+
+	if opts != nil {
+		r.maxSize = opts.MaxSize
+		r.maxBackups = opts.MaxBackups
+	}
+	return r, r.open()
+}
+
+func (r *UpdateRecorder) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	r.file = f
+	r.size = info.Size()
+	return nil
+}
+
+// Record appends the update to the file, rotating it if needed.
+// The update is written as received from Telegram if it has been decoded from JSON, see Update.Raw.
+func (r *UpdateRecorder) Record(update *Update) error {
+	raw := update.Raw()
+	if raw == nil {
+		var err error
+		if raw, err = json.Marshal(update); err != nil {
+			return err
+		}
+	}
+
+	line, err := json.Marshal(RecordedUpdate{Time: time.Now(), Update: raw})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return os.ErrClosed
+	}
+
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(line)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := r.file.Write(line)
+	r.size += int64(n)
+	return err
+}
+
+// rotate renames the current file with the suffix ".1", shifting the older ones, and opens a new one.
+func (r *UpdateRecorder) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	r.file = nil
+
+	n := 1
+	for ; r.maxBackups == 0 || n < r.maxBackups; n++ {
+		if _, err := os.Stat(backupName(r.path, n)); err != nil {
+			break
+		}
+	}
+
+	if r.maxBackups > 0 {
+		if err := os.Remove(backupName(r.path, n)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	for i := n; i > 1; i-- {
+		if err := os.Rename(backupName(r.path, i-1), backupName(r.path, i)); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Rename(r.path, backupName(r.path, 1)); err != nil {
+		return err
+	}
+	return r.open()
+}
+
+func backupName(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
+
+// Close closes the file.
+func (r *UpdateRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return os.ErrClosed
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// ReplayOptions contains the optional parameters used by the Replay method.
+type ReplayOptions struct {
+	// DryRun, if not nil, receives the calls made to the Telegram Bot API during the replay instead of Telegram,
+	// see DryRunTransport. It receives the calls of the API of the Dispatcher and of the API objects bound to the
+	// context of the replayed updates with API.WithContext(update.Context()). Until the replay returns, the calls
+	// of any other API object in the process fail with ErrDryRun, so that none of them reaches Telegram.
+	DryRun io.Writer
+	// Speed is the factor the original timing of the updates is accelerated by, eg: 2 replays them
+	// twice as fast. If 0 the updates are replayed one after the other without waiting.
+	Speed float64
+}
+
+// Replay feeds the updates recorded by an UpdateRecorder in the file at the given path to the Dispatcher,
+// in order, and returns once all of them have been handled or ctx is done.
+// The updates are handled synchronously, like with Dispatch.
+func (d *Dispatcher) Replay(ctx context.Context, path string, opts *ReplayOptions) error {
+	if opts == nil {
+		opts = &ReplayOptions{}
+	}
+
+	dispatch := d.Dispatch
+	if opts.DryRun != nil {
+		client := &http.Client{Transport: &DryRunTransport{Sink: opts.DryRun}}
+
+		dryRuns.Add(1)
+		defer dryRuns.Add(-1)
+
+		d.mu.Lock()
+		api := d.api
+		d.api.client = client
+		d.mu.Unlock()
+
+		defer func() {
+			d.mu.Lock()
+			d.api = api
+			d.mu.Unlock()
+		}()
+
+		uctx := context.WithValue(ctx, dryRunClientKey{}, client)
+		dispatch = func(update *Update) {
+			update.ctx = uctx
+			d.Dispatch(update)
+		}
+	}
+
+	return FileSource{Path: path, Speed: opts.Speed}.each(ctx, dispatch)
+}
+
+// ErrDryRun is the error returned during a dry-run replay by the calls to the Telegram Bot API made
+// by the API objects not bound to a replayed update, see ReplayOptions.DryRun.
+var ErrDryRun = errors.New("echotron: call rejected during a dry-run replay")
+
+// dryRuns is the number of dry-run replays in progress, while it's not 0 the API objects
+// not bound to a replayed update can't reach Telegram, see API.httpClient.
+var dryRuns atomic.Int64
+
+var dryRunRejectClient = &http.Client{Transport: dryRunReject{}}
+
+// dryRunReject is an http.RoundTripper failing all the requests with ErrDryRun.
+type dryRunReject struct{}
+
+func (dryRunReject) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, ErrDryRun
+}
+
+// dryRunClientKey is the context key of the HTTP client set by Replay for the dry-run mode.
+type dryRunClientKey struct{}
+
+// dryRunClient returns the HTTP client set by Replay in ctx for the dry-run mode, if any.
+func dryRunClient(ctx context.Context) *http.Client {
+	if ctx == nil {
+		return nil
+	}
+	c, _ := ctx.Value(dryRunClientKey{}).(*http.Client)
+	return c
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// DryRunCall is a line of the JSONL output written by the DryRunTransport.
+type DryRunCall struct {
+	Time   time.Time           `json:"time"`
+	Params map[string][]string `json:"params,omitempty"`
+	Method string              `json:"method"`
+}
+
+// DryRunTransport is an http.RoundTripper that doesn't send the calls to the Telegram Bot API:
+// it writes them to Sink, one DryRunCall per line, and replies with a successful response carrying
+// a stub of the result of the method, eg: a message with a new ID in the target chat for the send methods.
+// The uploaded files are written as their file name.
+type DryRunTransport struct {
+	Sink   io.Writer
+	mu     sync.Mutex
+	nextID int
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *DryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	r.Body = req.Body
+	if r.Body == nil {
+		r.Body = http.NoBody
+	}
+	defer r.Body.Close()
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil && err != http.ErrNotMultipart {
+		return nil, err
+	}
+
+	call := DryRunCall{
+		Time:   time.Now(),
+		Params: r.Form,
+		Method: req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:],
+	}
+	if r.MultipartForm != nil {
+		for name, files := range r.MultipartForm.File {
+			for _, f := range files {
+				call.Params[name] = append(call.Params[name], f.Filename)
+			}
+		}
+	}
+
+	line, err := json.Marshal(call)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	_, err = t.Sink.Write(append(line, '\n'))
+	id := t.nextID + 1
+	t.nextID += dryRunCount(call.Params, call.Method)
+	t.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(struct {
+		Result any  `json:"result"`
+		Ok     bool `json:"ok"`
+	}{dryRunResult(call.Method, call.Params, id), true})
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+var (
+	// dryRunResults maps the methods of the Telegram Bot API to the type of their result.
+	dryRunResults     map[string]reflect.Type
+	dryRunResultsOnce sync.Once
+
+	messageType    = reflect.TypeOf(&Message{})
+	messagesType   = reflect.TypeOf([]*Message{})
+	messageIDType  = reflect.TypeOf(&MessageID{})
+	messageIDsType = reflect.TypeOf([]*MessageID{})
+)
+
+// dryRunResultType returns the type of the result of the given method, taken from the
+// response returned by the corresponding method of API, or nil if it's not known.
+func dryRunResultType(method string) reflect.Type {
+	dryRunResultsOnce.Do(func() {
+		dryRunResults = make(map[string]reflect.Type)
+
+		t := reflect.TypeOf(API{})
+		for i := 0; i < t.NumMethod(); i++ {
+			m := t.Method(i)
+			if m.Type.NumOut() == 0 || m.Type.Out(0).Kind() != reflect.Struct {
+				continue
+			}
+			if f, ok := m.Type.Out(0).FieldByName("Result"); ok {
+				dryRunResults[strings.ToLower(m.Name[:1])+m.Name[1:]] = f.Type
+			}
+		}
+	})
+	return dryRunResults[method]
+}
+
+// dryRunCount returns the number of messages sent by the call, which get consecutive IDs.
+func dryRunCount(params map[string][]string, method string) int {
+	var items []json.RawMessage
+
+	switch dryRunResultType(method) {
+	case messagesType:
+		json.Unmarshal([]byte(firstValue(params["media"])), &items)
+	case messageIDsType:
+		json.Unmarshal([]byte(firstValue(params["message_ids"])), &items)
+	default:
+		return 1
+	}
+
+	if len(items) == 0 {
+		return 1
+	}
+	return len(items)
+}
+
+// dryRunResult returns a plausible result of the call to the given method, using id as the ID
+// of the first message it sends.
+func dryRunResult(method string, params map[string][]string, id int) any {
+	switch t := dryRunResultType(method); t {
+	case nil:
+		return true
+	case messageType:
+		return dryRunMessage(params, id)
+	case messagesType:
+		msgs := make([]*Message, dryRunCount(params, method))
+		for i := range msgs {
+			msgs[i] = dryRunMessage(params, id+i)
+		}
+		return msgs
+	case messageIDType:
+		return &MessageID{MessageID: id}
+	case messageIDsType:
+		ids := make([]*MessageID, dryRunCount(params, method))
+		for i := range ids {
+			ids[i] = &MessageID{MessageID: id + i}
+		}
+		return ids
+	default:
+		switch t.Kind() {
+		case reflect.Bool:
+			return true
+		case reflect.Pointer:
+			return reflect.New(t.Elem()).Interface()
+		case reflect.Slice:
+			return reflect.MakeSlice(t, 0, 0).Interface()
+		default:
+			return reflect.Zero(t).Interface()
+		}
+	}
+}
+
+// dryRunMessage returns a stub of the message sent with the given parameters.
+func dryRunMessage(params map[string][]string, id int) *Message {
+	msg := &Message{
+		ID:      id,
+		Date:    int(time.Now().Unix()),
+		Text:    firstValue(params["text"]),
+		Caption: firstValue(params["caption"]),
+	}
+
+	chat := firstValue(params["chat_id"])
+	if strings.HasPrefix(chat, "@") {
+		msg.Chat = Chat{Username: chat[1:], Type: "channel"}
+		return msg
+	}
+
+	msg.Chat.ID, _ = strconv.ParseInt(chat, 10, 64)
+	if msg.Chat.ID < 0 {
+		msg.Chat.Type = "supergroup"
+	} else {
+		msg.Chat.Type = "private"
+	}
+	return msg
+}
+
+func firstValue(s []string) string {
+	if len(s) > 0 {
+		return s[0]
+	}
+	return ""
+}
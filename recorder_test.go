@@ -0,0 +1,239 @@
+package echotron
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUpdateRecorder(t *testing.T) {
+	var (
+		path   = filepath.Join(t.TempDir(), "updates.jsonl")
+		raw    = `{"update_id":1,"message":{"message_id":5,"chat":{"id":42,"type":"private"},"date":0,"text":"hi","unknown_field":true}}`
+		update Update
+	)
+
+	if err := json.Unmarshal([]byte(raw), &update); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := NewUpdateRecorder(path, &UpdateRecorderOptions{MaxSize: 200, MaxBackups: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 4; i++ {
+		if err := rec.Record(&update); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range []string{path, path + ".1", path + ".2"} {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), `"unknown_field":true`) {
+			t.Fatalf("%s doesn't contain the raw update: %s", p, data)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Fatal("too many backups have been kept")
+	}
+}
+
+func TestReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "updates.jsonl")
+
+	rec, err := NewUpdateRecorder(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.Record(&Update{ID: 1, Message: &Message{Chat: Chat{ID: 42}, Text: "first"}})
+	rec.Record(&Update{ID: 2, Message: &Message{Chat: Chat{ID: 42}, Text: "second"}})
+	rec.Close()
+
+	var (
+		texts []string
+		sent  []*Message
+		sink  bytes.Buffer
+		api   = NewAPI("token")
+		d     = NewDispatcher("token", func(chatID int64) Bot {
+			return botFunc(func(u *Update) {
+				texts = append(texts, u.Message.Text)
+				if getDefaultClient() != http.DefaultClient {
+					t.Error("the default client has been replaced during the replay")
+				}
+
+				res, err := api.WithContext(u.Context()).SendMessage("echo "+u.Message.Text, chatID, nil)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				sent = append(sent, res.Result)
+
+				if _, err := api.SendChatAction(Typing, chatID, nil); !errors.Is(err, ErrDryRun) {
+					t.Errorf("expected ErrDryRun from an API not bound to the update, got %v", err)
+				}
+			})
+		})
+	)
+
+	if err := d.Replay(context.Background(), path, &ReplayOptions{DryRun: &sink, Speed: 100}); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(texts, ",") != "first,second" {
+		t.Fatalf("unexpected updates %v", texts)
+	}
+
+	var calls []DryRunCall
+	for _, line := range strings.Split(strings.TrimSpace(sink.String()), "\n") {
+		var c DryRunCall
+		if err := json.Unmarshal([]byte(line), &c); err != nil {
+			t.Fatal(err)
+		}
+		calls = append(calls, c)
+	}
+	if len(calls) != 2 || calls[1].Method != "sendMessage" || calls[1].Params["text"][0] != "echo second" {
+		t.Fatalf("unexpected dry-run calls %+v", calls)
+	}
+
+	if len(sent) != 2 || sent[1] == nil {
+		t.Fatalf("unexpected results %+v", sent)
+	}
+	if sent[0].ID != 1 || sent[1].ID != 2 || sent[1].Chat.ID != 42 || sent[1].Text != "echo second" {
+		t.Fatalf("unexpected stub message %+v", sent[1])
+	}
+}
+
+func TestReplayDispatcherAPI(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "updates.jsonl")
+
+	rec, err := NewUpdateRecorder(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.Record(&Update{ID: 1, Message: &Message{Chat: Chat{ID: 42}, Text: "hi"}})
+	rec.Close()
+
+	var (
+		d    *Dispatcher
+		sink bytes.Buffer
+	)
+	d = NewDispatcher("token", func(chatID int64) Bot {
+		return botFunc(func(u *Update) {
+			d.mu.Lock()
+			api := d.api
+			d.mu.Unlock()
+
+			if _, err := api.SendMessage("hello", chatID, nil); err != nil {
+				t.Error(err)
+			}
+		})
+	})
+
+	if err := d.Replay(context.Background(), path, &ReplayOptions{DryRun: &sink}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sink.String(), `"method":"sendMessage"`) {
+		t.Fatalf("the call of the Dispatcher API didn't reach the sink: %q", sink.String())
+	}
+	if d.api.client != nil || dryRuns.Load() != 0 {
+		t.Fatal("the dry run has not been stopped")
+	}
+}
+
+func TestDispatcherRecorder(t *testing.T) {
+	var (
+		path = filepath.Join(t.TempDir(), "updates.jsonl")
+		d    = NewDispatcher("token", func(chatID int64) Bot {
+			return botFunc(func(*Update) {})
+		})
+	)
+
+	rec, err := NewUpdateRecorder(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rec.Close()
+	d.SetRecorder(rec)
+
+	for _, body := range []string{
+		`{"update_id":1,"message":{"message_id":1,"chat":{"id":42,"type":"private"},"date":0,"text":"first"}}`,
+		`{"update_id":2,"message":{"message_id":2,"chat":{"id":42,"type":"private"},"date":0,"text":"second"}}`,
+	} {
+		d.HandleWebhook(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader(body)))
+	}
+	d.Dispatch(&Update{ID: 3, Message: &Message{Chat: Chat{ID: 42}, Text: "dispatched"}})
+
+	var data []byte
+	for i := 0; i < 100 && strings.Count(string(data), "\n") < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+		data, _ = os.ReadFile(path)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"text":"first"`) || !strings.Contains(lines[1], `"text":"second"`) {
+		t.Fatalf("unexpected recorded updates %q", data)
+	}
+}
+
+func TestDryRunResult(t *testing.T) {
+	tests := []struct {
+		method   string
+		params   map[string][]string
+		expected string
+	}{
+		{"sendMediaGroup", map[string][]string{"chat_id": {"-5"}, "media": {`[{},{}]`}}, `[{"chat":{"type":"supergroup","id":-5},"message_id":7,"date":0},{"chat":{"type":"supergroup","id":-5},"message_id":8,"date":0}]`},
+		{"copyMessage", nil, `{"message_id":7}`},
+		{"getChatAdministrators", nil, `[]`},
+		{"getChatMemberCount", nil, `0`},
+		{"deleteMessage", nil, `true`},
+		{"getMe", nil, `{"first_name":"","id":0,"is_bot":false}`},
+		{"unknownMethod", nil, `true`},
+	}
+
+	for _, tt := range tests {
+		res := dryRunResult(tt.method, tt.params, 7)
+		for _, m := range resultMessages(res) {
+			m.Date = 0
+		}
+
+		b, err := json.Marshal(res)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.method, tt.expected, b)
+		}
+	}
+}
+
+func resultMessages(res any) []*Message {
+	switch r := res.(type) {
+	case *Message:
+		return []*Message{r}
+	case []*Message:
+		return r
+	default:
+		return nil
+	}
+}
+
+type botFunc func(*Update)
+
+func (f botFunc) Update(u *Update) {
+	f(u)
+}
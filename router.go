@@ -0,0 +1,317 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package echotron
+
+import (
+	"regexp"
+	"strings"
+)
+
+// RouteFunc is the function called by the Router when an update matches a route.
+// args contains the arguments extracted by the route: the words following a command,
+// the callback data following the prefix or the submatches of a regular expression.
+type RouteFunc func(update *Update, args []string)
+
+// ContentType is a custom type for the various kinds of content a message can carry.
+type ContentType string
+
+// These are all the content types the Router can route messages by.
+const (
+	TextContent      ContentType = "text"
+	PhotoContent                 = "photo"
+	VideoContent                 = "video"
+	AnimationContent             = "animation"
+	AudioContent                 = "audio"
+	VoiceContent                 = "voice"
+	VideoNoteContent             = "video_note"
+	DocumentContent              = "document"
+	StickerContent               = "sticker"
+	LocationContent              = "location"
+	VenueContent                 = "venue"
+	ContactContent               = "contact"
+	PollContent                  = "poll"
+	DiceContent                  = "dice"
+)
+
+type commandRoute struct {
+	handler     RouteFunc
+	name        string
+	description string
+}
+
+type prefixRoute struct {
+	handler RouteFunc
+	prefix  string
+}
+
+type regexpRoute struct {
+	handler RouteFunc
+	re      *regexp.Regexp
+}
+
+// Router dispatches updates to handlers according to their commands, callback data and content,
+// so that the Update method of a Bot doesn't need to switch on them.
+// A Router can be used directly as a Bot, as a Middleware of a Dispatcher or to wrap another Bot.
+// Routes must be registered before the Router starts handling updates.
+type Router struct {
+	commands       map[string]commandRoute
+	contents       map[ContentType]RouteFunc
+	fallback       RouteFunc
+	botName        string
+	commandList    []commandRoute
+	prefixes       []prefixRoute
+	callbackRegexp []regexpRoute
+	textRegexp     []regexpRoute
+}
+
+// NewRouter returns a new Router.
+// botName is the username of the bot, used to tell apart the commands in the form /command@BotName
+// addressed to this bot from the ones addressed to other bots in the same group.
+func NewRouter(botName string) *Router {
+	return &Router{
+		commands: make(map[string]commandRoute),
+		contents: make(map[ContentType]RouteFunc),
+		botName:  strings.TrimPrefix(botName, "@"),
+	}
+}
+
+// Command routes the messages starting with /name to h, with the following words as arguments.
+// The description is used by Commands, commands with an empty description are not listed.
+func (r *Router) Command(name, description string, h RouteFunc) {
+	cmd := commandRoute{
+		handler:     h,
+		name:        strings.TrimPrefix(name, "/"),
+		description: description,
+	}
+
+	if _, ok := r.commands[cmd.name]; !ok {
+		r.commandList = append(r.commandList, cmd)
+	} else {
+		for i, c := range r.commandList {
+			if c.name == cmd.name {
+				r.commandList[i] = cmd
+			}
+		}
+	}
+	r.commands[cmd.name] = cmd
+}
+
+// Callback routes the callback queries whose data starts with prefix to h,
+// with the rest of the data as the only argument.
+func (r *Router) Callback(prefix string, h RouteFunc) {
+	r.prefixes = append(r.prefixes, prefixRoute{h, prefix})
+}
+
+// CallbackRegexp routes the callback queries whose data matches the regular expression pattern to h,
+// with the submatches as arguments, without the full match. It panics if pattern can't be compiled.
+func (r *Router) CallbackRegexp(pattern string, h RouteFunc) {
+	r.callbackRegexp = append(r.callbackRegexp, regexpRoute{h, regexp.MustCompile(pattern)})
+}
+
+// Text routes the messages whose text matches the regular expression pattern to h,
+// with the submatches as arguments, without the full match. It panics if pattern can't be compiled.
+func (r *Router) Text(pattern string, h RouteFunc) {
+	r.textRegexp = append(r.textRegexp, regexpRoute{h, regexp.MustCompile(pattern)})
+}
+
+// Content routes the messages carrying the given type of content to h.
+func (r *Router) Content(t ContentType, h RouteFunc) {
+	r.contents[t] = h
+}
+
+// Default sets the handler for the updates not matched by any other route.
+func (r *Router) Default(h RouteFunc) {
+	r.fallback = h
+}
+
+// Commands returns the list of the registered commands with a description,
+// ready to be passed to SetMyCommands.
+func (r *Router) Commands() []BotCommand {
+	var ret []BotCommand
+
+	for _, c := range r.commandList {
+		if c.description != "" {
+			ret = append(ret, BotCommand{Command: c.name, Description: c.description})
+		}
+	}
+	return ret
+}
+
+// Route passes the update to the handler of the first matching route and reports whether one was found.
+// Commands are matched first, then the text patterns and the content types.
+// Callback queries are matched by prefix first and then by pattern.
+func (r *Router) Route(update *Update) bool {
+	if h, args, ok := r.match(update); ok {
+		h(update, args)
+		return true
+	}
+
+	if r.fallback != nil {
+		r.fallback(update, nil)
+		return true
+	}
+	return false
+}
+
+// Update implements the Bot interface.
+func (r *Router) Update(update *Update) {
+	r.Route(update)
+}
+
+// Middleware returns a Middleware that routes the updates and passes to next only the ones without a match.
+func (r *Router) Middleware() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(sessionKey int64, update *Update) {
+			if !r.Route(update) {
+				next(sessionKey, update)
+			}
+		}
+	}
+}
+
+// Wrap returns a Bot that routes the updates and passes to b only the ones without a match.
+func (r *Router) Wrap(b Bot) Bot {
+	return wrappedBot{r, b}
+}
+
+type wrappedBot struct {
+	router *Router
+	bot    Bot
+}
+
+func (w wrappedBot) Update(update *Update) {
+	if !w.router.Route(update) {
+		w.bot.Update(update)
+	}
+}
+
+func (r *Router) match(update *Update) (RouteFunc, []string, bool) {
+	if cq := update.CallbackQuery; cq != nil {
+		return r.matchCallback(cq.Data)
+	}
+
+	msg := update.Message
+	if msg == nil {
+		msg = update.ChannelPost
+	}
+	if msg == nil {
+		return nil, nil, false
+	}
+
+	if h, args, ok := r.matchCommand(msg.Text); ok {
+		return h, args, true
+	}
+
+	if msg.Text != "" {
+		for _, rt := range r.textRegexp {
+			if m := rt.re.FindStringSubmatch(msg.Text); m != nil {
+				return rt.handler, m[1:], true
+			}
+		}
+	}
+
+	if h, ok := r.contents[messageContent(msg)]; ok {
+		return h, nil, true
+	}
+	return nil, nil, false
+}
+
+func (r *Router) matchCallback(data string) (RouteFunc, []string, bool) {
+	for _, rt := range r.prefixes {
+		if strings.HasPrefix(data, rt.prefix) {
+			return rt.handler, []string{strings.TrimPrefix(data, rt.prefix)}, true
+		}
+	}
+
+	for _, rt := range r.callbackRegexp {
+		if m := rt.re.FindStringSubmatch(data); m != nil {
+			return rt.handler, m[1:], true
+		}
+	}
+	return nil, nil, false
+}
+
+func (r *Router) matchCommand(text string) (RouteFunc, []string, bool) {
+	name, args, ok := ParseCommand(text)
+	if !ok {
+		return nil, nil, false
+	}
+
+	if n, bot, found := strings.Cut(name, "@"); found {
+		if !strings.EqualFold(bot, r.botName) {
+			return nil, nil, false
+		}
+		name = n
+	}
+
+	if cmd, ok := r.commands[name]; ok {
+		return cmd.handler, args, true
+	}
+	return nil, nil, false
+}
+
+// ParseCommand splits a message text in the form "/command@BotName arg1 arg2" into the command name,
+// including the eventual @BotName suffix, and its arguments. ok is false if text is not a command.
+func ParseCommand(text string) (name string, args []string, ok bool) {
+	if !strings.HasPrefix(text, "/") {
+		return "", nil, false
+	}
+
+	fields := strings.Fields(text)
+	if name = strings.TrimPrefix(fields[0], "/"); name == "" {
+		return "", nil, false
+	}
+	return name, fields[1:], true
+}
+
+// messageContent returns the type of content carried by the message.
+func messageContent(msg *Message) ContentType {
+	switch {
+	case msg.Photo != nil:
+		return PhotoContent
+	case msg.Video != nil:
+		return VideoContent
+	case msg.Animation != nil:
+		return AnimationContent
+	case msg.Audio != nil:
+		return AudioContent
+	case msg.Voice != nil:
+		return VoiceContent
+	case msg.VideoNote != nil:
+		return VideoNoteContent
+	case msg.Document != nil:
+		return DocumentContent
+	case msg.Sticker != nil:
+		return StickerContent
+	case msg.Venue != nil:
+		return VenueContent
+	case msg.Location != nil:
+		return LocationContent
+	case msg.Contact != nil:
+		return ContactContent
+	case msg.Poll != nil:
+		return PollContent
+	case msg.Dice != nil:
+		return DiceContent
+	case msg.Text != "":
+		return TextContent
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,101 @@
+package echotron
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newTestRouter(got *string, gotArgs *[]string) *Router {
+	route := func(name string) RouteFunc {
+		return func(_ *Update, args []string) {
+			*got, *gotArgs = name, args
+		}
+	}
+
+	r := NewRouter("@EchotronBot")
+	r.Command("start", "Start the bot", route("start"))
+	r.Command("hidden", "", route("hidden"))
+	r.Callback("buy:", route("buy"))
+	r.CallbackRegexp(`^page:(\d+)$`, route("page"))
+	r.Text(`(?i)^hello (\w+)$`, route("hello"))
+	r.Content(PhotoContent, route("photo"))
+	r.Content(LocationContent, route("location"))
+	return r
+}
+
+func TestRouter(t *testing.T) {
+	var (
+		got     string
+		gotArgs []string
+		r       = newTestRouter(&got, &gotArgs)
+		text    = func(s string) *Update { return &Update{Message: &Message{Text: s}} }
+		data    = func(s string) *Update { return &Update{CallbackQuery: &CallbackQuery{Data: s}} }
+	)
+
+	tests := []struct {
+		update *Update
+		route  string
+		args   []string
+	}{
+		{text("/start"), "start", []string{}},
+		{text("/start@echotronbot a b"), "start", []string{"a", "b"}},
+		{text("/start@OtherBot"), "", nil},
+		{text("/hidden"), "hidden", []string{}},
+		{text("Hello world"), "hello", []string{"world"}},
+		{text("/unknown"), "", nil},
+		{data("buy:42"), "buy", []string{"42"}},
+		{data("page:3"), "page", []string{"3"}},
+		{data("page:x"), "", nil},
+		{&Update{Message: &Message{Photo: []*PhotoSize{{}}}}, "photo", nil},
+		{&Update{ChannelPost: &Message{Location: &Location{}}}, "location", nil},
+		{&Update{Message: &Message{Venue: &Venue{}, Location: &Location{}}}, "", nil},
+	}
+
+	for i, tt := range tests {
+		got, gotArgs = "", nil
+		if matched := r.Route(tt.update); matched != (tt.route != "") {
+			t.Fatalf("test #%d: unexpected match result %t", i, matched)
+		}
+		if got != tt.route || !reflect.DeepEqual(gotArgs, tt.args) {
+			t.Fatalf("test #%d: expected %s %v, got %s %v", i, tt.route, tt.args, got, gotArgs)
+		}
+	}
+
+	r.Default(func(_ *Update, _ []string) { got = "default" })
+	if r.Route(text("/unknown")); got != "default" {
+		t.Fatalf("expected default route, got %q", got)
+	}
+}
+
+func TestRouterCommands(t *testing.T) {
+	var (
+		got     string
+		gotArgs []string
+		r       = newTestRouter(&got, &gotArgs)
+	)
+
+	expected := []BotCommand{{Command: "start", Description: "Start the bot"}}
+	if cmds := r.Commands(); !reflect.DeepEqual(cmds, expected) {
+		t.Fatalf("expected %v, got %v", expected, cmds)
+	}
+}
+
+func TestRouterWrap(t *testing.T) {
+	var (
+		got     string
+		gotArgs []string
+		r       = newTestRouter(&got, &gotArgs)
+		ch      = make(chan *Update, 1)
+		b       = r.Wrap(recorderBot{ch})
+	)
+
+	b.Update(&Update{Message: &Message{Text: "/start"}})
+	if got != "start" || len(ch) != 0 {
+		t.Fatal("matched update should not reach the wrapped bot")
+	}
+
+	b.Update(&Update{Message: &Message{Text: "something else"}})
+	if len(ch) != 1 {
+		t.Fatal("unmatched update should reach the wrapped bot")
+	}
+}
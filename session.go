@@ -0,0 +1,126 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package echotron
+
+import "context"
+
+// Session represents a session of the Dispatcher and allows to wait for its next updates
+// from within the Update method of its Bot.
+type Session struct {
+	dsp *Dispatcher
+	key int64
+}
+
+type waiter struct {
+	match func(*Update) bool
+	ch    chan *Update
+}
+
+// Session returns the Session with the given key, which is the chat ID its updates come from.
+func (d *Dispatcher) Session(sessionKey int64) *Session {
+	return &Session{dsp: d, key: sessionKey}
+}
+
+// Key returns the key of the session.
+func (s *Session) Key() int64 {
+	return s.key
+}
+
+// Wait blocks until the session receives an update for which match returns true, or until ctx is done.
+// The matching update is returned instead of being passed to the Update method of the Bot.
+// If match is nil, the next update of the session is returned.
+func (s *Session) Wait(ctx context.Context, match func(*Update) bool) (*Update, error) {
+	w := &waiter{match: match, ch: make(chan *Update, 1)}
+
+	s.dsp.mu.Lock()
+	s.dsp.waiters[s.key] = append(s.dsp.waiters[s.key], w)
+	s.dsp.mu.Unlock()
+
+	select {
+	case u := <-w.ch:
+		return u, nil
+
+	case <-ctx.Done():
+		if !s.dsp.unwait(s.key, w) {
+			// The update has been delivered while ctx was being cancelled.
+			return <-w.ch, nil
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// Ask is a wrapper function for AskOptions.
+func (s *Session) Ask(ctx context.Context, text string) (*Update, error) {
+	return s.AskOptions(ctx, text, nil)
+}
+
+// AskOptions sends a message with the given text to the chat of the session and waits for the reply,
+// which is the next message or callback query the session receives.
+// The message is sent with the API bound to ctx.
+func (s *Session) AskOptions(ctx context.Context, text string, opts *MessageOptions) (*Update, error) {
+	if _, err := s.dsp.getAPI().WithContext(ctx).SendMessage(text, s.key, opts); err != nil {
+		return nil, err
+	}
+
+	return s.Wait(ctx, func(u *Update) bool {
+		return u.Message != nil || u.CallbackQuery != nil
+	})
+}
+
+// wake passes the update to the first goroutine of the session waiting for a matching update
+// and reports whether there was one.
+// The match functions are called without holding d.mu, since they're user code.
+func (d *Dispatcher) wake(sessionKey int64, update *Update) bool {
+	d.mu.Lock()
+	waiters := append([]*waiter(nil), d.waiters[sessionKey]...)
+	d.mu.Unlock()
+
+	for _, w := range waiters {
+		// The waiter might have given up in the meantime, in which case unwait fails.
+		if (w.match == nil || w.match(update)) && d.unwait(sessionKey, w) {
+			w.ch <- update
+			return true
+		}
+	}
+	return false
+}
+
+// unwait removes the waiter from the session and reports whether it was still waiting.
+func (d *Dispatcher) unwait(sessionKey int64, w *waiter) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for i, v := range d.waiters[sessionKey] {
+		if v == w {
+			d.removeWaiter(sessionKey, i)
+			return true
+		}
+	}
+	return false
+}
+
+// removeWaiter removes the i-th waiter of the session, d.mu must be held.
+func (d *Dispatcher) removeWaiter(sessionKey int64, i int) {
+	ws := d.waiters[sessionKey]
+	if len(ws) == 1 {
+		delete(d.waiters, sessionKey)
+		return
+	}
+	d.waiters[sessionKey] = append(ws[:i:i], ws[i+1:]...)
+}
@@ -0,0 +1,131 @@
+package echotron
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSessionWait(t *testing.T) {
+	var (
+		d, ch  = newRecorderDispatcher()
+		s      = d.Session(7)
+		answer = make(chan *Update, 1)
+	)
+
+	go func() {
+		u, err := s.Wait(context.Background(), func(u *Update) bool {
+			return u.CallbackQuery != nil
+		})
+		if err != nil {
+			t.Error(err)
+		}
+		answer <- u
+	}()
+
+	for {
+		d.mu.Lock()
+		n := len(d.waiters[7])
+		d.mu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	d.handle(&Update{Message: &Message{Chat: Chat{ID: 7}}})
+	if u := <-ch; u.Message == nil {
+		t.Fatal("non matching update should reach the bot")
+	}
+
+	d.handle(&Update{CallbackQuery: &CallbackQuery{Message: &Message{Chat: Chat{ID: 7}}, Data: "yes"}})
+	if u := <-answer; u.CallbackQuery.Data != "yes" {
+		t.Fatalf("unexpected answer %+v", u)
+	}
+	if len(ch) != 0 {
+		t.Fatal("matching update should not reach the bot")
+	}
+}
+
+func TestSessionWaitTimeout(t *testing.T) {
+	d, _ := newRecorderDispatcher()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := d.Session(7).Wait(ctx, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if len(d.waiters) != 0 {
+		t.Fatal("waiter has not been removed")
+	}
+}
+
+func TestSessionWaitMatchReentrant(t *testing.T) {
+	var (
+		d, ch  = newRecorderDispatcher()
+		s      = d.Session(7)
+		answer = make(chan *Update, 1)
+	)
+
+	go func() {
+		u, _ := s.Wait(context.Background(), func(u *Update) bool {
+			// The match function may use the Dispatcher.
+			return d.sessionCount() >= 0 && u.CallbackQuery != nil
+		})
+		answer <- u
+	}()
+
+	for {
+		d.mu.Lock()
+		n := len(d.waiters[7])
+		d.mu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.handle(&Update{CallbackQuery: &CallbackQuery{Message: &Message{Chat: Chat{ID: 7}}, Data: "yes"}})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("deadlock calling the Dispatcher from the match function")
+	}
+	if u := <-answer; u.CallbackQuery.Data != "yes" || len(ch) != 0 {
+		t.Fatalf("unexpected answer %+v", u)
+	}
+}
+
+func TestSessionAskSetAPI(t *testing.T) {
+	var (
+		d, _ = newRecorderDispatcher()
+		a    = newTestServerAPI(t, nil, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"ok":true,"result":{"message_id":1,"date":0,"chat":{"id":7,"type":"private"}}}`))
+		})
+		done = make(chan struct{})
+	)
+	d.SetAPI(a)
+
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			d.SetAPI(a)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := d.Session(7).Ask(ctx, "question?"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	<-done
+}
@@ -1,11 +1,7 @@
 package echotron
 
 import (
-	"encoding/json"
-	"fmt"
-	"log"
-	"net/http"
-	"net/url"
+	"context"
 	"time"
 )
 
@@ -21,44 +17,13 @@ func PollingUpdatesOptions(token string, dropPendingUpdates bool, opts UpdateOpt
 	go func() {
 		defer close(updates)
 
-		var (
-			api        = NewAPI(token)
-			timeout    = opts.Timeout
-			isFirstRun = true
-		)
-
-		// deletes webhook if present to run in long polling mode
-		if _, err := api.DeleteWebhook(dropPendingUpdates); err != nil {
-			log.Println("echotron.PollingUpdates", err)
-		}
-
-		for {
-			if isFirstRun {
-				opts.Timeout = 0
-			}
-
-			response, err := api.GetUpdates(&opts)
-			if err != nil {
-				log.Println("echotron.PollingUpdates", err)
-				time.Sleep(5 * time.Second)
-				continue
-			}
-
-			if !dropPendingUpdates || !isFirstRun {
-				for _, u := range response.Result {
-					updates <- u
-				}
-			}
-
-			if l := len(response.Result); l > 0 {
-				opts.Offset = response.Result[l-1].ID + 1
-			}
-
-			if isFirstRun {
-				isFirstRun = false
-				opts.Timeout = timeout
-			}
+		src := &PollingSource{
+			API:                NewAPI(token),
+			Options:            opts,
+			RetryInterval:      5 * time.Second,
+			DropPendingUpdates: dropPendingUpdates,
 		}
+		src.Run(context.Background(), updates)
 	}()
 
 	return updates
@@ -75,44 +40,21 @@ func WebhookUpdates(url, token string) <-chan *Update {
 // WebhookUpdatesOptions will then proceed to communicate the webhook url '<hostname>/<path>'
 // to Telegram and run a webserver that listens to ':<port>' and handles the path.
 func WebhookUpdatesOptions(whURL, token string, dropPendingUpdates bool, opts *WebhookOptions) <-chan *Update {
-	u, err := url.Parse(whURL)
-	if err != nil {
-		panic(err)
+	src := &WebhookSource{
+		Options:            opts,
+		API:                NewAPI(token),
+		URL:                whURL,
+		RetryInterval:      5 * time.Second,
+		DropPendingUpdates: dropPendingUpdates,
 	}
-
-	wURL := u.Hostname() + u.EscapedPath()
-	api := NewAPI(token)
-	if _, err := api.SetWebhook(wURL, dropPendingUpdates, opts); err != nil {
+	if err := src.setWebhook(); err != nil {
 		panic(err)
 	}
 
 	var updates = make(chan *Update)
-	http.HandleFunc(u.EscapedPath(), func(w http.ResponseWriter, r *http.Request) {
-		var update Update
-
-		jsn, err := readRequest(r)
-		if err != nil {
-			log.Println("echotron.WebhookUpdates", err)
-			return
-		}
-
-		if err := json.Unmarshal(jsn, &update); err != nil {
-			log.Println("echotron.WebhookUpdates", err)
-			return
-		}
-
-		updates <- &update
-	})
-
 	go func() {
 		defer close(updates)
-		port := fmt.Sprintf(":%s", u.Port())
-		for {
-			if err := http.ListenAndServe(port, nil); err != nil {
-				log.Println("echotron.WebhookUpdates", err)
-				time.Sleep(5 * time.Second)
-			}
-		}
+		src.serve(context.Background(), updates)
 	}()
 
 	return updates
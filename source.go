@@ -0,0 +1,504 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package echotron
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// UpdateSource is the interface implemented by the sources of the updates consumed by a Dispatcher,
+// such as long polling and webhooks. Custom sources, eg: backed by a message queue, allow an ingress
+// process to receive the updates from Telegram and fan them out to worker processes.
+type UpdateSource interface {
+	// Run sends the updates to the channel until ctx is done, when it returns nil,
+	// or until it can't receive updates anymore, when it returns the reason.
+	Run(ctx context.Context, updates chan<- *Update) error
+}
+
+// Run consumes the updates of the source until ctx is done or the source stops,
+// passing each of them to the session it belongs to.
+func (d *Dispatcher) Run(ctx context.Context, src UpdateSource) error {
+	return src.Run(ctx, d.updates)
+}
+
+// PollingSource is the UpdateSource receiving the updates with long polling.
+type PollingSource struct {
+	// Logger is the Logger the errors are logged to when RetryInterval or Lease is set,
+	// if nil the one set with the SetLogger function is used.
+	Logger Logger
+	// API is the API object used to receive the updates.
+	API API
+	// Options are the options passed to GetUpdates.
+	Options UpdateOptions
+	// RetryInterval is how long to wait before retrying after an error.
+	// If 0 the first error stops the source.
+	RetryInterval time.Duration
+	// Lease, if set, makes the source poll only while holding it, so that when several replicas of
+	// the bot are running only one of them polls, and the others stand by until the lease is released
+	// or expires. A conflict with another getUpdates request puts the source back on standby.
+	Lease Lease
+	// LeaseInterval is how often the lease is acquired or renewed, it must be shorter than
+	// the time the lease takes to expire. 10s if 0.
+	LeaseInterval time.Duration
+	// DropPendingUpdates drops the updates received before the source started.
+	DropPendingUpdates bool
+}
+
+// Run implements the UpdateSource interface.
+// Once ctx is done, the updates still pending on Telegram's side are fetched
+// and sent so that their offset gets confirmed before returning.
+// A conflict with another getUpdates request or a webhook is reported with an error
+// for which IsConflict returns true.
+func (p *PollingSource) Run(ctx context.Context, updates chan<- *Update) error {
+	if p.Lease != nil {
+		return p.lead(ctx, updates)
+	}
+	return p.poll(ctx, updates, p.DropPendingUpdates, nil)
+}
+
+// poll receives the updates until ctx is done, then fetches the pending ones unless lost is closed,
+// which means that another replica took over.
+func (p *PollingSource) poll(ctx context.Context, updates chan<- *Update, drop bool, lost <-chan struct{}) error {
+	var (
+		api        = p.API.WithContext(ctx)
+		opts       = p.Options
+		timeout    = opts.Timeout
+		isFirstRun = true
+		isStopping = false
+	)
+
+	// deletes webhook if present to run in long polling mode
+	for {
+		_, err := api.DeleteWebhook(drop)
+		if err == nil {
+			break
+		}
+		if err := p.retry(ctx, "cannot delete webhook", err); err != nil {
+			return stopped(ctx, err)
+		}
+	}
+
+	for {
+		if isFirstRun || isStopping {
+			opts.Timeout = 0
+		}
+
+		response, err := api.GetUpdates(&opts)
+		if err != nil {
+			if isStopping {
+				return nil
+			}
+			if ctx.Err() != nil {
+				if isClosed(lost) {
+					return nil
+				}
+				isStopping = true
+				api = p.API
+				continue
+			}
+			if IsConflict(err) {
+				err = fmt.Errorf("echotron: another getUpdates request or a webhook is active for the bot: %w", err)
+				if p.Lease != nil {
+					return err
+				}
+			}
+			if err := p.retry(ctx, "cannot get updates", err); err != nil {
+				return stopped(ctx, err)
+			}
+			continue
+		}
+
+		if !drop || !isFirstRun {
+			for _, u := range response.Result {
+				updates <- u
+			}
+		}
+
+		l := len(response.Result)
+		if l > 0 {
+			opts.Offset = response.Result[l-1].ID + 1
+		}
+
+		if isStopping && l == 0 {
+			return nil
+		}
+
+		if isFirstRun {
+			isFirstRun = false
+			opts.Timeout = timeout
+		}
+
+		select {
+		case <-ctx.Done():
+			if isClosed(lost) {
+				return nil
+			}
+			isStopping = true
+			api = p.API
+		default:
+		}
+	}
+}
+
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// lead polls while holding the lease and stands by while another replica holds it.
+func (p *PollingSource) lead(ctx context.Context, updates chan<- *Update) error {
+	interval := p.LeaseInterval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	for drop := p.DropPendingUpdates; ; drop = false {
+		if err := p.acquire(ctx, interval); err != nil {
+			return nil
+		}
+
+		var (
+			pollCtx, cancel = context.WithCancel(ctx)
+			lost            = make(chan struct{})
+			done            = make(chan struct{})
+		)
+
+		go func() {
+			defer close(done)
+			p.renew(pollCtx, cancel, lost, interval)
+		}()
+
+		err := p.poll(pollCtx, updates, drop, lost)
+		cancel()
+		<-done
+		p.Lease.Release(context.Background())
+
+		switch {
+		case ctx.Err() != nil:
+			return nil
+
+		case IsConflict(err):
+			p.log().Log(LogError, "echotron.PollingSource: conflict with another getUpdates request, standing by", "error", err)
+			if sleepContext(ctx, interval) != nil {
+				return nil
+			}
+
+		case err != nil:
+			return err
+		}
+	}
+}
+
+// acquire waits until the lease is acquired or ctx is done.
+func (p *PollingSource) acquire(ctx context.Context, interval time.Duration) error {
+	for {
+		ok, err := p.Lease.TryAcquire(ctx)
+		if err != nil && ctx.Err() == nil {
+			p.log().Log(LogError, "echotron.PollingSource: cannot acquire lease", "error", err)
+		}
+		if ok {
+			return nil
+		}
+		if err := sleepContext(ctx, interval); err != nil {
+			return err
+		}
+	}
+}
+
+// renew renews the lease until ctx is done, and closes lost and calls cancel if it's lost.
+func (p *PollingSource) renew(ctx context.Context, cancel context.CancelFunc, lost chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			ok, err := p.Lease.TryAcquire(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			if !ok {
+				p.log().Log(LogError, "echotron.PollingSource: lease lost, standing by", "error", err)
+				close(lost)
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+func (p *PollingSource) log() Logger {
+	if p.Logger != nil {
+		return p.Logger
+	}
+	return getLogger()
+}
+
+// retry logs the error and waits for RetryInterval, or returns the error if retrying is disabled.
+func (p *PollingSource) retry(ctx context.Context, msg string, err error) error {
+	if p.RetryInterval <= 0 {
+		return err
+	}
+
+	p.log().Log(LogError, "echotron.PollingSource: "+msg, "error", err)
+
+	return sleepContext(ctx, p.RetryInterval)
+}
+
+// stopped returns nil if the error is due to ctx being done, and err otherwise.
+func stopped(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// WebhookSource is the UpdateSource receiving the updates with a webhook.
+type WebhookSource struct {
+	// Logger is the Logger the errors are logged to, if nil the one set with the SetLogger function is used.
+	Logger Logger
+	// Options are the options passed to SetWebhook.
+	Options *WebhookOptions
+	// Server is the http.Server the webhook is served by. If nil, a server listening on the port
+	// of the URL is used, which also serves the handlers registered in http.DefaultServeMux.
+	// The handler of the server keeps serving the other paths, unless the webhook is at the root path.
+	// The server is shut down when the source stops.
+	Server *http.Server
+	// API is the API object used to set the webhook.
+	API API
+	// URL is the URL of the webhook in the following format: '<hostname>:<port>/<path>',
+	// eg: 'https://example.com:443/bot_token'.
+	// The webhook url '<hostname>/<path>' is communicated to Telegram and the path is served on ':<port>'.
+	URL string
+	// RetryInterval is how long to wait before restarting the server after an error.
+	// If 0 the first error stops the source.
+	RetryInterval time.Duration
+	// DropPendingUpdates drops the updates received before the source started.
+	DropPendingUpdates bool
+}
+
+// webhookAddress splits the URL of a webhook in the URL communicated to Telegram, the path and the port.
+func webhookAddress(webhookURL string) (whURL, path, port string, err error) {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return "", "", "", err
+	}
+	return u.Hostname() + u.EscapedPath(), u.EscapedPath(), u.Port(), nil
+}
+
+// Run implements the UpdateSource interface.
+func (w *WebhookSource) Run(ctx context.Context, updates chan<- *Update) error {
+	if err := w.setWebhook(); err != nil {
+		return err
+	}
+	return w.serve(ctx, updates)
+}
+
+func (w *WebhookSource) setWebhook() error {
+	whURL, _, _, err := webhookAddress(w.URL)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.API.SetWebhook(whURL, w.DropPendingUpdates, w.Options)
+	return err
+}
+
+func (w *WebhookSource) log() Logger {
+	if w.Logger != nil {
+		return w.Logger
+	}
+	return getLogger()
+}
+
+func (w *WebhookSource) serve(ctx context.Context, updates chan<- *Update) error {
+	_, path, port, err := webhookAddress(w.URL)
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		path = "/"
+	}
+
+	srv := w.Server
+	if srv == nil {
+		srv = &http.Server{Addr: fmt.Sprintf(":%s", port), Handler: http.DefaultServeMux}
+	}
+
+	var (
+		mux  = http.NewServeMux()
+		done = make(chan struct{})
+	)
+
+	// The webhook served at the root path replaces the handler of the server.
+	if srv.Handler != nil && path != "/" {
+		mux.Handle("/", srv.Handler)
+	}
+	mux.HandleFunc(path, func(rw http.ResponseWriter, r *http.Request) {
+		update, err := decodeUpdate(r)
+		if err != nil {
+			w.log().Log(LogError, "echotron.WebhookSource: invalid update", "error", err)
+			return
+		}
+
+		select {
+		case updates <- update:
+		case <-ctx.Done():
+		}
+	})
+	srv.Handler = mux
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			srv.Shutdown(context.Background())
+		case <-done:
+		}
+	}()
+	defer close(done)
+
+	for {
+		err := srv.ListenAndServe()
+		if errors.Is(err, http.ErrServerClosed) || ctx.Err() != nil {
+			return nil
+		}
+		if w.RetryInterval <= 0 {
+			return err
+		}
+
+		w.log().Log(LogError, "echotron.WebhookSource: webhook server stopped", "error", err)
+		if sleepContext(ctx, w.RetryInterval) != nil {
+			return nil
+		}
+	}
+}
+
+// decodeUpdate reads and decodes the update sent by Telegram to a webhook.
+func decodeUpdate(r *http.Request) (*Update, error) {
+	var update Update
+
+	jsn, err := readRequest(r)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read request: %w", err)
+	}
+
+	if err := json.Unmarshal(jsn, &update); err != nil {
+		return nil, fmt.Errorf("cannot decode update: %w", err)
+	}
+	return &update, nil
+}
+
+// ChanSource is the UpdateSource forwarding the updates received from a channel, until it's closed.
+type ChanSource <-chan *Update
+
+// Run implements the UpdateSource interface.
+func (c ChanSource) Run(ctx context.Context, updates chan<- *Update) error {
+	for {
+		select {
+		case u, ok := <-c:
+			if !ok {
+				return nil
+			}
+			select {
+			case updates <- u:
+			case <-ctx.Done():
+				return nil
+			}
+
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// FileSource is the UpdateSource replaying the updates recorded by an UpdateRecorder in a file.
+type FileSource struct {
+	// Path is the path of the file.
+	Path string
+	// Speed is the factor the original timing of the updates is accelerated by, eg: 2 replays them
+	// twice as fast. If 0 the updates are replayed one after the other without waiting.
+	Speed float64
+}
+
+// Run implements the UpdateSource interface.
+func (f FileSource) Run(ctx context.Context, updates chan<- *Update) error {
+	return stopped(ctx, f.each(ctx, func(u *Update) {
+		select {
+		case updates <- u:
+		case <-ctx.Done():
+		}
+	}))
+}
+
+// each calls fn with each update of the file, respecting their timing.
+func (f FileSource) each(ctx context.Context, fn func(*Update)) error {
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var (
+		last    time.Time
+		scanner = bufio.NewScanner(file)
+	)
+
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var (
+			rec    RecordedUpdate
+			update Update
+		)
+
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return fmt.Errorf("echotron: %s:%d: %w", f.Path, line, err)
+		}
+		if err := json.Unmarshal(rec.Update, &update); err != nil {
+			return fmt.Errorf("echotron: %s:%d: %w", f.Path, line, err)
+		}
+
+		if f.Speed > 0 && !last.IsZero() {
+			if err := sleepContext(ctx, time.Duration(float64(rec.Time.Sub(last))/f.Speed)); err != nil {
+				return err
+			}
+		}
+		last = rec.Time
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		fn(&update)
+	}
+	return scanner.Err()
+}
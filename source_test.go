@@ -0,0 +1,180 @@
+package echotron
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestChanSource(t *testing.T) {
+	var (
+		got = make(chan string, 2)
+		src = make(chan *Update, 2)
+		d   = NewDispatcher("token", func(chatID int64) Bot {
+			return botFunc(func(u *Update) { got <- u.Message.Text })
+		})
+	)
+
+	src <- &Update{ID: 1, Message: &Message{Chat: Chat{ID: 42}, Text: "first"}}
+	src <- &Update{ID: 2, Message: &Message{Chat: Chat{ID: 43}, Text: "second"}}
+	close(src)
+
+	if err := d.Run(context.Background(), ChanSource(src)); err != nil {
+		t.Fatal(err)
+	}
+
+	texts := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		select {
+		case txt := <-got:
+			texts[txt] = true
+		case <-time.After(time.Second):
+			t.Fatal("update not dispatched")
+		}
+	}
+	if !texts["first"] || !texts["second"] {
+		t.Fatalf("unexpected updates %v", texts)
+	}
+}
+
+func TestPollingSource(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		offsets []string
+		api     = newTestServerAPI(t, nil, func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/deleteWebhook") {
+				w.Write([]byte(`{"ok":true,"result":true}`))
+				return
+			}
+
+			offset := r.URL.Query().Get("offset")
+			mu.Lock()
+			offsets = append(offsets, offset)
+			mu.Unlock()
+
+			if offset == "0" || offset == "" {
+				w.Write([]byte(`{"ok":true,"result":[{"update_id":7,"message":{"message_id":1,"chat":{"id":42},"text":"hi"}}]}`))
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+			w.Write([]byte(`{"ok":true,"result":[]}`))
+		})
+		updates     = make(chan *Update)
+		ctx, cancel = context.WithCancel(context.Background())
+		errc        = make(chan error, 1)
+	)
+
+	go func() {
+		errc <- (&PollingSource{API: api, Options: UpdateOptions{Timeout: 1}}).Run(ctx, updates)
+	}()
+
+	select {
+	case u := <-updates:
+		if u.ID != 7 || u.Message.Text != "hi" {
+			t.Fatalf("unexpected update %+v", u)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no update received")
+	}
+
+	cancel()
+	select {
+	case err := <-errc:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the source didn't stop")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if last := offsets[len(offsets)-1]; last != "8" {
+		t.Fatalf("expected the last offset to be 8, got %q", last)
+	}
+}
+
+func TestPollingSourceError(t *testing.T) {
+	api := newTestServerAPI(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":false,"error_code":401,"description":"Unauthorized"}`))
+	})
+
+	if err := (&PollingSource{API: api}).Run(context.Background(), make(chan *Update)); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestPollingSourceConflict(t *testing.T) {
+	api := newTestServerAPI(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/deleteWebhook") {
+			w.Write([]byte(`{"ok":true,"result":true}`))
+			return
+		}
+		w.Write([]byte(`{"ok":false,"error_code":409,"description":"Conflict: terminated by other getUpdates request"}`))
+	})
+
+	err := (&PollingSource{API: api}).Run(context.Background(), make(chan *Update))
+	if !IsConflict(err) {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+}
+
+func TestWebhookSourceRootPath(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	var (
+		updates     = make(chan *Update, 1)
+		ctx, cancel = context.WithCancel(context.Background())
+		errc        = make(chan error, 1)
+		src         = &WebhookSource{
+			API: newTestServerAPI(t, nil, func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"ok":true,"result":true}`))
+			}),
+			URL: fmt.Sprintf("https://localhost:%d/", port),
+		}
+	)
+	defer cancel()
+
+	go func() { errc <- src.Run(ctx, updates) }()
+
+	body := `{"update_id":1,"message":{"message_id":1,"chat":{"id":42,"type":"private"},"date":0,"text":"hi"}}`
+	for i := 0; ; i++ {
+		res, err := http.Post(fmt.Sprintf("http://127.0.0.1:%d/", port), "application/json", strings.NewReader(body))
+		if err == nil {
+			res.Body.Close()
+			break
+		}
+		if i == 100 {
+			t.Fatal(err)
+		}
+		select {
+		case err := <-errc:
+			t.Fatal(err)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	select {
+	case u := <-updates:
+		if u.Message.Text != "hi" {
+			t.Fatalf("unexpected update %+v", u)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("update not received")
+	}
+
+	cancel()
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+}
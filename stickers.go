@@ -20,7 +20,6 @@ package echotron
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/url"
 )
 
@@ -118,7 +117,7 @@ func (a API) SendSticker(stickerID string, chatID int64, opts *StickerOptions) (
 
 	vals.Set("sticker", stickerID)
 	vals.Set("chat_id", itoa(chatID))
-	return get[APIResponseMessage](a.base, "sendSticker", addValues(vals, opts))
+	return get[APIResponseMessage](a, "sendSticker", addValues(vals, opts))
 }
 
 // GetStickerSet is used to get a sticker set.
@@ -126,30 +125,16 @@ func (a API) GetStickerSet(name string) (res APIResponseStickerSet, err error) {
 	var vals = make(url.Values)
 
 	vals.Set("name", name)
-	return get[APIResponseStickerSet](a.base, "getStickerSet", vals)
+	return get[APIResponseStickerSet](a, "getStickerSet", vals)
 }
 
 // GetCustomEmojiStickers is used to get information about custom emoji stickers by their identifiers.
 func (a API) GetCustomEmojiStickers(customEmojiIDs ...string) (res APIResponseStickers, err error) {
-	jsn, _ := json.Marshal(customEmojiIDs)
-
-	var url = fmt.Sprintf(
-		"%sgetCustomEmojiStickers?custom_emoji_ids=%s",
-		a.base,
-		jsn,
-	)
-
-	cnt, err := sendGetRequest(url)
-	if err != nil {
-		return
-	}
-
-	if err = json.Unmarshal(cnt, &res); err != nil {
-		return
-	}
+	var vals = make(url.Values)
 
-	err = check(res)
-	return
+	jsn, _ := json.Marshal(customEmojiIDs)
+	vals.Set("custom_emoji_ids", string(jsn))
+	return get[APIResponseStickers](a, "getCustomEmojiStickers", vals)
 }
 
 // UploadStickerFile is used to upload a .PNG file with a sticker for later use in
@@ -159,7 +144,7 @@ func (a API) UploadStickerFile(userID int64, sticker InputFile, format StickerFo
 
 	vals.Set("user_id", itoa(userID))
 	vals.Set("sticker_format", string(format))
-	return postFile[APIResponseFile](a.base, "uploadStickerFile", "sticker", sticker, InputFile{}, vals)
+	return postFile[APIResponseFile](a, "uploadStickerFile", "sticker", sticker, InputFile{}, vals)
 }
 
 // CreateNewStickerSet is used to create a new sticker set owned by a user.
@@ -170,7 +155,7 @@ func (a API) CreateNewStickerSet(userID int64, name, title string, stickers []In
 	vals.Set("name", name)
 	vals.Set("title", title)
 	vals.Set("sticker_format", string(format))
-	return postStickers[APIResponseBool](a.base, "createNewStickerSet", addValues(vals, opts), stickers...)
+	return postStickers[APIResponseBool](a, "createNewStickerSet", addValues(vals, opts), stickers...)
 }
 
 // AddStickerToSet is used to add a new sticker to a set created by the bot.
@@ -179,7 +164,7 @@ func (a API) AddStickerToSet(userID int64, name string, sticker InputSticker) (r
 
 	vals.Set("user_id", itoa(userID))
 	vals.Set("name", name)
-	return postStickers[APIResponseBool](a.base, "addStickerToSet", vals, sticker)
+	return postStickers[APIResponseBool](a, "addStickerToSet", vals, sticker)
 }
 
 // SetStickerPositionInSet is used to move a sticker in a set created by the bot to a specific position.
@@ -188,7 +173,7 @@ func (a API) SetStickerPositionInSet(sticker string, position int) (res APIRespo
 
 	vals.Set("sticker", sticker)
 	vals.Set("position", itoa(int64(position)))
-	return get[APIResponseBase](a.base, "setStickerPositionInSet", vals)
+	return get[APIResponseBase](a, "setStickerPositionInSet", vals)
 }
 
 // DeleteStickerFromSet is used to delete a sticker from a set created by the bot.
@@ -196,7 +181,7 @@ func (a API) DeleteStickerFromSet(sticker string) (res APIResponseBase, err erro
 	var vals = make(url.Values)
 
 	vals.Set("sticker", sticker)
-	return get[APIResponseBase](a.base, "deleteStickerFromSet", vals)
+	return get[APIResponseBase](a, "deleteStickerFromSet", vals)
 }
 
 // SetStickerEmojiList is used to change the list of emoji assigned to a regular or custom emoji sticker.
@@ -208,7 +193,7 @@ func (a API) SetStickerEmojiList(sticker string, emojis []string) (res APIRespon
 
 	vals.Set("sticker", sticker)
 	vals.Set("emoji_list", string(jsn))
-	return get[APIResponseBool](a.base, "setStickerEmojiList", vals)
+	return get[APIResponseBool](a, "setStickerEmojiList", vals)
 }
 
 // SetStickerKeywords is used to change search keywords assigned to a regular or custom emoji sticker.
@@ -220,7 +205,7 @@ func (a API) SetStickerKeywords(sticker string, keywords []string) (res APIRespo
 
 	vals.Set("sticker", sticker)
 	vals.Set("keywords", string(jsn))
-	return get[APIResponseBool](a.base, "setStickerKeywords", vals)
+	return get[APIResponseBool](a, "setStickerKeywords", vals)
 }
 
 // SetStickerMaskPosition is used to change the mask position of a mask sticker.
@@ -232,7 +217,7 @@ func (a API) SetStickerMaskPosition(sticker string, mask MaskPosition) (res APIR
 
 	vals.Set("sticker", sticker)
 	vals.Set("mask_position", string(jsn))
-	return get[APIResponseBool](a.base, "setStickerMaskPosition", vals)
+	return get[APIResponseBool](a, "setStickerMaskPosition", vals)
 }
 
 // SetStickerSetTitle is used to set the title of a created sticker set.
@@ -241,7 +226,7 @@ func (a API) SetStickerSetTitle(name, title string) (res APIResponseBool, err er
 
 	vals.Set("name", name)
 	vals.Set("title", title)
-	return get[APIResponseBool](a.base, "setStickerSetTitle", vals)
+	return get[APIResponseBool](a, "setStickerSetTitle", vals)
 }
 
 // SetStickerSetThumbnail is used to set the thumbnail of a sticker set.
@@ -250,7 +235,7 @@ func (a API) SetStickerSetThumbnail(name string, userID int64, thumbnail InputFi
 
 	vals.Set("name", name)
 	vals.Set("user_id", itoa(userID))
-	return postFile[APIResponseBase](a.base, "setStickerSetThumbnail", "thumbnail", thumbnail, InputFile{}, vals)
+	return postFile[APIResponseBase](a, "setStickerSetThumbnail", "thumbnail", thumbnail, InputFile{}, vals)
 }
 
 // SetCustomEmojiStickerSetThumbnail is used to set the thumbnail of a custom emoji sticker set.
@@ -259,7 +244,7 @@ func (a API) SetCustomEmojiStickerSetThumbnail(name, emojiID string) (res APIRes
 
 	vals.Set("name", name)
 	vals.Set("custom_emoji_id", emojiID)
-	return get[APIResponseBool](a.base, "setCustomEmojiStickerSetThumbnail", vals)
+	return get[APIResponseBool](a, "setCustomEmojiStickerSetThumbnail", vals)
 }
 
 // DeleteStickerSet is used to delete a sticker set that was created by the bot.
@@ -267,10 +252,10 @@ func (a API) DeleteStickerSet(name string) (res APIResponseBool, err error) {
 	var vals = make(url.Values)
 
 	vals.Set("name", name)
-	return get[APIResponseBool](a.base, "DeleteStickerSet", vals)
+	return get[APIResponseBool](a, "DeleteStickerSet", vals)
 }
 
 // GetForumTopicIconStickers is used to get custom emoji stickers, which can be used as a forum topic icon by any user.
 func (a API) GetForumTopicIconStickers() (res APIResponseStickers, err error) {
-	return get[APIResponseStickers](a.base, "getForumTopicIconStickers", nil)
+	return get[APIResponseStickers](a, "getForumTopicIconStickers", nil)
 }
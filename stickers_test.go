@@ -1,3 +1,5 @@
+//go:build apitest
+
 /*
  * Echotron
  * Copyright (C) 2018-2022 The Echotron Devs
@@ -45,6 +47,8 @@ func TestUploadStickerFile(t *testing.T) {
 }
 
 func TestCreateNewStickerSet(t *testing.T) {
+	requireResult(t, stickerFile, "stickerFile")
+
 	_, err := api.CreateNewStickerSet(
 		chatID,
 		stickerSetName,
@@ -107,9 +111,16 @@ func TestGetStickerSet(t *testing.T) {
 	}
 
 	stickerSet = resp.Result
+	requireResult(t, stickerSet, "sticker set")
+
+	if len(stickerSet.Stickers) < 2 {
+		t.Fatalf("expected 2 stickers in the set, got %d", len(stickerSet.Stickers))
+	}
 }
 
 func TestSetStickerPositionInSet(t *testing.T) {
+	requireResult(t, stickerSet, "stickerSet")
+
 	_, err := api.SetStickerPositionInSet(
 		stickerSet.Stickers[1].FileID,
 		0,
@@ -121,6 +132,8 @@ func TestSetStickerPositionInSet(t *testing.T) {
 }
 
 func TestSetStickerEmojiList(t *testing.T) {
+	requireResult(t, stickerSet, "stickerSet")
+
 	_, err := api.SetStickerEmojiList(
 		stickerSet.Stickers[0].FileID,
 		[]string{"🤖", "👾"},
@@ -132,6 +145,8 @@ func TestSetStickerEmojiList(t *testing.T) {
 }
 
 func TestSetStickerKeywords(t *testing.T) {
+	requireResult(t, stickerSet, "stickerSet")
+
 	_, err := api.SetStickerKeywords(
 		stickerSet.Stickers[0].FileID,
 		[]string{"echotron"},
@@ -154,6 +169,8 @@ func TestSetStickerSetTitle(t *testing.T) {
 }
 
 func TestDeleteStickerFromSet(t *testing.T) {
+	requireResult(t, stickerSet, "stickerSet")
+
 	_, err := api.DeleteStickerFromSet(
 		stickerSet.Stickers[0].FileID,
 	)
@@ -164,6 +181,8 @@ func TestDeleteStickerFromSet(t *testing.T) {
 }
 
 func TestSendSticker(t *testing.T) {
+	requireResult(t, stickerSet, "stickerSet")
+
 	_, err := api.SendSticker(
 		stickerSet.Stickers[0].FileID,
 		chatID,
@@ -0,0 +1,85 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package echotron
+
+import (
+	"context"
+	"time"
+)
+
+// UpdateMeta contains the metadata of the update being handled,
+// attached by the Dispatcher to the context of each update.
+type UpdateMeta struct {
+	// Start is the time the Dispatcher started handling the update at.
+	Start time.Time
+	// UpdateID is the ID of the update.
+	UpdateID int
+	// SessionKey is the key of the session the update belongs to.
+	SessionKey int64
+}
+
+type updateMetaKey struct{}
+
+// ContextWithUpdateMeta returns a copy of ctx carrying the given UpdateMeta.
+func ContextWithUpdateMeta(ctx context.Context, meta UpdateMeta) context.Context {
+	return context.WithValue(ctx, updateMetaKey{}, meta)
+}
+
+// UpdateMetaFromContext returns the UpdateMeta carried by ctx, if any.
+func UpdateMetaFromContext(ctx context.Context) (UpdateMeta, bool) {
+	if ctx == nil {
+		return UpdateMeta{}, false
+	}
+	meta, ok := ctx.Value(updateMetaKey{}).(UpdateMeta)
+	return meta, ok
+}
+
+// Span is a unit of work started by a Tracer, such as the handling of an update or an API call.
+type Span interface {
+	// SetAttribute sets an attribute of the span.
+	SetAttribute(key string, value any)
+	// End marks the end of the span, err is the error the work ended with, if any.
+	End(err error)
+}
+
+// Tracer is the interface used to export the spans of the updates and of the API calls
+// to a tracing backend.
+// The span of an update is named "echotron.update" and the one of an API call is named
+// after its method, eg: "echotron.api.sendMessage".
+type Tracer interface {
+	// Start starts a new span as a child of the one in ctx, if any,
+	// and returns a context carrying it.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// TracerFunc is an adapter to allow the use of ordinary functions as Tracers.
+type TracerFunc func(ctx context.Context, name string) (context.Context, Span)
+
+// Start calls f(ctx, name).
+func (f TracerFunc) Start(ctx context.Context, name string) (context.Context, Span) {
+	return f(ctx, name)
+}
+
+// metaKeyvals returns the update metadata carried by ctx as key-value pairs for the Logger.
+func metaKeyvals(ctx context.Context) []any {
+	if meta, ok := UpdateMetaFromContext(ctx); ok {
+		return []any{"update_id", meta.UpdateID, "session_key", meta.SessionKey}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package echotron
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+type testSpan struct {
+	attrs map[string]any
+	name  string
+	ended bool
+}
+
+func (s *testSpan) SetAttribute(key string, value any) {
+	s.attrs[key] = value
+}
+
+func (s *testSpan) End(_ error) {
+	s.ended = true
+}
+
+type testTracer struct {
+	spans []*testSpan
+	mu    sync.Mutex
+}
+
+func (t *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := &testSpan{name: name, attrs: make(map[string]any)}
+	t.spans = append(t.spans, s)
+	return ctx, s
+}
+
+func TestUpdateContext(t *testing.T) {
+	var (
+		tracer = &testTracer{}
+		calls  []*APICall
+		a      = newTestServerAPI(t, &APIOptions{
+			Tracer: tracer,
+			Interceptors: []Interceptor{{
+				After: func(c *APICall) { calls = append(calls, c) },
+			}},
+		}, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+		})
+		d, ch = newRecorderDispatcher()
+	)
+
+	d.SetTracer(tracer)
+	d.handle(&Update{ID: 10, Message: &Message{Chat: Chat{ID: 42}}})
+
+	u := <-ch
+	meta, ok := UpdateMetaFromContext(u.Context())
+	if !ok {
+		t.Fatal("missing update metadata")
+	}
+	if meta.UpdateID != 10 || meta.SessionKey != 42 || meta.Start.IsZero() {
+		t.Fatalf("unexpected metadata %+v", meta)
+	}
+
+	if _, err := a.WithContext(u.Context()).SendMessage("hi", 42, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if meta, _ := UpdateMetaFromContext(calls[0].Context); meta.UpdateID != 10 {
+		t.Fatalf("interceptor got update ID %d", meta.UpdateID)
+	}
+
+	if len(tracer.spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(tracer.spans))
+	}
+	if s := tracer.spans[0]; s.name != "echotron.update" || !s.ended {
+		t.Fatalf("unexpected update span %+v", s)
+	}
+	if s := tracer.spans[1]; s.name != "echotron.api.sendMessage" || s.attrs["update_id"] != 10 || !s.ended {
+		t.Fatalf("unexpected API span %+v", s)
+	}
+}
+
+func TestUpdateContextDefault(t *testing.T) {
+	if _, ok := UpdateMetaFromContext((&Update{}).Context()); ok {
+		t.Fatal("update not handled by a Dispatcher should carry no metadata")
+	}
+}
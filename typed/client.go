@@ -0,0 +1,79 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package typed provides a client for the Telegram Bot API whose methods return the
+// results of the calls directly, eg: (*echotron.Message, error) rather than an
+// echotron.APIResponseMessage, so that the callers only need to check the error.
+//
+// It wraps an echotron.BotAPI, so it works with the echotron.API as well as with the
+// echotrontest.Mock, and the errors returned by the Telegram API are *echotron.APIError
+// values carrying the description, the error code and the parameters of the response.
+package typed
+
+//go:generate go run ./internal/clientgen -dir .. -o client_gen.go
+
+import (
+	"encoding/json"
+
+	"github.com/NicoNex/echotron/v3"
+)
+
+// Client wraps an echotron.BotAPI and returns the results of its methods directly.
+type Client struct {
+	api echotron.BotAPI
+}
+
+// New returns a new Client that calls the methods of the given BotAPI.
+func New(api echotron.BotAPI) Client {
+	return Client{api: api}
+}
+
+// NewFromToken returns a new Client that calls the Telegram Bot API with the given token.
+func NewFromToken(token string) Client {
+	return New(echotron.NewAPI(token))
+}
+
+// API returns the BotAPI wrapped by the Client.
+func (c Client) API() echotron.BotAPI {
+	return c.api
+}
+
+// Do calls the given method of the Telegram Bot API with the Call method of the BotAPI
+// wrapped by the Client and decodes its result into a value of type T, eg:
+//
+//	boosts, err := typed.Do[*echotron.UserChatBoosts](client, "getUserChatBoosts", map[string]any{"chat_id": chatID, "user_id": userID}, nil)
+func Do[T any](c Client, method string, params any, files map[string]echotron.InputFile) (res T, err error) {
+	raw, err := c.api.Call(method, params, files)
+	if err != nil {
+		return res, err
+	}
+
+	if len(raw.Result) > 0 {
+		err = json.Unmarshal(raw.Result, &res)
+	}
+	return
+}
+
+// unwrap returns the result of a call, or the zero value of T if the call failed.
+func unwrap[T any](res T, err error) (T, error) {
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return res, nil
+}
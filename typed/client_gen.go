@@ -0,0 +1,730 @@
+// Code generated by clientgen; DO NOT EDIT.
+
+package typed
+
+import "github.com/NicoNex/echotron/v3"
+
+// GetUpdates calls the GetUpdates method of the BotAPI and returns its result.
+func (c Client) GetUpdates(opts *echotron.UpdateOptions) ([]*echotron.Update, error) {
+	res, err := c.api.GetUpdates(opts)
+	return unwrap(res.Result, err)
+}
+
+// SetWebhook calls the SetWebhook method of the BotAPI and returns its error.
+func (c Client) SetWebhook(webhookURL string, dropPendingUpdates bool, opts *echotron.WebhookOptions) error {
+	_, err := c.api.SetWebhook(webhookURL, dropPendingUpdates, opts)
+	return err
+}
+
+// DeleteWebhook calls the DeleteWebhook method of the BotAPI and returns its error.
+func (c Client) DeleteWebhook(dropPendingUpdates bool) error {
+	_, err := c.api.DeleteWebhook(dropPendingUpdates)
+	return err
+}
+
+// GetWebhookInfo calls the GetWebhookInfo method of the BotAPI and returns its result.
+func (c Client) GetWebhookInfo() (*echotron.WebhookInfo, error) {
+	res, err := c.api.GetWebhookInfo()
+	return unwrap(res.Result, err)
+}
+
+// GetMe calls the GetMe method of the BotAPI and returns its result.
+func (c Client) GetMe() (*echotron.User, error) {
+	res, err := c.api.GetMe()
+	return unwrap(res.Result, err)
+}
+
+// LogOut calls the LogOut method of the BotAPI and returns its result.
+func (c Client) LogOut() (bool, error) {
+	res, err := c.api.LogOut()
+	return unwrap(res.Result, err)
+}
+
+// Close calls the Close method of the BotAPI and returns its result.
+func (c Client) Close() (bool, error) {
+	res, err := c.api.Close()
+	return unwrap(res.Result, err)
+}
+
+// SetMyCommands calls the SetMyCommands method of the BotAPI and returns its result.
+func (c Client) SetMyCommands(opts *echotron.CommandOptions, commands ...echotron.BotCommand) (bool, error) {
+	res, err := c.api.SetMyCommands(opts, commands...)
+	return unwrap(res.Result, err)
+}
+
+// DeleteMyCommands calls the DeleteMyCommands method of the BotAPI and returns its result.
+func (c Client) DeleteMyCommands(opts *echotron.CommandOptions) (bool, error) {
+	res, err := c.api.DeleteMyCommands(opts)
+	return unwrap(res.Result, err)
+}
+
+// GetMyCommands calls the GetMyCommands method of the BotAPI and returns its result.
+func (c Client) GetMyCommands(opts *echotron.CommandOptions) ([]*echotron.BotCommand, error) {
+	res, err := c.api.GetMyCommands(opts)
+	return unwrap(res.Result, err)
+}
+
+// SetMyName calls the SetMyName method of the BotAPI and returns its result.
+func (c Client) SetMyName(name string, languageCode string) (bool, error) {
+	res, err := c.api.SetMyName(name, languageCode)
+	return unwrap(res.Result, err)
+}
+
+// GetMyName calls the GetMyName method of the BotAPI and returns its result.
+func (c Client) GetMyName(languageCode string) (*echotron.BotName, error) {
+	res, err := c.api.GetMyName(languageCode)
+	return unwrap(res.Result, err)
+}
+
+// SetMyDescription calls the SetMyDescription method of the BotAPI and returns its result.
+func (c Client) SetMyDescription(description string, languageCode string) (bool, error) {
+	res, err := c.api.SetMyDescription(description, languageCode)
+	return unwrap(res.Result, err)
+}
+
+// GetMyDescription calls the GetMyDescription method of the BotAPI and returns its result.
+func (c Client) GetMyDescription(languageCode string) (*echotron.BotDescription, error) {
+	res, err := c.api.GetMyDescription(languageCode)
+	return unwrap(res.Result, err)
+}
+
+// SetMyShortDescription calls the SetMyShortDescription method of the BotAPI and returns its result.
+func (c Client) SetMyShortDescription(shortDescription string, languageCode string) (bool, error) {
+	res, err := c.api.SetMyShortDescription(shortDescription, languageCode)
+	return unwrap(res.Result, err)
+}
+
+// GetMyShortDescription calls the GetMyShortDescription method of the BotAPI and returns its result.
+func (c Client) GetMyShortDescription(languageCode string) (*echotron.BotShortDescription, error) {
+	res, err := c.api.GetMyShortDescription(languageCode)
+	return unwrap(res.Result, err)
+}
+
+// SetMyDefaultAdministratorRights calls the SetMyDefaultAdministratorRights method of the BotAPI and returns its result.
+func (c Client) SetMyDefaultAdministratorRights(opts echotron.SetMyDefaultAdministratorRightsOptions) (bool, error) {
+	res, err := c.api.SetMyDefaultAdministratorRights(opts)
+	return unwrap(res.Result, err)
+}
+
+// GetMyDefaultAdministratorRights calls the GetMyDefaultAdministratorRights method of the BotAPI and returns its result.
+func (c Client) GetMyDefaultAdministratorRights(opts echotron.GetMyDefaultAdministratorRightsOptions) (*echotron.ChatAdministratorRights, error) {
+	res, err := c.api.GetMyDefaultAdministratorRights(opts)
+	return unwrap(res.Result, err)
+}
+
+// SetChatMenuButton calls the SetChatMenuButton method of the BotAPI and returns its result.
+func (c Client) SetChatMenuButton(opts echotron.SetChatMenuButtonOptions) (bool, error) {
+	res, err := c.api.SetChatMenuButton(opts)
+	return unwrap(res.Result, err)
+}
+
+// GetChatMenuButton calls the GetChatMenuButton method of the BotAPI and returns its result.
+func (c Client) GetChatMenuButton(opts echotron.GetChatMenuButtonOptions) (*echotron.MenuButton, error) {
+	res, err := c.api.GetChatMenuButton(opts)
+	return unwrap(res.Result, err)
+}
+
+// SendMessage calls the SendMessage method of the BotAPI and returns its result.
+func (c Client) SendMessage(text string, chatID int64, opts *echotron.MessageOptions) (*echotron.Message, error) {
+	res, err := c.api.SendMessage(text, chatID, opts)
+	return unwrap(res.Result, err)
+}
+
+// SendMessageWithUserName calls the SendMessageWithUserName method of the BotAPI and returns its result.
+func (c Client) SendMessageWithUserName(text string, userName string, opts *echotron.MessageOptions) (*echotron.Message, error) {
+	res, err := c.api.SendMessageWithUserName(text, userName, opts)
+	return unwrap(res.Result, err)
+}
+
+// ForwardMessage calls the ForwardMessage method of the BotAPI and returns its result.
+func (c Client) ForwardMessage(chatID int64, fromChatID int64, messageID int, opts *echotron.ForwardOptions) (*echotron.Message, error) {
+	res, err := c.api.ForwardMessage(chatID, fromChatID, messageID, opts)
+	return unwrap(res.Result, err)
+}
+
+// CopyMessage calls the CopyMessage method of the BotAPI and returns its result.
+func (c Client) CopyMessage(chatID int64, fromChatID int64, messageID int, opts *echotron.CopyOptions) (*echotron.MessageID, error) {
+	res, err := c.api.CopyMessage(chatID, fromChatID, messageID, opts)
+	return unwrap(res.Result, err)
+}
+
+// SendPhoto calls the SendPhoto method of the BotAPI and returns its result.
+func (c Client) SendPhoto(file echotron.InputFile, chatID int64, opts *echotron.PhotoOptions) (*echotron.Message, error) {
+	res, err := c.api.SendPhoto(file, chatID, opts)
+	return unwrap(res.Result, err)
+}
+
+// SendAudio calls the SendAudio method of the BotAPI and returns its result.
+func (c Client) SendAudio(file echotron.InputFile, chatID int64, opts *echotron.AudioOptions) (*echotron.Message, error) {
+	res, err := c.api.SendAudio(file, chatID, opts)
+	return unwrap(res.Result, err)
+}
+
+// SendDocument calls the SendDocument method of the BotAPI and returns its result.
+func (c Client) SendDocument(file echotron.InputFile, chatID int64, opts *echotron.DocumentOptions) (*echotron.Message, error) {
+	res, err := c.api.SendDocument(file, chatID, opts)
+	return unwrap(res.Result, err)
+}
+
+// SendVideo calls the SendVideo method of the BotAPI and returns its result.
+func (c Client) SendVideo(file echotron.InputFile, chatID int64, opts *echotron.VideoOptions) (*echotron.Message, error) {
+	res, err := c.api.SendVideo(file, chatID, opts)
+	return unwrap(res.Result, err)
+}
+
+// SendAnimation calls the SendAnimation method of the BotAPI and returns its result.
+func (c Client) SendAnimation(file echotron.InputFile, chatID int64, opts *echotron.AnimationOptions) (*echotron.Message, error) {
+	res, err := c.api.SendAnimation(file, chatID, opts)
+	return unwrap(res.Result, err)
+}
+
+// SendVoice calls the SendVoice method of the BotAPI and returns its result.
+func (c Client) SendVoice(file echotron.InputFile, chatID int64, opts *echotron.VoiceOptions) (*echotron.Message, error) {
+	res, err := c.api.SendVoice(file, chatID, opts)
+	return unwrap(res.Result, err)
+}
+
+// SendVideoNote calls the SendVideoNote method of the BotAPI and returns its result.
+func (c Client) SendVideoNote(file echotron.InputFile, chatID int64, opts *echotron.VideoNoteOptions) (*echotron.Message, error) {
+	res, err := c.api.SendVideoNote(file, chatID, opts)
+	return unwrap(res.Result, err)
+}
+
+// SendMediaGroup calls the SendMediaGroup method of the BotAPI and returns its result.
+func (c Client) SendMediaGroup(chatID int64, media []echotron.GroupableInputMedia, opts *echotron.MediaGroupOptions) ([]*echotron.Message, error) {
+	res, err := c.api.SendMediaGroup(chatID, media, opts)
+	return unwrap(res.Result, err)
+}
+
+// SendLocation calls the SendLocation method of the BotAPI and returns its result.
+func (c Client) SendLocation(chatID int64, latitude float64, longitude float64, opts *echotron.LocationOptions) (*echotron.Message, error) {
+	res, err := c.api.SendLocation(chatID, latitude, longitude, opts)
+	return unwrap(res.Result, err)
+}
+
+// EditMessageLiveLocation calls the EditMessageLiveLocation method of the BotAPI and returns its result.
+func (c Client) EditMessageLiveLocation(msg echotron.MessageIDOptions, latitude float64, longitude float64, opts *echotron.EditLocationOptions) (*echotron.Message, error) {
+	res, err := c.api.EditMessageLiveLocation(msg, latitude, longitude, opts)
+	return unwrap(res.Result, err)
+}
+
+// StopMessageLiveLocation calls the StopMessageLiveLocation method of the BotAPI and returns its result.
+func (c Client) StopMessageLiveLocation(msg echotron.MessageIDOptions, opts *echotron.MessageReplyMarkup) (*echotron.Message, error) {
+	res, err := c.api.StopMessageLiveLocation(msg, opts)
+	return unwrap(res.Result, err)
+}
+
+// SendVenue calls the SendVenue method of the BotAPI and returns its result.
+func (c Client) SendVenue(chatID int64, latitude float64, longitude float64, title string, address string, opts *echotron.VenueOptions) (*echotron.Message, error) {
+	res, err := c.api.SendVenue(chatID, latitude, longitude, title, address, opts)
+	return unwrap(res.Result, err)
+}
+
+// SendContact calls the SendContact method of the BotAPI and returns its result.
+func (c Client) SendContact(phoneNumber string, firstName string, chatID int64, opts *echotron.ContactOptions) (*echotron.Message, error) {
+	res, err := c.api.SendContact(phoneNumber, firstName, chatID, opts)
+	return unwrap(res.Result, err)
+}
+
+// SendPoll calls the SendPoll method of the BotAPI and returns its result.
+func (c Client) SendPoll(chatID int64, question string, options []string, opts *echotron.PollOptions) (*echotron.Message, error) {
+	res, err := c.api.SendPoll(chatID, question, options, opts)
+	return unwrap(res.Result, err)
+}
+
+// SendDice calls the SendDice method of the BotAPI and returns its result.
+func (c Client) SendDice(chatID int64, emoji echotron.DiceEmoji, opts *echotron.BaseOptions) (*echotron.Message, error) {
+	res, err := c.api.SendDice(chatID, emoji, opts)
+	return unwrap(res.Result, err)
+}
+
+// SendChatAction calls the SendChatAction method of the BotAPI and returns its result.
+func (c Client) SendChatAction(action echotron.ChatAction, chatID int64, opts *echotron.ChatActionOptions) (bool, error) {
+	res, err := c.api.SendChatAction(action, chatID, opts)
+	return unwrap(res.Result, err)
+}
+
+// EditMessageText calls the EditMessageText method of the BotAPI and returns its result.
+func (c Client) EditMessageText(text string, msg echotron.MessageIDOptions, opts *echotron.MessageTextOptions) (*echotron.Message, error) {
+	res, err := c.api.EditMessageText(text, msg, opts)
+	return unwrap(res.Result, err)
+}
+
+// EditMessageCaption calls the EditMessageCaption method of the BotAPI and returns its result.
+func (c Client) EditMessageCaption(msg echotron.MessageIDOptions, opts *echotron.MessageCaptionOptions) (*echotron.Message, error) {
+	res, err := c.api.EditMessageCaption(msg, opts)
+	return unwrap(res.Result, err)
+}
+
+// EditMessageMedia calls the EditMessageMedia method of the BotAPI and returns its result.
+func (c Client) EditMessageMedia(msg echotron.MessageIDOptions, media echotron.InputMedia, opts *echotron.MessageReplyMarkup) (*echotron.Message, error) {
+	res, err := c.api.EditMessageMedia(msg, media, opts)
+	return unwrap(res.Result, err)
+}
+
+// EditMessageReplyMarkup calls the EditMessageReplyMarkup method of the BotAPI and returns its result.
+func (c Client) EditMessageReplyMarkup(msg echotron.MessageIDOptions, opts *echotron.MessageReplyMarkup) (*echotron.Message, error) {
+	res, err := c.api.EditMessageReplyMarkup(msg, opts)
+	return unwrap(res.Result, err)
+}
+
+// StopPoll calls the StopPoll method of the BotAPI and returns its result.
+func (c Client) StopPoll(chatID int64, messageID int, opts *echotron.MessageReplyMarkup) (*echotron.Poll, error) {
+	res, err := c.api.StopPoll(chatID, messageID, opts)
+	return unwrap(res.Result, err)
+}
+
+// DeleteMessage calls the DeleteMessage method of the BotAPI and returns its error.
+func (c Client) DeleteMessage(chatID int64, messageID int) error {
+	_, err := c.api.DeleteMessage(chatID, messageID)
+	return err
+}
+
+// AnswerCallbackQuery calls the AnswerCallbackQuery method of the BotAPI and returns its result.
+func (c Client) AnswerCallbackQuery(callbackID string, opts *echotron.CallbackQueryOptions) (bool, error) {
+	res, err := c.api.AnswerCallbackQuery(callbackID, opts)
+	return unwrap(res.Result, err)
+}
+
+// GetFile calls the GetFile method of the BotAPI and returns its result.
+func (c Client) GetFile(fileID string) (*echotron.File, error) {
+	res, err := c.api.GetFile(fileID)
+	return unwrap(res.Result, err)
+}
+
+// DownloadFile calls the DownloadFile method of the BotAPI.
+func (c Client) DownloadFile(filePath string) ([]byte, error) {
+	return c.api.DownloadFile(filePath)
+}
+
+// GetUserProfilePhotos calls the GetUserProfilePhotos method of the BotAPI and returns its result.
+func (c Client) GetUserProfilePhotos(userID int64, opts *echotron.UserProfileOptions) (*echotron.UserProfilePhotos, error) {
+	res, err := c.api.GetUserProfilePhotos(userID, opts)
+	return unwrap(res.Result, err)
+}
+
+// BanChatMember calls the BanChatMember method of the BotAPI and returns its result.
+func (c Client) BanChatMember(chatID int64, userID int64, opts *echotron.BanOptions) (bool, error) {
+	res, err := c.api.BanChatMember(chatID, userID, opts)
+	return unwrap(res.Result, err)
+}
+
+// UnbanChatMember calls the UnbanChatMember method of the BotAPI and returns its result.
+func (c Client) UnbanChatMember(chatID int64, userID int64, opts *echotron.UnbanOptions) (bool, error) {
+	res, err := c.api.UnbanChatMember(chatID, userID, opts)
+	return unwrap(res.Result, err)
+}
+
+// RestrictChatMember calls the RestrictChatMember method of the BotAPI and returns its result.
+func (c Client) RestrictChatMember(chatID int64, userID int64, permissions echotron.ChatPermissions, opts *echotron.RestrictOptions) (bool, error) {
+	res, err := c.api.RestrictChatMember(chatID, userID, permissions, opts)
+	return unwrap(res.Result, err)
+}
+
+// PromoteChatMember calls the PromoteChatMember method of the BotAPI and returns its result.
+func (c Client) PromoteChatMember(chatID int64, userID int64, opts *echotron.PromoteOptions) (bool, error) {
+	res, err := c.api.PromoteChatMember(chatID, userID, opts)
+	return unwrap(res.Result, err)
+}
+
+// SetChatAdministratorCustomTitle calls the SetChatAdministratorCustomTitle method of the BotAPI and returns its result.
+func (c Client) SetChatAdministratorCustomTitle(chatID int64, userID int64, customTitle string) (bool, error) {
+	res, err := c.api.SetChatAdministratorCustomTitle(chatID, userID, customTitle)
+	return unwrap(res.Result, err)
+}
+
+// BanChatSenderChat calls the BanChatSenderChat method of the BotAPI and returns its result.
+func (c Client) BanChatSenderChat(chatID int64, senderChatID int64) (bool, error) {
+	res, err := c.api.BanChatSenderChat(chatID, senderChatID)
+	return unwrap(res.Result, err)
+}
+
+// UnbanChatSenderChat calls the UnbanChatSenderChat method of the BotAPI and returns its result.
+func (c Client) UnbanChatSenderChat(chatID int64, senderChatID int64) (bool, error) {
+	res, err := c.api.UnbanChatSenderChat(chatID, senderChatID)
+	return unwrap(res.Result, err)
+}
+
+// SetChatPermissions calls the SetChatPermissions method of the BotAPI and returns its result.
+func (c Client) SetChatPermissions(chatID int64, permissions echotron.ChatPermissions, opts *echotron.ChatPermissionsOptions) (bool, error) {
+	res, err := c.api.SetChatPermissions(chatID, permissions, opts)
+	return unwrap(res.Result, err)
+}
+
+// ExportChatInviteLink calls the ExportChatInviteLink method of the BotAPI and returns its result.
+func (c Client) ExportChatInviteLink(chatID int64) (string, error) {
+	res, err := c.api.ExportChatInviteLink(chatID)
+	return unwrap(res.Result, err)
+}
+
+// CreateChatInviteLink calls the CreateChatInviteLink method of the BotAPI and returns its result.
+func (c Client) CreateChatInviteLink(chatID int64, opts *echotron.InviteLinkOptions) (*echotron.ChatInviteLink, error) {
+	res, err := c.api.CreateChatInviteLink(chatID, opts)
+	return unwrap(res.Result, err)
+}
+
+// EditChatInviteLink calls the EditChatInviteLink method of the BotAPI and returns its result.
+func (c Client) EditChatInviteLink(chatID int64, inviteLink string, opts *echotron.InviteLinkOptions) (*echotron.ChatInviteLink, error) {
+	res, err := c.api.EditChatInviteLink(chatID, inviteLink, opts)
+	return unwrap(res.Result, err)
+}
+
+// RevokeChatInviteLink calls the RevokeChatInviteLink method of the BotAPI and returns its result.
+func (c Client) RevokeChatInviteLink(chatID int64, inviteLink string) (*echotron.ChatInviteLink, error) {
+	res, err := c.api.RevokeChatInviteLink(chatID, inviteLink)
+	return unwrap(res.Result, err)
+}
+
+// ApproveChatJoinRequest calls the ApproveChatJoinRequest method of the BotAPI and returns its result.
+func (c Client) ApproveChatJoinRequest(chatID int64, userID int64) (bool, error) {
+	res, err := c.api.ApproveChatJoinRequest(chatID, userID)
+	return unwrap(res.Result, err)
+}
+
+// DeclineChatJoinRequest calls the DeclineChatJoinRequest method of the BotAPI and returns its result.
+func (c Client) DeclineChatJoinRequest(chatID int64, userID int64) (bool, error) {
+	res, err := c.api.DeclineChatJoinRequest(chatID, userID)
+	return unwrap(res.Result, err)
+}
+
+// SetChatPhoto calls the SetChatPhoto method of the BotAPI and returns its result.
+func (c Client) SetChatPhoto(file echotron.InputFile, chatID int64) (bool, error) {
+	res, err := c.api.SetChatPhoto(file, chatID)
+	return unwrap(res.Result, err)
+}
+
+// DeleteChatPhoto calls the DeleteChatPhoto method of the BotAPI and returns its result.
+func (c Client) DeleteChatPhoto(chatID int64) (bool, error) {
+	res, err := c.api.DeleteChatPhoto(chatID)
+	return unwrap(res.Result, err)
+}
+
+// SetChatTitle calls the SetChatTitle method of the BotAPI and returns its result.
+func (c Client) SetChatTitle(chatID int64, title string) (bool, error) {
+	res, err := c.api.SetChatTitle(chatID, title)
+	return unwrap(res.Result, err)
+}
+
+// SetChatDescription calls the SetChatDescription method of the BotAPI and returns its result.
+func (c Client) SetChatDescription(chatID int64, description string) (bool, error) {
+	res, err := c.api.SetChatDescription(chatID, description)
+	return unwrap(res.Result, err)
+}
+
+// PinChatMessage calls the PinChatMessage method of the BotAPI and returns its result.
+func (c Client) PinChatMessage(chatID int64, messageID int, opts *echotron.PinMessageOptions) (bool, error) {
+	res, err := c.api.PinChatMessage(chatID, messageID, opts)
+	return unwrap(res.Result, err)
+}
+
+// UnpinChatMessage calls the UnpinChatMessage method of the BotAPI and returns its result.
+func (c Client) UnpinChatMessage(chatID int64, messageID int) (bool, error) {
+	res, err := c.api.UnpinChatMessage(chatID, messageID)
+	return unwrap(res.Result, err)
+}
+
+// UnpinAllChatMessages calls the UnpinAllChatMessages method of the BotAPI and returns its result.
+func (c Client) UnpinAllChatMessages(chatID int64) (bool, error) {
+	res, err := c.api.UnpinAllChatMessages(chatID)
+	return unwrap(res.Result, err)
+}
+
+// LeaveChat calls the LeaveChat method of the BotAPI and returns its result.
+func (c Client) LeaveChat(chatID int64) (bool, error) {
+	res, err := c.api.LeaveChat(chatID)
+	return unwrap(res.Result, err)
+}
+
+// GetChat calls the GetChat method of the BotAPI and returns its result.
+func (c Client) GetChat(chatID int64) (*echotron.Chat, error) {
+	res, err := c.api.GetChat(chatID)
+	return unwrap(res.Result, err)
+}
+
+// GetChatAdministrators calls the GetChatAdministrators method of the BotAPI and returns its result.
+func (c Client) GetChatAdministrators(chatID int64) ([]*echotron.ChatMember, error) {
+	res, err := c.api.GetChatAdministrators(chatID)
+	return unwrap(res.Result, err)
+}
+
+// GetChatMemberCount calls the GetChatMemberCount method of the BotAPI and returns its result.
+func (c Client) GetChatMemberCount(chatID int64) (int, error) {
+	res, err := c.api.GetChatMemberCount(chatID)
+	return unwrap(res.Result, err)
+}
+
+// GetChatMember calls the GetChatMember method of the BotAPI and returns its result.
+func (c Client) GetChatMember(chatID int64, userID int64) (*echotron.ChatMember, error) {
+	res, err := c.api.GetChatMember(chatID, userID)
+	return unwrap(res.Result, err)
+}
+
+// SetChatStickerSet calls the SetChatStickerSet method of the BotAPI and returns its result.
+func (c Client) SetChatStickerSet(chatID int64, stickerSetName string) (bool, error) {
+	res, err := c.api.SetChatStickerSet(chatID, stickerSetName)
+	return unwrap(res.Result, err)
+}
+
+// DeleteChatStickerSet calls the DeleteChatStickerSet method of the BotAPI and returns its result.
+func (c Client) DeleteChatStickerSet(chatID int64) (bool, error) {
+	res, err := c.api.DeleteChatStickerSet(chatID)
+	return unwrap(res.Result, err)
+}
+
+// CreateForumTopic calls the CreateForumTopic method of the BotAPI and returns its result.
+func (c Client) CreateForumTopic(chatID int64, name string, opts *echotron.CreateTopicOptions) (*echotron.ForumTopic, error) {
+	res, err := c.api.CreateForumTopic(chatID, name, opts)
+	return unwrap(res.Result, err)
+}
+
+// EditForumTopic calls the EditForumTopic method of the BotAPI and returns its result.
+func (c Client) EditForumTopic(chatID int64, messageThreadID int64, opts *echotron.EditTopicOptions) (bool, error) {
+	res, err := c.api.EditForumTopic(chatID, messageThreadID, opts)
+	return unwrap(res.Result, err)
+}
+
+// CloseForumTopic calls the CloseForumTopic method of the BotAPI and returns its result.
+func (c Client) CloseForumTopic(chatID int64, messageThreadID int64) (bool, error) {
+	res, err := c.api.CloseForumTopic(chatID, messageThreadID)
+	return unwrap(res.Result, err)
+}
+
+// ReopenForumTopic calls the ReopenForumTopic method of the BotAPI and returns its result.
+func (c Client) ReopenForumTopic(chatID int64, messageThreadID int64) (bool, error) {
+	res, err := c.api.ReopenForumTopic(chatID, messageThreadID)
+	return unwrap(res.Result, err)
+}
+
+// DeleteForumTopic calls the DeleteForumTopic method of the BotAPI and returns its result.
+func (c Client) DeleteForumTopic(chatID int64, messageThreadID int64) (bool, error) {
+	res, err := c.api.DeleteForumTopic(chatID, messageThreadID)
+	return unwrap(res.Result, err)
+}
+
+// UnpinAllForumTopicMessages calls the UnpinAllForumTopicMessages method of the BotAPI and returns its result.
+func (c Client) UnpinAllForumTopicMessages(chatID int64, messageThreadID int64) (bool, error) {
+	res, err := c.api.UnpinAllForumTopicMessages(chatID, messageThreadID)
+	return unwrap(res.Result, err)
+}
+
+// EditGeneralForumTopic calls the EditGeneralForumTopic method of the BotAPI and returns its result.
+func (c Client) EditGeneralForumTopic(chatID int64, name string) (bool, error) {
+	res, err := c.api.EditGeneralForumTopic(chatID, name)
+	return unwrap(res.Result, err)
+}
+
+// CloseGeneralForumTopic calls the CloseGeneralForumTopic method of the BotAPI and returns its result.
+func (c Client) CloseGeneralForumTopic(chatID int64) (bool, error) {
+	res, err := c.api.CloseGeneralForumTopic(chatID)
+	return unwrap(res.Result, err)
+}
+
+// ReopenGeneralForumTopic calls the ReopenGeneralForumTopic method of the BotAPI and returns its result.
+func (c Client) ReopenGeneralForumTopic(chatID int64) (bool, error) {
+	res, err := c.api.ReopenGeneralForumTopic(chatID)
+	return unwrap(res.Result, err)
+}
+
+// HideGeneralForumTopic calls the HideGeneralForumTopic method of the BotAPI and returns its result.
+func (c Client) HideGeneralForumTopic(chatID int64) (bool, error) {
+	res, err := c.api.HideGeneralForumTopic(chatID)
+	return unwrap(res.Result, err)
+}
+
+// UnhideGeneralForumTopic calls the UnhideGeneralForumTopic method of the BotAPI and returns its result.
+func (c Client) UnhideGeneralForumTopic(chatID int64) (bool, error) {
+	res, err := c.api.UnhideGeneralForumTopic(chatID)
+	return unwrap(res.Result, err)
+}
+
+// SendSticker calls the SendSticker method of the BotAPI and returns its result.
+func (c Client) SendSticker(stickerID string, chatID int64, opts *echotron.StickerOptions) (*echotron.Message, error) {
+	res, err := c.api.SendSticker(stickerID, chatID, opts)
+	return unwrap(res.Result, err)
+}
+
+// GetStickerSet calls the GetStickerSet method of the BotAPI and returns its result.
+func (c Client) GetStickerSet(name string) (*echotron.StickerSet, error) {
+	res, err := c.api.GetStickerSet(name)
+	return unwrap(res.Result, err)
+}
+
+// GetCustomEmojiStickers calls the GetCustomEmojiStickers method of the BotAPI and returns its result.
+func (c Client) GetCustomEmojiStickers(customEmojiIDs ...string) ([]*echotron.Sticker, error) {
+	res, err := c.api.GetCustomEmojiStickers(customEmojiIDs...)
+	return unwrap(res.Result, err)
+}
+
+// UploadStickerFile calls the UploadStickerFile method of the BotAPI and returns its result.
+func (c Client) UploadStickerFile(userID int64, sticker echotron.InputFile, format echotron.StickerFormat) (*echotron.File, error) {
+	res, err := c.api.UploadStickerFile(userID, sticker, format)
+	return unwrap(res.Result, err)
+}
+
+// CreateNewStickerSet calls the CreateNewStickerSet method of the BotAPI and returns its result.
+func (c Client) CreateNewStickerSet(userID int64, name string, title string, stickers []echotron.InputSticker, format echotron.StickerFormat, opts *echotron.NewStickerSetOptions) (bool, error) {
+	res, err := c.api.CreateNewStickerSet(userID, name, title, stickers, format, opts)
+	return unwrap(res.Result, err)
+}
+
+// AddStickerToSet calls the AddStickerToSet method of the BotAPI and returns its result.
+func (c Client) AddStickerToSet(userID int64, name string, sticker echotron.InputSticker) (bool, error) {
+	res, err := c.api.AddStickerToSet(userID, name, sticker)
+	return unwrap(res.Result, err)
+}
+
+// SetStickerPositionInSet calls the SetStickerPositionInSet method of the BotAPI and returns its error.
+func (c Client) SetStickerPositionInSet(sticker string, position int) error {
+	_, err := c.api.SetStickerPositionInSet(sticker, position)
+	return err
+}
+
+// DeleteStickerFromSet calls the DeleteStickerFromSet method of the BotAPI and returns its error.
+func (c Client) DeleteStickerFromSet(sticker string) error {
+	_, err := c.api.DeleteStickerFromSet(sticker)
+	return err
+}
+
+// SetStickerEmojiList calls the SetStickerEmojiList method of the BotAPI and returns its result.
+func (c Client) SetStickerEmojiList(sticker string, emojis []string) (bool, error) {
+	res, err := c.api.SetStickerEmojiList(sticker, emojis)
+	return unwrap(res.Result, err)
+}
+
+// SetStickerKeywords calls the SetStickerKeywords method of the BotAPI and returns its result.
+func (c Client) SetStickerKeywords(sticker string, keywords []string) (bool, error) {
+	res, err := c.api.SetStickerKeywords(sticker, keywords)
+	return unwrap(res.Result, err)
+}
+
+// SetStickerMaskPosition calls the SetStickerMaskPosition method of the BotAPI and returns its result.
+func (c Client) SetStickerMaskPosition(sticker string, mask echotron.MaskPosition) (bool, error) {
+	res, err := c.api.SetStickerMaskPosition(sticker, mask)
+	return unwrap(res.Result, err)
+}
+
+// SetStickerSetTitle calls the SetStickerSetTitle method of the BotAPI and returns its result.
+func (c Client) SetStickerSetTitle(name string, title string) (bool, error) {
+	res, err := c.api.SetStickerSetTitle(name, title)
+	return unwrap(res.Result, err)
+}
+
+// SetStickerSetThumbnail calls the SetStickerSetThumbnail method of the BotAPI and returns its error.
+func (c Client) SetStickerSetThumbnail(name string, userID int64, thumbnail echotron.InputFile) error {
+	_, err := c.api.SetStickerSetThumbnail(name, userID, thumbnail)
+	return err
+}
+
+// SetCustomEmojiStickerSetThumbnail calls the SetCustomEmojiStickerSetThumbnail method of the BotAPI and returns its result.
+func (c Client) SetCustomEmojiStickerSetThumbnail(name string, emojiID string) (bool, error) {
+	res, err := c.api.SetCustomEmojiStickerSetThumbnail(name, emojiID)
+	return unwrap(res.Result, err)
+}
+
+// DeleteStickerSet calls the DeleteStickerSet method of the BotAPI and returns its result.
+func (c Client) DeleteStickerSet(name string) (bool, error) {
+	res, err := c.api.DeleteStickerSet(name)
+	return unwrap(res.Result, err)
+}
+
+// GetForumTopicIconStickers calls the GetForumTopicIconStickers method of the BotAPI and returns its result.
+func (c Client) GetForumTopicIconStickers() ([]*echotron.Sticker, error) {
+	res, err := c.api.GetForumTopicIconStickers()
+	return unwrap(res.Result, err)
+}
+
+// SendInvoice calls the SendInvoice method of the BotAPI and returns its result.
+func (c Client) SendInvoice(chatID int64, title string, description string, payload string, providerToken string, currency string, prices []echotron.LabeledPrice, opts *echotron.InvoiceOptions) (*echotron.Message, error) {
+	res, err := c.api.SendInvoice(chatID, title, description, payload, providerToken, currency, prices, opts)
+	return unwrap(res.Result, err)
+}
+
+// AnswerShippingQuery calls the AnswerShippingQuery method of the BotAPI and returns its error.
+func (c Client) AnswerShippingQuery(shippingQueryID string, ok bool, opts *echotron.ShippingQueryOptions) error {
+	_, err := c.api.AnswerShippingQuery(shippingQueryID, ok, opts)
+	return err
+}
+
+// AnswerPreCheckoutQuery calls the AnswerPreCheckoutQuery method of the BotAPI and returns its error.
+func (c Client) AnswerPreCheckoutQuery(preCheckoutQueryID string, ok bool, opts *echotron.PreCheckoutOptions) error {
+	_, err := c.api.AnswerPreCheckoutQuery(preCheckoutQueryID, ok, opts)
+	return err
+}
+
+// CreateInvoiceLink calls the CreateInvoiceLink method of the BotAPI and returns its error.
+func (c Client) CreateInvoiceLink(title string, description string, payload string, providerToken string, currency string, prices []echotron.LabeledPrice, opts *echotron.CreateInvoiceLinkOptions) error {
+	_, err := c.api.CreateInvoiceLink(title, description, payload, providerToken, currency, prices, opts)
+	return err
+}
+
+// AnswerInlineQuery calls the AnswerInlineQuery method of the BotAPI and returns its error.
+func (c Client) AnswerInlineQuery(inlineQueryID string, results []echotron.InlineQueryResult, opts *echotron.InlineQueryOptions) error {
+	_, err := c.api.AnswerInlineQuery(inlineQueryID, results, opts)
+	return err
+}
+
+// AnswerWebAppQuery calls the AnswerWebAppQuery method of the BotAPI and returns its result.
+func (c Client) AnswerWebAppQuery(webAppQueryID string, result echotron.InlineQueryResult) (*echotron.SentWebAppMessage, error) {
+	res, err := c.api.AnswerWebAppQuery(webAppQueryID, result)
+	return unwrap(res.Result, err)
+}
+
+// SendGame calls the SendGame method of the BotAPI and returns its result.
+func (c Client) SendGame(gameShortName string, chatID int64, opts *echotron.BaseOptions) (*echotron.Message, error) {
+	res, err := c.api.SendGame(gameShortName, chatID, opts)
+	return unwrap(res.Result, err)
+}
+
+// SetGameScore calls the SetGameScore method of the BotAPI and returns its result.
+func (c Client) SetGameScore(userID int64, score int, msgID echotron.MessageIDOptions, opts *echotron.GameScoreOptions) (*echotron.Message, error) {
+	res, err := c.api.SetGameScore(userID, score, msgID, opts)
+	return unwrap(res.Result, err)
+}
+
+// GetGameHighScores calls the GetGameHighScores method of the BotAPI and returns its result.
+func (c Client) GetGameHighScores(userID int64, opts echotron.MessageIDOptions) ([]*echotron.GameHighScore, error) {
+	res, err := c.api.GetGameHighScores(userID, opts)
+	return unwrap(res.Result, err)
+}
+
+// SetPassportDataErrors calls the SetPassportDataErrors method of the BotAPI and returns its result.
+func (c Client) SetPassportDataErrors(userID int64, errors []echotron.PassportElementError) (bool, error) {
+	res, err := c.api.SetPassportDataErrors(userID, errors)
+	return unwrap(res.Result, err)
+}
+
+// CopyMessages calls the CopyMessages method of the BotAPI and returns its result.
+func (c Client) CopyMessages(chatID int64, fromChatID int64, messageIDs []int, opts *echotron.CopyMessagesOptions) ([]*echotron.MessageID, error) {
+	res, err := c.api.CopyMessages(chatID, fromChatID, messageIDs, opts)
+	return unwrap(res.Result, err)
+}
+
+// DeleteMessages calls the DeleteMessages method of the BotAPI and returns its result.
+func (c Client) DeleteMessages(chatID int64, messageIDs []int) (bool, error) {
+	res, err := c.api.DeleteMessages(chatID, messageIDs)
+	return unwrap(res.Result, err)
+}
+
+// ForwardMessages calls the ForwardMessages method of the BotAPI and returns its result.
+func (c Client) ForwardMessages(chatID int64, fromChatID int64, messageIDs []int, opts *echotron.ForwardMessagesOptions) ([]*echotron.MessageID, error) {
+	res, err := c.api.ForwardMessages(chatID, fromChatID, messageIDs, opts)
+	return unwrap(res.Result, err)
+}
+
+// GetUserChatBoosts calls the GetUserChatBoosts method of the BotAPI and returns its result.
+func (c Client) GetUserChatBoosts(chatID int64, userID int64) (*echotron.UserChatBoosts, error) {
+	res, err := c.api.GetUserChatBoosts(chatID, userID)
+	return unwrap(res.Result, err)
+}
+
+// SetMessageReaction calls the SetMessageReaction method of the BotAPI and returns its result.
+func (c Client) SetMessageReaction(chatID int64, messageID int, opts *echotron.SetMessageReactionOptions) (bool, error) {
+	res, err := c.api.SetMessageReaction(chatID, messageID, opts)
+	return unwrap(res.Result, err)
+}
+
+// UnpinAllGeneralForumTopicMessages calls the UnpinAllGeneralForumTopicMessages method of the BotAPI and returns its result.
+func (c Client) UnpinAllGeneralForumTopicMessages(chatID int64) (bool, error) {
+	res, err := c.api.UnpinAllGeneralForumTopicMessages(chatID)
+	return unwrap(res.Result, err)
+}
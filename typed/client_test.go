@@ -0,0 +1,101 @@
+package typed
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/NicoNex/echotron/v3"
+	"github.com/NicoNex/echotron/v3/echotrontest"
+)
+
+func TestClientCoversBotAPI(t *testing.T) {
+	var (
+		client = reflect.TypeOf(Client{})
+		iface  = reflect.TypeOf((*echotron.BotAPI)(nil)).Elem()
+	)
+
+	for i := 0; i < iface.NumMethod(); i++ {
+		name := iface.Method(i).Name
+		if name == "Call" {
+			continue
+		}
+		if _, ok := client.MethodByName(name); !ok {
+			t.Errorf("BotAPI.%s is missing from the Client, run go generate", name)
+		}
+	}
+}
+
+func TestClient(t *testing.T) {
+	srv := echotrontest.NewServer()
+	defer srv.Close()
+
+	client := New(srv.API())
+
+	msg, err := client.SendMessage("hello", 42, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Text != "hello" || msg.Chat.ID != 42 {
+		t.Fatalf("unexpected message %+v", msg)
+	}
+
+	me, err := client.GetMe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if me.ID != srv.Bot().ID {
+		t.Fatalf("unexpected user %+v", me)
+	}
+
+	if err := client.DeleteMessage(42, msg.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	chat, err := Do[*echotron.Chat](client, "getChat", map[string]any{"chat_id": 42}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chat.ID != 42 {
+		t.Fatalf("unexpected chat %+v", chat)
+	}
+}
+
+func TestClientError(t *testing.T) {
+	srv := echotrontest.NewServer()
+	defer srv.Close()
+
+	srv.Handle("sendMessage", func(*echotrontest.Call) (any, error) {
+		return nil, &echotrontest.Error{Code: http.StatusTooManyRequests, Description: "Too Many Requests: retry after 5", RetryAfter: 5}
+	})
+
+	msg, err := New(srv.API()).SendMessage("hello", 42, nil)
+	if msg != nil {
+		t.Fatalf("unexpected message %+v", msg)
+	}
+
+	var apiErr *echotron.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an APIError, got %v", err)
+	}
+	if apiErr.ErrorCode() != http.StatusTooManyRequests || apiErr.Parameters() == nil || apiErr.Parameters().RetryAfter != 5 {
+		t.Fatalf("unexpected error %v with parameters %+v", apiErr, apiErr.Parameters())
+	}
+}
+
+func TestClientMock(t *testing.T) {
+	var mock echotrontest.Mock
+
+	mock.GetUpdatesFunc = func(*echotron.UpdateOptions) (echotron.APIResponseUpdate, error) {
+		return echotron.APIResponseUpdate{Result: []*echotron.Update{{ID: 1}, {ID: 2}}}, nil
+	}
+
+	updates, err := New(&mock).GetUpdates(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 2 || updates[1].ID != 2 {
+		t.Fatalf("unexpected updates %+v", updates)
+	}
+}
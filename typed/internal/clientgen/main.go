@@ -0,0 +1,269 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Clientgen generates the methods of the typed.Client object from the interfaces
+// embedded in echotron.BotAPI, so that the client is kept in sync with the API.
+// Each method returns the Result field of the APIResponse* type returned by the
+// corresponding method of the BotAPI.
+//
+// Usage:
+//
+//	go run ./internal/clientgen -dir .. -o client_gen.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"log"
+	"os"
+	"strings"
+)
+
+// skip contains the methods of the BotAPI which aren't wrapped by the client:
+// Call is replaced by the generic Do function.
+var skip = map[string]bool{
+	"Call": true,
+}
+
+type param struct {
+	name     string
+	typ      string
+	variadic bool
+}
+
+type method struct {
+	name    string
+	params  []param
+	results []string
+}
+
+func main() {
+	var (
+		dir = flag.String("dir", "..", "the directory of the echotron package")
+		out = flag.String("o", "client_gen.go", "the output file")
+	)
+	flag.Parse()
+
+	methods, results, err := parse(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	code, err := generate(methods, results)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(*out, code, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parse returns the methods of the interfaces embedded in BotAPI, in order, and the types
+// of the Result field of the APIResponse* types declared in the echotron package in dir.
+func parse(dir string) ([]method, map[string]string, error) {
+	var (
+		ifaces  = make(map[string]*ast.InterfaceType)
+		results = make(map[string]string)
+		notTest = func(fi fs.FileInfo) bool { return !strings.HasSuffix(fi.Name(), "_test.go") }
+	)
+
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, notTest, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	pkg, ok := pkgs["echotron"]
+	if !ok {
+		return nil, nil, fmt.Errorf("package echotron not found in %s", dir)
+	}
+
+	for _, f := range pkg.Files {
+		for _, d := range f.Decls {
+			gd, ok := d.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+
+			for _, s := range gd.Specs {
+				ts := s.(*ast.TypeSpec)
+
+				switch t := ts.Type.(type) {
+				case *ast.InterfaceType:
+					ifaces[ts.Name.Name] = t
+				case *ast.StructType:
+					if strings.HasPrefix(ts.Name.Name, "APIResponse") {
+						if typ, ok := resultType(t); ok {
+							results["echotron."+ts.Name.Name] = typ
+						}
+					}
+				}
+			}
+		}
+	}
+
+	root, ok := ifaces["BotAPI"]
+	if !ok {
+		return nil, nil, fmt.Errorf("BotAPI not found in %s", dir)
+	}
+
+	methods, err := collect(root, ifaces)
+	return methods, results, err
+}
+
+// resultType returns the type of the Result field of the struct, if any.
+func resultType(st *ast.StructType) (string, bool) {
+	for _, field := range st.Fields.List {
+		for _, n := range field.Names {
+			if n.Name == "Result" {
+				return qualify(field.Type), true
+			}
+		}
+	}
+	return "", false
+}
+
+func collect(it *ast.InterfaceType, ifaces map[string]*ast.InterfaceType) ([]method, error) {
+	var ret []method
+
+	for _, field := range it.Methods.List {
+		switch t := field.Type.(type) {
+		case *ast.Ident:
+			embedded, ok := ifaces[t.Name]
+			if !ok {
+				return nil, fmt.Errorf("interface %s not found", t.Name)
+			}
+			methods, err := collect(embedded, ifaces)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, methods...)
+
+		case *ast.FuncType:
+			if name := field.Names[0].Name; !skip[name] {
+				ret = append(ret, newMethod(name, t))
+			}
+		}
+	}
+	return ret, nil
+}
+
+func newMethod(name string, ft *ast.FuncType) method {
+	m := method{name: name}
+
+	for _, p := range ft.Params.List {
+		_, variadic := p.Type.(*ast.Ellipsis)
+		for _, n := range p.Names {
+			m.params = append(m.params, param{name: n.Name, typ: qualify(p.Type), variadic: variadic})
+		}
+	}
+
+	for _, r := range ft.Results.List {
+		m.results = append(m.results, qualify(r.Type))
+	}
+	return m
+}
+
+// qualify returns the type expression with the types declared in the echotron package qualified.
+func qualify(e ast.Expr) string {
+	switch t := e.(type) {
+	case *ast.Ident:
+		if ast.IsExported(t.Name) {
+			return "echotron." + t.Name
+		}
+		return t.Name
+	case *ast.StarExpr:
+		return "*" + qualify(t.X)
+	case *ast.ArrayType:
+		return "[]" + qualify(t.Elt)
+	case *ast.Ellipsis:
+		return "..." + qualify(t.Elt)
+	case *ast.MapType:
+		return "map[" + qualify(t.Key) + "]" + qualify(t.Value)
+	case *ast.SelectorExpr:
+		return qualify(t.X) + "." + t.Sel.Name
+	default:
+		panic(fmt.Sprintf("unsupported type expression %T", e))
+	}
+}
+
+func generate(methods []method, results map[string]string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteString("// Code generated by clientgen; DO NOT EDIT.\n\n")
+	buf.WriteString("package typed\n\n")
+	buf.WriteString("import \"github.com/NicoNex/echotron/v3\"\n")
+
+	for _, m := range methods {
+		if len(m.results) != 2 || m.results[1] != "error" {
+			return nil, fmt.Errorf("unsupported results of method %s: %s", m.name, strings.Join(m.results, ", "))
+		}
+
+		res := m.results[0]
+		typ, ok := results[res]
+
+		switch {
+		case res == "echotron.APIResponseBase":
+			fmt.Fprintf(&buf, "\n// %s calls the %s method of the BotAPI and returns its error.\n", m.name, m.name)
+			fmt.Fprintf(&buf, "func (c Client) %s(%s) error {\n", m.name, m.signature())
+			fmt.Fprintf(&buf, "\t_, err := c.api.%s(%s)\n\treturn err\n}\n", m.name, m.args())
+
+		case ok:
+			fmt.Fprintf(&buf, "\n// %s calls the %s method of the BotAPI and returns its result.\n", m.name, m.name)
+			fmt.Fprintf(&buf, "func (c Client) %s(%s) (%s, error) {\n", m.name, m.signature(), typ)
+			fmt.Fprintf(&buf, "\tres, err := c.api.%s(%s)\n\treturn unwrap(res.Result, err)\n}\n", m.name, m.args())
+
+		case strings.HasPrefix(res, "echotron.APIResponse"):
+			return nil, fmt.Errorf("result type of %s not found", res)
+
+		default:
+			fmt.Fprintf(&buf, "\n// %s calls the %s method of the BotAPI.\n", m.name, m.name)
+			fmt.Fprintf(&buf, "func (c Client) %s(%s) (%s, error) {\n", m.name, m.signature(), res)
+			fmt.Fprintf(&buf, "\treturn c.api.%s(%s)\n}\n", m.name, m.args())
+		}
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+func (m method) signature() string {
+	var s []string
+
+	for _, p := range m.params {
+		s = append(s, p.name+" "+p.typ)
+	}
+	return strings.Join(s, ", ")
+}
+
+func (m method) args() string {
+	var s []string
+
+	for _, p := range m.params {
+		if p.variadic {
+			s = append(s, p.name+"...")
+		} else {
+			s = append(s, p.name)
+		}
+	}
+	return strings.Join(s, ", ")
+}
@@ -18,7 +18,12 @@
 
 package echotron
 
-import "encoding/json"
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+)
 
 // Update represents an incoming update.
 // At most one of the optional parameters can be present in any given update.
@@ -36,6 +41,108 @@ type Update struct {
 	MyChatMember       *ChatMemberUpdated  `json:"my_chat_member,omitempty"`
 	ChatMember         *ChatMemberUpdated  `json:"chat_member,omitempty"`
 	ID                 int                 `json:"update_id"`
+
+	album *Album
+	ctx   context.Context
+	src   *rawJSON
+}
+
+var (
+	updateStruct            = reflect.TypeOf(Update{})
+	messageStruct           = reflect.TypeOf(Message{})
+	chatStruct              = reflect.TypeOf(Chat{})
+	callbackQueryStruct     = reflect.TypeOf(CallbackQuery{})
+	chatMemberUpdatedStruct = reflect.TypeOf(ChatMemberUpdated{})
+	chatJoinRequestStruct   = reflect.TypeOf(ChatJoinRequest{})
+)
+
+// UnmarshalJSON decodes the update keeping a copy of the JSON it has been decoded from,
+// which the messages and chats of the update refer to.
+func (u *Update) UnmarshalJSON(b []byte) error {
+	type update Update
+
+	if err := json.Unmarshal(b, (*update)(u)); err != nil {
+		return err
+	}
+	u.src = &rawJSON{data: append(json.RawMessage(nil), b...), typ: updateStruct}
+
+	bindMessage(u.Message, u.src, "message")
+	bindMessage(u.EditedMessage, u.src, "edited_message")
+	bindMessage(u.ChannelPost, u.src, "channel_post")
+	bindMessage(u.EditedChannelPost, u.src, "edited_channel_post")
+	if u.CallbackQuery != nil {
+		bindMessage(u.CallbackQuery.Message, u.src.child("callback_query", callbackQueryStruct), "message")
+	}
+	if u.MyChatMember != nil {
+		u.MyChatMember.Chat.src = u.src.child("my_chat_member", chatMemberUpdatedStruct).child("chat", chatStruct)
+	}
+	if u.ChatMember != nil {
+		u.ChatMember.Chat.src = u.src.child("chat_member", chatMemberUpdatedStruct).child("chat", chatStruct)
+	}
+	if u.ChatJoinRequest != nil {
+		u.ChatJoinRequest.Chat.src = u.src.child("chat_join_request", chatJoinRequestStruct).child("chat", chatStruct)
+	}
+	return nil
+}
+
+// bindMessage makes the message found under the given key of parent, and its chats,
+// refer to the JSON of parent.
+func bindMessage(m *Message, parent *rawJSON, key string) {
+	if m == nil {
+		return
+	}
+
+	m.src = parent.child(key, messageStruct)
+	m.Chat.src = m.src.child("chat", chatStruct)
+	if m.SenderChat != nil {
+		m.SenderChat.src = m.src.child("sender_chat", chatStruct)
+	}
+	bindMessage(m.ReplyToMessage, m.src, "reply_to_message")
+	bindMessage(m.PinnedMessage, m.src, "pinned_message")
+}
+
+// MarshalJSON encodes the update along with the fields not supported by echotron yet.
+func (u Update) MarshalJSON() ([]byte, error) {
+	type update Update
+
+	b, err := json.Marshal(update(u))
+	if err != nil {
+		return nil, err
+	}
+	return withUnknownFields(b, u.Unknown())
+}
+
+// Raw returns the JSON the update has been decoded from, or nil if it hasn't been decoded from JSON.
+func (u Update) Raw() json.RawMessage {
+	return u.src.raw()
+}
+
+// Unknown returns the fields of the JSON the update has been decoded from that aren't supported
+// by echotron yet, eg: the ones of the update types added by new versions of the Bot API.
+// They're extracted from the JSON returned by Raw on the first call, the returned map must not be modified.
+func (u Update) Unknown() map[string]json.RawMessage {
+	return u.src.unknownFields()
+}
+
+// Album returns the album the update has been aggregated into by the MediaGroups middleware,
+// or nil if the update doesn't carry an album.
+func (u Update) Album() *Album {
+	return u.album
+}
+
+// Context returns the context of the update, which carries its UpdateMeta when the update
+// is handled by a Dispatcher. It defaults to context.Background.
+func (u Update) Context() context.Context {
+	if u.ctx != nil {
+		return u.ctx
+	}
+	return context.Background()
+}
+
+// WithContext returns a shallow copy of the update with its context set to ctx.
+func (u Update) WithContext(ctx context.Context) *Update {
+	u.ctx = ctx
+	return &u
 }
 
 // ChatID returns the ID of the chat the update is coming from.
@@ -66,7 +173,73 @@ func (u Update) ChatID() int64 {
 	case u.ChatJoinRequest != nil:
 		return u.ChatJoinRequest.Chat.ID
 	default:
-		return 0
+		return u.unknownChatID()
+	}
+}
+
+// unknownChatID returns the ID of the chat or, failing that, of the user found in the
+// unknown fields of the update, so that the updates of the types not supported by
+// echotron yet still get to the right session.
+func (u Update) unknownChatID() int64 {
+	unknown := u.Unknown()
+	names := make([]string, 0, len(unknown))
+	for name := range unknown {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var v struct {
+			Chat *Chat `json:"chat"`
+			From *User `json:"from"`
+			User *User `json:"user"`
+		}
+
+		if json.Unmarshal(unknown[name], &v) != nil {
+			continue
+		}
+		switch {
+		case v.Chat != nil:
+			return v.Chat.ID
+		case v.From != nil:
+			return v.From.ID
+		case v.User != nil:
+			return v.User.ID
+		}
+	}
+	return 0
+}
+
+// Type returns the type of the update, or UnknownUpdate if it's not supported by echotron yet,
+// in which case its content can be found in the fields returned by Unknown.
+func (u Update) Type() UpdateType {
+	switch {
+	case u.Message != nil:
+		return MessageUpdate
+	case u.EditedMessage != nil:
+		return EditedMessageUpdate
+	case u.ChannelPost != nil:
+		return ChannelPostUpdate
+	case u.EditedChannelPost != nil:
+		return EditedChannelPostUpdate
+	case u.InlineQuery != nil:
+		return InlineQueryUpdate
+	case u.ChosenInlineResult != nil:
+		return ChosenInlineResultUpdate
+	case u.CallbackQuery != nil:
+		return CallbackQueryUpdate
+	case u.ShippingQuery != nil:
+		return ShippingQueryUpdate
+	case u.PreCheckoutQuery != nil:
+		return PreCheckoutQueryUpdate
+	case u.MyChatMember != nil:
+		return MyChatMemberUpdate
+	case u.ChatMember != nil:
+		return ChatMemberUpdate
+	case u.ChatJoinRequest != nil:
+		return ChatJoinRequestUpdate
+	default:
+		return UnknownUpdate
 	}
 }
 
@@ -92,9 +265,10 @@ type APIResponse interface {
 // APIResponseBase is a base type that represents the incoming response from Telegram servers.
 // Used by APIResponse* to slim down the implementation.
 type APIResponseBase struct {
-	Description string `json:"description,omitempty"`
-	ErrorCode   int    `json:"error_code,omitempty"`
-	Ok          bool   `json:"ok"`
+	Parameters  *ResponseParameters `json:"parameters,omitempty"`
+	Description string              `json:"description,omitempty"`
+	ErrorCode   int                 `json:"error_code,omitempty"`
+	Ok          bool                `json:"ok"`
 }
 
 // Base returns the APIResponseBase itself.
@@ -471,6 +645,32 @@ type Chat struct {
 	JoinToSendMessages                 bool             `json:"join_to_send_messages,omitempty"`
 	JoinByRequest                      bool             `json:"join_by_request,omitempty"`
 	HasRestrictedVoiceAndVideoMessages bool             `json:"has_restricted_voice_and_video_messages,omitempty"`
+
+	src *rawJSON
+}
+
+// MarshalJSON encodes the chat along with the fields not supported by echotron yet.
+func (c Chat) MarshalJSON() ([]byte, error) {
+	type chat Chat
+
+	b, err := json.Marshal(chat(c))
+	if err != nil {
+		return nil, err
+	}
+	return withUnknownFields(b, c.Unknown())
+}
+
+// Raw returns the JSON the chat has been decoded from as part of an Update, extracted from the one of the update,
+// or nil if it hasn't been decoded as part of an Update.
+// The chats decoded as part of an Update are equal only to the copies of themselves, since they refer to its JSON.
+func (c Chat) Raw() json.RawMessage {
+	return c.src.raw()
+}
+
+// Unknown returns the fields of the JSON returned by Raw that aren't supported by echotron yet,
+// the returned map must not be modified.
+func (c Chat) Unknown() map[string]json.RawMessage {
+	return c.src.unknownFields()
 }
 
 // Message represents a message.
@@ -545,6 +745,32 @@ type Message struct {
 	ChannelChatCreated            bool                           `json:"channel_chat_created,omitempty"`
 	HasProtectedContent           bool                           `json:"has_protected_content,omitempty"`
 	HasMediaSpoiler               bool                           `json:"has_media_spoiler,omitempty"`
+
+	src *rawJSON
+}
+
+// MarshalJSON encodes the message along with the fields not supported by echotron yet.
+func (m Message) MarshalJSON() ([]byte, error) {
+	type message Message
+
+	b, err := json.Marshal(message(m))
+	if err != nil {
+		return nil, err
+	}
+	return withUnknownFields(b, m.Unknown())
+}
+
+// Raw returns the JSON the message has been decoded from as part of an Update, extracted from the one of the update,
+// or nil if it hasn't been decoded as part of an Update.
+func (m Message) Raw() json.RawMessage {
+	return m.src.raw()
+}
+
+// Unknown returns the fields of the JSON returned by Raw that aren't supported by echotron yet,
+// eg: the ones of the kinds of content added by new versions of the Bot API.
+// The returned map must not be modified.
+func (m Message) Unknown() map[string]json.RawMessage {
+	return m.src.unknownFields()
 }
 
 // MessageID represents a unique message identifier.
@@ -0,0 +1,57 @@
+// Code generated by apigen; DO NOT EDIT.
+
+package echotron
+
+// ChatBoost contains information about a chat boost.
+type ChatBoost struct {
+	BoostID        string          `json:"boost_id"`
+	Source         ChatBoostSource `json:"source"`
+	AddDate        int64           `json:"add_date"`
+	ExpirationDate int64           `json:"expiration_date"`
+}
+
+// ChatBoostSource describes the source of a chat boost. It's a unique type for ChatBoostSourcePremium,
+// ChatBoostSourceGiftCode and ChatBoostSourceGiveaway.
+type ChatBoostSource struct {
+	User              *User  `json:"user,omitempty"`
+	Source            string `json:"source"`
+	GiveawayMessageID int    `json:"giveaway_message_id,omitempty"`
+	IsUnclaimed       bool   `json:"is_unclaimed,omitempty"`
+}
+
+// ReactionType describes the type of a reaction. It's a unique type for ReactionTypeEmoji and
+// ReactionTypeCustomEmoji.
+type ReactionType struct {
+	Type          string `json:"type"`
+	Emoji         string `json:"emoji,omitempty"`
+	CustomEmojiID string `json:"custom_emoji_id,omitempty"`
+}
+
+// UserChatBoosts represents a list of boosts added to a chat by a user.
+type UserChatBoosts struct {
+	Boosts []*ChatBoost `json:"boosts"`
+}
+
+// APIResponseMessageIDArray represents the incoming response from Telegram servers.
+// Used by all methods that return an array of MessageID objects on success.
+type APIResponseMessageIDArray struct {
+	Result []*MessageID `json:"result,omitempty"`
+	APIResponseBase
+}
+
+// Base returns the contained object of type APIResponseBase.
+func (a APIResponseMessageIDArray) Base() APIResponseBase {
+	return a.APIResponseBase
+}
+
+// APIResponseUserChatBoosts represents the incoming response from Telegram servers.
+// Used by all methods that return a UserChatBoosts object on success.
+type APIResponseUserChatBoosts struct {
+	Result *UserChatBoosts `json:"result,omitempty"`
+	APIResponseBase
+}
+
+// Base returns the contained object of type APIResponseBase.
+func (a APIResponseUserChatBoosts) Base() APIResponseBase {
+	return a.APIResponseBase
+}
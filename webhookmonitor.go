@@ -0,0 +1,319 @@
+/*
+ * Echotron
+ * Copyright (C) 2018-2022 The Echotron Devs
+ *
+ * Echotron is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Echotron is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package echotron
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// WebhookMonitorOptions contains the parameters used by the WebhookMonitor.
+// The callbacks are called from the goroutine of the monitor, so they should not block.
+type WebhookMonitorOptions struct {
+	// OnUnhealthy is called every time a check finds the webhook unhealthy.
+	// info is nil if the webhook status couldn't be retrieved at all.
+	OnUnhealthy func(info *WebhookInfo, reason string)
+	// OnHealthy is called when the webhook is found healthy after being reported as unhealthy.
+	OnHealthy func(info *WebhookInfo)
+	// OnFailover is called when the webhook gets deleted and the Dispatcher switches to long polling.
+	OnFailover func(reason string)
+	// OnRestore is called when the monitor has tried to set the webhook again after a failover,
+	// once the webhook has been checked at the following interval: err is nil if no error has been
+	// reported by Telegram since it has been set, otherwise the Dispatcher is switched back to long polling.
+	OnRestore func(err error)
+	// WebhookOptions are the options used to restore the webhook after a failover.
+	// If WebhookURL is empty, the options passed to ListenWebhookOptions are used instead.
+	WebhookOptions *WebhookOptions
+	// WebhookURL is the URL used to restore the webhook after a failover, in the same format accepted by
+	// ListenWebhookOptions. If empty, the one passed to ListenWebhookOptions is used instead.
+	WebhookURL string
+	// PollOptions are the options used for long polling while the webhook is down.
+	// If the Timeout is 0, a timeout of 30 seconds is used.
+	PollOptions UpdateOptions
+	// Interval is the time between two checks, defaults to one minute.
+	Interval time.Duration
+	// ErrorWindow is how long the LastErrorDate of the webhook makes it unhealthy, defaults to Interval.
+	ErrorWindow time.Duration
+	// RestoreInterval is how long the Dispatcher keeps polling before trying to restore the webhook,
+	// defaults to 10 minutes.
+	RestoreInterval time.Duration
+	// MaxPendingUpdates is the PendingUpdateCount above which the webhook is unhealthy, 0 disables the check.
+	MaxPendingUpdates int
+	// Failover enables deleting the webhook and switching the Dispatcher to long polling
+	// while the webhook is unhealthy.
+	Failover bool
+}
+
+// WebhookMonitor periodically calls GetWebhookInfo and reports when the webhook looks unhealthy.
+// When created through Dispatcher.MonitorWebhook with the Failover option, it also switches the
+// Dispatcher to long polling until the webhook is restored.
+type WebhookMonitor struct {
+	opts      WebhookMonitorOptions
+	api       API
+	dsp       *Dispatcher
+	stop      chan struct{}
+	pollStop  context.CancelFunc
+	pollDone  chan error
+	failedAt  time.Time
+	since     int64
+	unhealthy bool
+	restoring bool
+	once      sync.Once
+}
+
+// NewWebhookMonitor returns a new WebhookMonitor that checks the webhook of the bot with the given token.
+// The Failover option is ignored since there's no Dispatcher to switch to long polling.
+func NewWebhookMonitor(token string, opts WebhookMonitorOptions) *WebhookMonitor {
+	return newWebhookMonitor(NewAPI(token), nil, opts)
+}
+
+// MonitorWebhook returns a new WebhookMonitor that checks the webhook of the Dispatcher
+// and, if the Failover option is set, switches it to long polling while the webhook is unhealthy.
+func (d *Dispatcher) MonitorWebhook(opts WebhookMonitorOptions) *WebhookMonitor {
+	return newWebhookMonitor(d.getAPI(), d, opts)
+}
+
+func newWebhookMonitor(api API, dsp *Dispatcher, opts WebhookMonitorOptions) *WebhookMonitor {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Minute
+	}
+	if opts.ErrorWindow <= 0 {
+		opts.ErrorWindow = opts.Interval
+	}
+	if opts.RestoreInterval <= 0 {
+		opts.RestoreInterval = 10 * time.Minute
+	}
+	if opts.PollOptions.Timeout == 0 {
+		opts.PollOptions.Timeout = 30
+	}
+
+	m := &WebhookMonitor{
+		opts: opts,
+		api:  api,
+		dsp:  dsp,
+		stop: make(chan struct{}),
+	}
+	go m.run()
+	return m
+}
+
+// Stop stops the monitor.
+// If the Dispatcher is polling because of a failover, polling is stopped as well
+// and the webhook is not restored.
+func (m *WebhookMonitor) Stop() {
+	m.once.Do(func() { close(m.stop) })
+}
+
+func (m *WebhookMonitor) run() {
+	ticker := time.NewTicker(m.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		m.check()
+
+		select {
+		case <-m.stop:
+			m.stopPolling()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *WebhookMonitor) check() {
+	if m.isPolling() {
+		m.checkPolling()
+		return
+	}
+	if m.restoring {
+		m.checkRestored()
+		return
+	}
+
+	res, err := m.api.GetWebhookInfo()
+	if err != nil {
+		m.reportUnhealthy(nil, fmt.Sprintf("cannot get webhook info: %v", err))
+		return
+	}
+
+	if reason := m.diagnose(res.Result); reason != "" {
+		m.reportUnhealthy(res.Result, reason)
+		if m.dsp != nil && m.opts.Failover {
+			m.failover(reason)
+		}
+		return
+	}
+
+	if m.unhealthy {
+		m.unhealthy = false
+		if m.opts.OnHealthy != nil {
+			m.opts.OnHealthy(res.Result)
+		}
+	}
+}
+
+// diagnose returns the reason why the webhook is unhealthy or an empty string if it's healthy.
+func (m *WebhookMonitor) diagnose(info *WebhookInfo) string {
+	if info == nil || info.URL == "" {
+		return "webhook is not set"
+	}
+
+	if info.LastErrorDate > 0 && info.LastErrorDate >= m.since {
+		if errDate := time.Unix(info.LastErrorDate, 0); time.Since(errDate) <= m.opts.ErrorWindow {
+			return fmt.Sprintf("webhook error at %s: %s", errDate.Format(time.RFC3339), info.LastErrorMessage)
+		}
+	}
+
+	if limit := m.opts.MaxPendingUpdates; limit > 0 && info.PendingUpdateCount > limit {
+		return fmt.Sprintf("%d pending updates", info.PendingUpdateCount)
+	}
+
+	return ""
+}
+
+func (m *WebhookMonitor) reportUnhealthy(info *WebhookInfo, reason string) {
+	m.unhealthy = true
+	if m.opts.OnUnhealthy != nil {
+		m.opts.OnUnhealthy(info, reason)
+	}
+}
+
+func (m *WebhookMonitor) isPolling() bool {
+	return m.pollStop != nil
+}
+
+func (m *WebhookMonitor) log() Logger {
+	if m.dsp != nil {
+		return m.dsp.log()
+	}
+	return getLogger()
+}
+
+func (m *WebhookMonitor) failover(reason string) {
+	m.log().Log(LogWarn, "echotron.WebhookMonitor: switching to long polling", "reason", reason)
+	m.failedAt = time.Now()
+	m.startPolling()
+	if m.opts.OnFailover != nil {
+		m.opts.OnFailover(reason)
+	}
+}
+
+func (m *WebhookMonitor) checkPolling() {
+	select {
+	case err := <-m.pollDone:
+		m.pollStop, m.pollDone = nil, nil
+		m.log().Log(LogError, "echotron.WebhookMonitor: long polling stopped", "error", err)
+		m.reportUnhealthy(nil, fmt.Sprintf("long polling stopped: %v", err))
+		m.startPolling()
+		return
+	default:
+	}
+
+	if time.Since(m.failedAt) >= m.opts.RestoreInterval {
+		m.restore()
+	}
+}
+
+func (m *WebhookMonitor) startPolling() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	m.pollStop, m.pollDone = cancel, done
+
+	go func() {
+		// Pending updates are kept, since they're the ones the webhook failed to deliver.
+		done <- m.dsp.Run(ctx, &PollingSource{API: m.dsp.getAPI(), Options: m.opts.PollOptions})
+	}()
+}
+
+func (m *WebhookMonitor) stopPolling() {
+	if !m.isPolling() {
+		return
+	}
+
+	m.pollStop()
+	<-m.pollDone
+	m.pollStop, m.pollDone = nil, nil
+}
+
+// restore stops polling and sets the webhook again, which is checked by checkRestored at the next interval.
+func (m *WebhookMonitor) restore() {
+	m.stopPolling()
+
+	whURL, whOpts, err := m.webhook()
+	if err == nil {
+		_, err = m.api.SetWebhook(whURL, false, whOpts)
+	}
+	m.since = time.Now().Unix()
+
+	if err != nil {
+		m.restored(fmt.Errorf("cannot restore webhook: %w", err))
+		return
+	}
+	m.restoring = true
+}
+
+// checkRestored checks the webhook set by restore, switching back to long polling if Telegram
+// can't reach it, so that an unreachable webhook isn't kept until the next health check.
+func (m *WebhookMonitor) checkRestored() {
+	m.restoring = false
+
+	res, err := m.api.GetWebhookInfo()
+	switch {
+	case err != nil:
+		err = fmt.Errorf("cannot get webhook info: %w", err)
+	case res.Result == nil || res.Result.URL == "":
+		err = fmt.Errorf("webhook is not set")
+	case res.Result.LastErrorDate >= m.since:
+		err = fmt.Errorf("webhook unreachable: %s", res.Result.LastErrorMessage)
+	default:
+		m.log().Log(LogInfo, "echotron.WebhookMonitor: webhook restored", "url", res.Result.URL)
+	}
+	m.restored(err)
+}
+
+// restored reports the result of the restore, failing over again if err is not nil.
+func (m *WebhookMonitor) restored(err error) {
+	if m.opts.OnRestore != nil {
+		m.opts.OnRestore(err)
+	}
+	if err != nil {
+		m.failover(err.Error())
+	}
+}
+
+// webhook returns the URL and the options used to restore the webhook.
+func (m *WebhookMonitor) webhook() (string, *WebhookOptions, error) {
+	if m.opts.WebhookURL != "" {
+		u, err := url.Parse(m.opts.WebhookURL)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("%s%s", u.Hostname(), u.EscapedPath()), m.opts.WebhookOptions, nil
+	}
+
+	m.dsp.mu.Lock()
+	defer m.dsp.mu.Unlock()
+	if m.dsp.whURL == "" {
+		return "", nil, fmt.Errorf("no webhook URL to restore")
+	}
+	return m.dsp.whURL, m.dsp.whOpts, nil
+}
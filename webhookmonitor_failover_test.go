@@ -0,0 +1,181 @@
+package echotron_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NicoNex/echotron/v3"
+	"github.com/NicoNex/echotron/v3/echotrontest"
+)
+
+type botFunc func(*echotron.Update)
+
+func (f botFunc) Update(u *echotron.Update) {
+	f(u)
+}
+
+func receive(t *testing.T, updates <-chan *echotron.Update, text string) {
+	t.Helper()
+
+	select {
+	case u := <-updates:
+		if u.Message == nil || u.Message.Text != text {
+			t.Fatalf("expected the message %q, got %+v", text, u)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("the message %q hasn't been received", text)
+	}
+}
+
+// TestWebhookMonitorFailover drives a WebhookMonitor through a failover to long polling
+// and the restore of the webhook against the echotrontest server.
+func TestWebhookMonitorFailover(t *testing.T) {
+	var (
+		srv      = echotrontest.NewServer()
+		updates  = make(chan *echotron.Update, 4)
+		failover = make(chan string, 4)
+		restore  = make(chan error, 4)
+		dsp      = echotron.NewDispatcher(srv.Token, func(_ int64) echotron.Bot {
+			return botFunc(func(u *echotron.Update) { updates <- u })
+		})
+		bot = httptest.NewServer(http.HandlerFunc(dsp.HandleWebhook))
+	)
+	defer srv.Close()
+	defer bot.Close()
+
+	// The restored webhook is set without the port, as with ListenWebhook.
+	srv.WebhookPort = bot.URL[strings.LastIndex(bot.URL, ":")+1:]
+	dsp.SetAPI(srv.API())
+
+	// The webhook can't be reached, so the update stays pending.
+	if _, err := srv.API().SetWebhook("127.0.0.1:1/dead", false, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := srv.SendMessage(42, "first"); err == nil {
+		t.Fatal("the update shouldn't have been delivered to the webhook")
+	}
+
+	m := dsp.MonitorWebhook(echotron.WebhookMonitorOptions{
+		OnFailover:      func(reason string) { failover <- reason },
+		OnRestore:       func(err error) { restore <- err },
+		WebhookURL:      bot.URL + "/",
+		PollOptions:     echotron.UpdateOptions{Timeout: 1},
+		Interval:        10 * time.Millisecond,
+		ErrorWindow:     time.Minute,
+		RestoreInterval: 200 * time.Millisecond,
+		Failover:        true,
+	})
+	defer m.Stop()
+
+	select {
+	case reason := <-failover:
+		if !strings.Contains(reason, "webhook error") {
+			t.Fatalf("unexpected failover reason %q", reason)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("the monitor didn't fail over to long polling")
+	}
+
+	// The update the webhook failed to deliver is received through long polling.
+	receive(t, updates, "first")
+
+	select {
+	case err := <-restore:
+		if err != nil {
+			t.Fatalf("cannot restore the webhook: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("the monitor didn't restore the webhook")
+	}
+
+	info, err := srv.API().GetWebhookInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Result.URL != "127.0.0.1/" {
+		t.Fatalf("unexpected restored webhook URL %q", info.Result.URL)
+	}
+
+	// Once restored, the updates are delivered to the webhook again.
+	if _, err := srv.SendMessage(42, "second"); err != nil {
+		t.Fatal(err)
+	}
+	receive(t, updates, "second")
+
+	if len(failover) != 0 {
+		t.Fatalf("unexpected failover after the restore: %q", <-failover)
+	}
+}
+
+// TestWebhookMonitorRestoreUnreachable checks that the monitor goes back to long polling
+// when the restored webhook can't be reached.
+func TestWebhookMonitorRestoreUnreachable(t *testing.T) {
+	var (
+		srv      = echotrontest.NewServer()
+		updates  = make(chan *echotron.Update, 4)
+		failover = make(chan string, 4)
+		restore  = make(chan error, 4)
+		dsp      = echotron.NewDispatcher(srv.Token, func(_ int64) echotron.Bot {
+			return botFunc(func(u *echotron.Update) { updates <- u })
+		})
+	)
+	defer srv.Close()
+
+	dsp.SetAPI(srv.API())
+	if _, err := srv.API().SetWebhook("127.0.0.1:1/dead", false, nil); err != nil {
+		t.Fatal(err)
+	}
+	srv.SendMessage(42, "first")
+
+	m := dsp.MonitorWebhook(echotron.WebhookMonitorOptions{
+		OnFailover:      func(reason string) { failover <- reason },
+		OnRestore:       func(err error) { restore <- err },
+		WebhookURL:      "https://127.0.0.1:1/dead",
+		PollOptions:     echotron.UpdateOptions{Timeout: 1},
+		Interval:        200 * time.Millisecond,
+		ErrorWindow:     time.Minute,
+		RestoreInterval: 500 * time.Millisecond,
+		Failover:        true,
+	})
+	defer m.Stop()
+
+	select {
+	case <-failover:
+	case <-time.After(2 * time.Second):
+		t.Fatal("the monitor didn't fail over to long polling")
+	}
+	receive(t, updates, "first")
+
+	// Wait for the webhook to be set again and make Telegram fail to deliver an update to it.
+	for deadline := time.Now().Add(2 * time.Second); ; time.Sleep(time.Millisecond) {
+		info, err := srv.API().GetWebhookInfo()
+		if err == nil && info.Result.URL != "" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the monitor didn't set the webhook again")
+		}
+	}
+	if _, err := srv.SendMessage(42, "lost"); err == nil {
+		t.Fatal("the update shouldn't have been delivered to the webhook")
+	}
+
+	select {
+	case err := <-restore:
+		if err == nil || !strings.Contains(err.Error(), "unreachable") {
+			t.Fatalf("expected the restore to fail, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("the monitor didn't check the restored webhook")
+	}
+
+	select {
+	case <-failover:
+	case <-time.After(2 * time.Second):
+		t.Fatal("the monitor didn't switch back to long polling")
+	}
+	receive(t, updates, "lost")
+}
@@ -0,0 +1,39 @@
+package echotron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWebhookMonitorDiagnose(t *testing.T) {
+	m := &WebhookMonitor{
+		opts: WebhookMonitorOptions{
+			ErrorWindow:       time.Minute,
+			MaxPendingUpdates: 10,
+		},
+	}
+
+	tests := []struct {
+		info    *WebhookInfo
+		healthy bool
+	}{
+		{nil, false},
+		{&WebhookInfo{}, false},
+		{&WebhookInfo{URL: "example.com/test"}, true},
+		{&WebhookInfo{URL: "example.com/test", PendingUpdateCount: 10}, true},
+		{&WebhookInfo{URL: "example.com/test", PendingUpdateCount: 11}, false},
+		{&WebhookInfo{URL: "example.com/test", LastErrorDate: time.Now().Unix(), LastErrorMessage: "SSL error"}, false},
+		{&WebhookInfo{URL: "example.com/test", LastErrorDate: time.Now().Add(-time.Hour).Unix()}, true},
+	}
+
+	for i, tt := range tests {
+		if reason := m.diagnose(tt.info); (reason == "") != tt.healthy {
+			t.Fatalf("test #%d: unexpected diagnosis %q", i, reason)
+		}
+	}
+
+	m.since = time.Now().Add(time.Minute).Unix()
+	if reason := m.diagnose(&WebhookInfo{URL: "example.com/test", LastErrorDate: time.Now().Unix()}); reason != "" {
+		t.Fatalf("errors before the restore should be ignored, got %q", reason)
+	}
+}